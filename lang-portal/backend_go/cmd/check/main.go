@@ -0,0 +1,49 @@
+// Command check reports broken and orphaned rows in the database and, with
+// -repair, quarantines them in integrity_quarantine and rebuilds drifted
+// stats. It does not migrate, so it can fix a database a migration refuses.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+	"lang-portal/internal/service"
+)
+
+func main() {
+	dbPath := flag.String("db", database.DefaultConfig().DBPath, "SQLite DB path")
+	repair := flag.Bool("repair", false, "quarantine bad rows and rebuild drifted stats")
+	flag.Parse()
+
+	if err := database.Initialize(database.Config{DBPath: *dbPath}); err != nil {
+		log.Fatal("Failed to open database:", err)
+	}
+	defer database.Close()
+
+	integrity := service.NewIntegrityService(database.GetDB().Writer)
+	var report *models.IntegrityReport
+	var err error
+	if *repair {
+		report, err = integrity.Repair(context.Background())
+	} else {
+		report, err = integrity.Check(context.Background())
+	}
+	if err != nil {
+		log.Fatal("Failed to check database:", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatal("Failed to write report:", err)
+	}
+	if !report.OK {
+		database.Close()
+		os.Exit(1)
+	}
+}
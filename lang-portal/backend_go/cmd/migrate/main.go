@@ -0,0 +1,53 @@
+// Command migrate applies pending migrations, or with -check only lists the
+// rows that would stop them, e.g. duplicate group words or sessions of
+// deleted groups before 014_schema_hardening.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/database/migrations"
+)
+
+func main() {
+	dbPath := flag.String("db", database.DefaultConfig().DBPath, "SQLite DB path")
+	migrationsPath := flag.String("migrations", "internal/database/migrations", "migrations directory")
+	check := flag.Bool("check", false, "report rows that would block pending migrations without applying them")
+	flag.Parse()
+
+	if err := database.Initialize(database.Config{DBPath: *dbPath}); err != nil {
+		log.Fatal("Failed to open database:", err)
+	}
+	defer database.Close()
+
+	manager := migrations.NewManager(database.GetDB().Writer, *migrationsPath)
+	if *check {
+		violations, err := manager.Check()
+		if err != nil {
+			log.Fatal("Failed to check migrations:", err)
+		}
+		for _, v := range violations {
+			fmt.Println(v)
+		}
+		if len(violations) > 0 {
+			database.Close()
+			os.Exit(1)
+		}
+		log.Print("Pending migrations can be applied")
+		return
+	}
+
+	err := manager.Migrate()
+	var verr *migrations.ViolationError
+	if errors.As(err, &verr) {
+		log.Fatalf("%v\nFix these rows, or quarantine them with `go run ./cmd/check -repair`, then migrate again", err)
+	}
+	if err != nil {
+		log.Fatal("Failed to run migrations:", err)
+	}
+}
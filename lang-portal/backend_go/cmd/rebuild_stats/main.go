@@ -0,0 +1,35 @@
+// Command rebuild_stats recomputes the per-word review totals in word_stats
+// from word_review_items, e.g. after importing reviews directly into the database.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/database/migrations"
+	"lang-portal/internal/service"
+)
+
+func main() {
+	dbPath := flag.String("db", database.DefaultConfig().DBPath, "SQLite DB path")
+	migrationsPath := flag.String("migrations", "internal/database/migrations", "migrations directory")
+	flag.Parse()
+
+	if err := database.Initialize(database.Config{DBPath: *dbPath}); err != nil {
+		log.Fatal("Failed to open database:", err)
+	}
+	defer database.Close()
+
+	db := database.GetDB()
+	if err := migrations.NewManager(db.Writer, *migrationsPath).Migrate(); err != nil {
+		log.Fatal("Failed to run migrations:", err)
+	}
+
+	count, err := service.NewWordService(db.Writer).RebuildStats(context.Background())
+	if err != nil {
+		log.Fatal("Failed to rebuild word stats:", err)
+	}
+	log.Printf("Rebuilt stats for %d words", count)
+}
@@ -1,52 +1,58 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"errors"
+	"flag"
 	"log"
 
-	"github.com/gin-gonic/gin"
-	_ "github.com/mattn/go-sqlite3"
-
-	"lang-portal/internal/api/handlers"
+	"lang-portal/internal/api/server"
+	"lang-portal/internal/database"
+	"lang-portal/internal/database/migrations"
+	"lang-portal/internal/database/seeds"
 	"lang-portal/internal/service"
 )
 
-func setupRouter() *gin.Engine {
-	r := gin.Default()
+// newServer builds the API server over the given services
+func newServer(port int, services *service.Services) *server.Server {
+	return server.NewServer(server.Config{Port: port}, services)
+}
 
-	// Initialize database
-	db, err := sql.Open("sqlite3", "./data/lang_portal.db")
-	if err != nil {
+func main() {
+	port := flag.Int("port", 8080, "server port")
+	dbPath := flag.String("db", database.DefaultConfig().DBPath, "SQLite DB path")
+	migrationsPath := flag.String("migrations", "internal/database/migrations", "migrations directory")
+	seedsPath := flag.String("seeds", "internal/database/seeds", "seeds directory")
+	seed := flag.Bool("seed", false, "seed initial groups and words")
+	flag.Parse()
+
+	// Initialize database (WAL, single writer and a reader pool)
+	cfg := database.DefaultConfig()
+	cfg.DBPath = *dbPath
+	if err := database.Initialize(cfg); err != nil {
 		log.Fatal("Failed to open database:", err)
 	}
-
-	// Initialize services with database connection
-	studyService := service.NewStudyService(db)
-	groupService := service.NewGroupService(db)
-	wordService := service.NewWordService(db)
-
-	// Initialize handlers
-	studyHandler := handlers.NewStudyHandler(studyService)
-	groupHandler := handlers.NewGroupHandler(groupService)
-	wordHandler := handlers.NewWordHandler(wordService)
-
-	// Setup route groups
-	api := r.Group("/api")
-	{
-		// Register routes using handler methods
-		studyHandler.RegisterRoutes(api)
-		groupHandler.RegisterRoutes(api)
-		wordHandler.RegisterRoutes(api)
+	defer database.Close()
+	db := database.GetDB()
+
+	// Apply pending migrations so the server never runs on an old schema
+	err := migrations.NewManager(db.Writer, *migrationsPath).Migrate()
+	var verr *migrations.ViolationError
+	if errors.As(err, &verr) {
+		log.Fatalf("%v\nFix these rows, or quarantine them with `go run ./cmd/check -repair`, then start again", err)
+	}
+	if err != nil {
+		log.Fatal("Failed to run migrations:", err)
 	}
 
-	return r
-}
-
-func main() {
-	r := setupRouter()
+	if *seed {
+		if err := seeds.NewSeeder(db.Writer, *seedsPath).Seed(context.Background()); err != nil {
+			log.Fatal("Failed to seed database:", err)
+		}
+	}
 
 	// Start server
-	if err := r.Run(":8080"); err != nil {
+	if err := newServer(*port, service.NewServices(db)).Start(); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
 }
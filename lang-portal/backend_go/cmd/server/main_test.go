@@ -0,0 +1,128 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/database/migrations"
+	"lang-portal/internal/service"
+)
+
+// testServer returns the server main runs, over a migrated in-memory database
+func testServer(t *testing.T) (http.Handler, *database.DB) {
+	t.Helper()
+	db, err := database.Open(database.Config{DBPath: ":memory:"})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := migrations.NewManager(db.Writer, "../../internal/database/migrations").Migrate(); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return newServer(8080, service.NewServices(db)), db
+}
+
+func serve(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestIntegrityRoutes(t *testing.T) {
+	h, _ := testServer(t)
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodGet, "/api/admin/integrity"},
+		{http.MethodPost, "/api/admin/integrity/repair"},
+	} {
+		w := serve(t, h, tc.method, tc.path, "")
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"ok":true`) {
+			t.Errorf("%s %s = %d %s, want 200 with a clean report", tc.method, tc.path, w.Code, w.Body)
+		}
+	}
+}
+
+func TestTimezoneParameter(t *testing.T) {
+	h, db := testServer(t)
+	for _, stmt := range []string{
+		"INSERT INTO groups (id, name) VALUES (1, 'Numbers')",
+		"INSERT INTO study_sessions (id, group_id, study_activity_id, created_at) VALUES (1, 1, 1, '2025-03-10 09:30:15')",
+	} {
+		if _, err := db.Writer.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		query string
+		code  int
+		want  string
+	}{
+		{"", http.StatusOK, `"created_at":"2025-03-10T09:30:15Z"`},
+		{"?tz=Asia/Shanghai", http.StatusOK, `"created_at":"2025-03-10T17:30:15+08:00"`},
+		{"?tz=Nowhere/Land", http.StatusBadRequest, "unknown tz"},
+	} {
+		w := serve(t, h, http.MethodGet, "/api/study_sessions/1"+tc.query, "")
+		if w.Code != tc.code || !strings.Contains(w.Body.String(), tc.want) {
+			t.Errorf("GET /api/study_sessions/1%s = %d %s, want %d with %s", tc.query, w.Code, w.Body, tc.code, tc.want)
+		}
+	}
+}
+
+func TestQuizCount(t *testing.T) {
+	h, db := testServer(t)
+	for _, stmt := range []string{
+		`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 60)
+		 INSERT INTO words (id, chinese, english) SELECT i, 'w' || i, 'word ' || i FROM n`,
+		"INSERT INTO groups (id, name) VALUES (1, 'Many')",
+		"INSERT INTO words_groups (word_id, group_id) SELECT id, 1 FROM words",
+		"INSERT INTO study_sessions (id, group_id, study_activity_id) VALUES (1, 1, 1)",
+	} {
+		if _, err := db.Writer.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := serve(t, h, http.MethodGet, "/api/study_sessions/1/questions?count=500", "")
+	if got := strings.Count(w.Body.String(), `"prompt"`); w.Code != http.StatusOK || got != 50 {
+		t.Errorf("count=500 gave %d %d questions, want 200 with 50", w.Code, got)
+	}
+	for _, count := range []string{"ten", "0"} {
+		if w := serve(t, h, http.MethodGet, "/api/study_sessions/1/questions?count="+count, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("count=%s = %d, want 400", count, w.Code)
+		}
+	}
+}
+
+func TestReviewMissingReferences(t *testing.T) {
+	h, db := testServer(t)
+	for _, stmt := range []string{
+		"INSERT INTO groups (id, name) VALUES (1, 'Numbers')",
+		"INSERT INTO words (id, chinese, english) VALUES (1, '一', 'one')",
+		"INSERT INTO study_sessions (id, group_id, study_activity_id) VALUES (1, 1, 1)",
+	} {
+		if _, err := db.Writer.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		path string
+		code int
+		want string
+	}{
+		{"/api/study_sessions/9/words/1/review", http.StatusNotFound, "study session not found"},
+		{"/api/study_sessions/1/words/9/review", http.StatusNotFound, "word not found"},
+		{"/api/study_sessions/1/words/1/review", http.StatusOK, `"correct":true`},
+	} {
+		w := serve(t, h, http.MethodPost, tc.path, `{"correct":true}`)
+		if w.Code != tc.code || !strings.Contains(w.Body.String(), tc.want) {
+			t.Errorf("POST %s = %d %s, want %d with %s", tc.path, w.Code, w.Body, tc.code, tc.want)
+		}
+	}
+}
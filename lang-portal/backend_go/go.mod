@@ -6,7 +6,6 @@ require (
 	github.com/gin-contrib/cors v1.7.6
 	github.com/gin-gonic/gin v1.10.1
 	github.com/magefile/mage v1.15.0
-	github.com/mattn/go-sqlite3 v1.14.28
 	modernc.org/sqlite v1.38.2
 )
 
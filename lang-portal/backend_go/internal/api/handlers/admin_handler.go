@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/api/response"
+	"lang-portal/internal/service"
+)
+
+// AdminHandler handles database maintenance routes
+type AdminHandler struct {
+	integrityService *service.IntegrityService
+}
+
+// NewAdminHandler creates a new AdminHandler
+func NewAdminHandler(integrityService *service.IntegrityService) *AdminHandler {
+	return &AdminHandler{integrityService: integrityService}
+}
+
+// RegisterRoutes registers admin routes
+func (h *AdminHandler) RegisterRoutes(r *gin.RouterGroup) {
+	admin := r.Group("/admin")
+	{
+		admin.GET("/integrity", h.CheckIntegrity)
+		admin.POST("/integrity/repair", h.RepairIntegrity)
+	}
+}
+
+// CheckIntegrity handles GET /api/admin/integrity
+func (h *AdminHandler) CheckIntegrity(c *gin.Context) {
+	report, err := h.integrityService.Check(c.Request.Context())
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+	response.Success(c, report)
+}
+
+// RepairIntegrity handles POST /api/admin/integrity/repair, which quarantines
+// bad rows and rebuilds drifted stats, then reports what is left
+func (h *AdminHandler) RepairIntegrity(c *gin.Context) {
+	report, err := h.integrityService.Repair(c.Request.Context())
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+	response.Success(c, report)
+}
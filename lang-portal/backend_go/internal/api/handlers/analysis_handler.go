@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/api/response"
+	"lang-portal/internal/service"
+)
+
+// AnalysisHandler handles text analysis routes
+type AnalysisHandler struct {
+	analysisService *service.AnalysisService
+}
+
+// NewAnalysisHandler creates a new AnalysisHandler
+func NewAnalysisHandler(analysisService *service.AnalysisService) *AnalysisHandler {
+	return &AnalysisHandler{analysisService: analysisService}
+}
+
+// RegisterRoutes registers analysis-related routes
+func (h *AnalysisHandler) RegisterRoutes(r *gin.RouterGroup) {
+	analysis := r.Group("/analysis")
+	{
+		analysis.POST("/coverage", h.AnalyzeCoverage)
+	}
+}
+
+// AnalyzeCoverage handles POST /api/analysis/coverage
+func (h *AnalysisHandler) AnalyzeCoverage(c *gin.Context) {
+	var req struct {
+		Text string `json:"text" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	report, err := h.analysisService.AnalyzeCoverage(c.Request.Context(), req.Text)
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, report)
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/api/response"
+	"lang-portal/internal/service"
+)
+
+// defaultConfusionGroupName names groups built from confused pairs
+const defaultConfusionGroupName = "Confusable pairs"
+
+// AnalyticsHandler handles review analytics routes
+type AnalyticsHandler struct {
+	analyticsService *service.AnalyticsService
+	groupService     *service.GroupService
+}
+
+// NewAnalyticsHandler creates a new AnalyticsHandler
+func NewAnalyticsHandler(analyticsService *service.AnalyticsService, groupService *service.GroupService) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsService: analyticsService, groupService: groupService}
+}
+
+// RegisterRoutes registers analytics routes
+func (h *AnalyticsHandler) RegisterRoutes(r *gin.RouterGroup) {
+	analytics := r.Group("/analytics")
+	{
+		analytics.GET("/confusions", h.GetConfusions)
+		analytics.POST("/confusions/group", h.CreateConfusionGroup)
+		analytics.GET("/reviews", h.GetReviewSeries)
+		analytics.GET("/heatmap", h.GetHeatmap)
+		analytics.GET("/forecast", h.GetForecast)
+	}
+}
+
+// GetConfusions handles GET /api/analytics/confusions?group_id=&limit=
+func (h *AnalyticsHandler) GetConfusions(c *gin.Context) {
+	groupID, err := strconv.ParseInt(c.DefaultQuery("group_id", "0"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid group ID"))
+		return
+	}
+	limit := confusionLimit(c.DefaultQuery("limit", strconv.Itoa(service.DefaultConfusionLimit)))
+
+	groups, err := h.analyticsService.GetConfusions(c.Request.Context(), groupID, limit)
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, groups)
+}
+
+// CreateConfusionGroup handles POST /api/analytics/confusions/group
+func (h *AnalyticsHandler) CreateConfusionGroup(c *gin.Context) {
+	var req struct {
+		Name    string `json:"name"`
+		GroupID int64  `json:"group_id"`
+		Limit   int    `json:"limit"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	if req.Name == "" {
+		req.Name = defaultConfusionGroupName
+	}
+
+	wordIDs, err := h.analyticsService.ConfusableWordIDs(c.Request.Context(), req.GroupID, confusionLimit(strconv.Itoa(req.Limit)))
+	if err != nil {
+		if errors.Is(err, service.ErrNoConfusions) {
+			response.BadRequest(c, err)
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	group, err := h.groupService.CreateGroup(c.Request.Context(), req.Name, wordIDs)
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, group)
+}
+
+// GetReviewSeries handles GET /api/analytics/reviews?bucket=&from=&to=&group_id=&activity_id=
+func (h *AnalyticsHandler) GetReviewSeries(c *gin.Context) {
+	groupID, err := strconv.ParseInt(c.DefaultQuery("group_id", "0"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid group ID"))
+		return
+	}
+	activityID, err := strconv.ParseInt(c.DefaultQuery("activity_id", "0"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid activity ID"))
+		return
+	}
+
+	series, err := h.analyticsService.GetReviewSeries(c.Request.Context(), service.ReviewSeriesOptions{
+		Bucket:     c.Query("bucket"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+		GroupID:    groupID,
+		ActivityID: activityID,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRange) {
+			response.BadRequest(c, err)
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, series)
+}
+
+// GetHeatmap handles GET /api/analytics/heatmap?year=
+func (h *AnalyticsHandler) GetHeatmap(c *gin.Context) {
+	year, err := strconv.Atoi(c.DefaultQuery("year", "0"))
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid year"))
+		return
+	}
+
+	heatmap, err := h.analyticsService.GetHeatmap(c.Request.Context(), year)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRange) {
+			response.BadRequest(c, err)
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, heatmap)
+}
+
+// GetForecast handles GET /api/analytics/forecast?days=&new_per_day=
+func (h *AnalyticsHandler) GetForecast(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(service.DefaultForecastDays)))
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid days"))
+		return
+	}
+	newPerDay, err := strconv.Atoi(c.DefaultQuery("new_per_day", "0"))
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid new_per_day"))
+		return
+	}
+
+	forecast, err := h.analyticsService.GetForecast(c.Request.Context(), days, newPerDay)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRange) {
+			response.BadRequest(c, err)
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, forecast)
+}
+
+// confusionLimit parses a pair limit, falling back to the default when out of range
+func confusionLimit(s string) int {
+	limit, _ := strconv.Atoi(s)
+	if limit < 1 || limit > service.MaxConfusionLimit {
+		return service.DefaultConfusionLimit
+	}
+	return limit
+}
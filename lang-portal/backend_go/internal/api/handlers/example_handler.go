@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/api/response"
+	"lang-portal/internal/service"
+)
+
+// ExampleHandler handles word example and cloze exercise routes
+type ExampleHandler struct {
+	exampleService *service.ExampleService
+}
+
+// NewExampleHandler creates a new ExampleHandler
+func NewExampleHandler(exampleService *service.ExampleService) *ExampleHandler {
+	return &ExampleHandler{exampleService: exampleService}
+}
+
+// exampleRequest is the body for creating or updating an example sentence
+type exampleRequest struct {
+	Sentence    string `json:"sentence" binding:"required"`
+	Translation string `json:"translation" binding:"required"`
+	Source      string `json:"source"`
+}
+
+// RegisterRoutes registers example-related routes
+func (h *ExampleHandler) RegisterRoutes(r *gin.RouterGroup) {
+	examples := r.Group("/words/:id/examples")
+	{
+		examples.GET("", h.GetExamples)
+		examples.POST("", h.CreateExample)
+		examples.PUT("/:example_id", h.UpdateExample)
+		examples.DELETE("/:example_id", h.DeleteExample)
+	}
+
+	r.GET("/study_sessions/:id/cloze", h.GetCloze)
+	r.POST("/study_sessions/:id/cloze", h.AnswerCloze)
+}
+
+// GetExamples handles GET /api/words/:id/examples
+func (h *ExampleHandler) GetExamples(c *gin.Context) {
+	wordID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid word ID"))
+		return
+	}
+
+	examples, err := h.exampleService.GetExamples(c.Request.Context(), wordID)
+	if err != nil {
+		handleExampleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"items": examples})
+}
+
+// CreateExample handles POST /api/words/:id/examples
+func (h *ExampleHandler) CreateExample(c *gin.Context) {
+	wordID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid word ID"))
+		return
+	}
+
+	var req exampleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	example, err := h.exampleService.CreateExample(c.Request.Context(), wordID, req.Sentence, req.Translation, req.Source)
+	if err != nil {
+		handleExampleError(c, err)
+		return
+	}
+
+	response.Success(c, example)
+}
+
+// UpdateExample handles PUT /api/words/:id/examples/:example_id
+func (h *ExampleHandler) UpdateExample(c *gin.Context) {
+	wordID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid word ID"))
+		return
+	}
+	exampleID, err := strconv.ParseInt(c.Param("example_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid example ID"))
+		return
+	}
+
+	var req exampleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	example, err := h.exampleService.UpdateExample(c.Request.Context(), wordID, exampleID, req.Sentence, req.Translation, req.Source)
+	if err != nil {
+		handleExampleError(c, err)
+		return
+	}
+
+	response.Success(c, example)
+}
+
+// DeleteExample handles DELETE /api/words/:id/examples/:example_id
+func (h *ExampleHandler) DeleteExample(c *gin.Context) {
+	wordID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid word ID"))
+		return
+	}
+	exampleID, err := strconv.ParseInt(c.Param("example_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid example ID"))
+		return
+	}
+
+	if err := h.exampleService.DeleteExample(c.Request.Context(), wordID, exampleID); err != nil {
+		handleExampleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"success": true})
+}
+
+// GetCloze handles GET /api/study_sessions/:id/cloze
+func (h *ExampleHandler) GetCloze(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid session ID"))
+		return
+	}
+
+	item, err := h.exampleService.GetCloze(c.Request.Context(), sessionID)
+	if err != nil {
+		handleExampleError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// AnswerCloze handles POST /api/study_sessions/:id/cloze
+func (h *ExampleHandler) AnswerCloze(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid session ID"))
+		return
+	}
+
+	var req struct {
+		ExampleID int64  `json:"example_id" binding:"required"`
+		Answer    string `json:"answer"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	result, err := h.exampleService.AnswerCloze(c.Request.Context(), sessionID, req.ExampleID, req.Answer)
+	if err != nil {
+		handleExampleError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// handleExampleError maps example service errors to responses
+func handleExampleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrWordNotFound),
+		errors.Is(err, service.ErrExampleNotFound),
+		errors.Is(err, service.ErrSessionNotFound):
+		response.NotFound(c, err)
+	case errors.Is(err, service.ErrExampleMissingWord):
+		response.BadRequest(c, err)
+	default:
+		response.InternalError(c, err)
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/api/response"
+	"lang-portal/internal/models"
+	"lang-portal/internal/service"
+)
+
+// GoalHandler handles daily goal routes
+type GoalHandler struct {
+	goalService *service.GoalService
+}
+
+// NewGoalHandler creates a new GoalHandler
+func NewGoalHandler(goalService *service.GoalService) *GoalHandler {
+	return &GoalHandler{goalService: goalService}
+}
+
+// RegisterRoutes registers goal-related routes
+func (h *GoalHandler) RegisterRoutes(r *gin.RouterGroup) {
+	goals := r.Group("/goals")
+	{
+		goals.PUT("", h.UpdateGoals)
+		goals.GET("/today", h.GetToday)
+	}
+}
+
+// UpdateGoals handles PUT /api/goals
+func (h *GoalHandler) UpdateGoals(c *gin.Context) {
+	var req models.DailyGoals
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	progress, err := h.goalService.UpdateGoals(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidGoals) {
+			response.BadRequest(c, err)
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, progress)
+}
+
+// GetToday handles GET /api/goals/today
+func (h *GoalHandler) GetToday(c *gin.Context) {
+	progress, err := h.goalService.GetToday(c.Request.Context())
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+	response.Success(c, progress)
+}
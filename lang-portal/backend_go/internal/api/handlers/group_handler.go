@@ -26,13 +26,15 @@ func (h *GroupHandler) RegisterRoutes(r *gin.RouterGroup) {
 	groups := r.Group("/groups")
 	{
 		groups.GET("", h.GetGroups)
+		groups.POST("", h.CreateGroup)
 		groups.GET("/:id", h.GetGroup)
+		groups.GET("/:id/stats", h.GetGroupStats)
 		groups.GET("/:id/words", h.GetGroupWords)
 		groups.GET("/:id/study_sessions", h.GetGroupStudySessions)
 	}
 }
 
-// GetGroups handles GET /api/groups
+// GetGroups handles GET /api/groups?scope=group|all
 func (h *GroupHandler) GetGroups(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
@@ -44,8 +46,12 @@ func (h *GroupHandler) GetGroups(c *gin.Context) {
 		perPage = 100
 	}
 
-	groups, err := h.groupService.GetGroups(page, perPage)
+	groups, err := h.groupService.GetGroups(c.Request.Context(), page, perPage, c.Query("scope"))
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatsScope) {
+			response.BadRequest(c, err)
+			return
+		}
 		response.InternalError(c, err)
 		return
 	}
@@ -53,6 +59,55 @@ func (h *GroupHandler) GetGroups(c *gin.Context) {
 	response.Success(c, groups)
 }
 
+// CreateGroup handles POST /api/groups
+func (h *GroupHandler) CreateGroup(c *gin.Context) {
+	var req struct {
+		Name    string  `json:"name" binding:"required"`
+		WordIDs []int64 `json:"word_ids"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	group, err := h.groupService.CreateGroup(c.Request.Context(), req.Name, req.WordIDs)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownWords) {
+			response.BadRequest(c, err)
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, group)
+}
+
+// GetGroupStats handles GET /api/groups/:id/stats?scope=group|all
+func (h *GroupHandler) GetGroupStats(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid group ID"))
+		return
+	}
+
+	stats, err := h.groupService.GetGroupStats(c.Request.Context(), id, c.Query("scope"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrGroupNotFound):
+			response.NotFound(c, err)
+		case errors.Is(err, service.ErrInvalidStatsScope):
+			response.BadRequest(c, err)
+		default:
+			response.InternalError(c, err)
+		}
+		return
+	}
+
+	response.Success(c, stats)
+}
+
 // GetGroup handles GET /api/groups/:id
 func (h *GroupHandler) GetGroup(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -61,7 +116,7 @@ func (h *GroupHandler) GetGroup(c *gin.Context) {
 		return
 	}
 
-	group, err := h.groupService.GetGroupByID(id)
+	group, err := h.groupService.GetGroupByID(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			response.NotFound(c, errors.New("group not found"))
@@ -85,7 +140,7 @@ func (h *GroupHandler) GetGroupWords(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
 
-	words, err := h.groupService.GetGroupWordsPaginated(id, page, perPage)
+	words, err := h.groupService.GetGroupWordsPaginated(c.Request.Context(), id, page, perPage)
 	if err != nil {
 		response.InternalError(c, err)
 		return
@@ -104,7 +159,7 @@ func (h *GroupHandler) GetGroupStudySessions(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
 
-	sessions, err := h.groupService.GetGroupStudySessions(id, page, perPage)
+	sessions, err := h.groupService.GetGroupStudySessions(c.Request.Context(), id, page, perPage)
 	if err != nil {
 		response.InternalError(c, err)
 		return
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/api/response"
+	"lang-portal/internal/service"
+)
+
+// QueueHandler handles the adaptive study session queue
+type QueueHandler struct {
+	queueService *service.QueueService
+}
+
+// NewQueueHandler creates a new QueueHandler
+func NewQueueHandler(queueService *service.QueueService) *QueueHandler {
+	return &QueueHandler{queueService: queueService}
+}
+
+// RegisterRoutes registers queue-related routes
+func (h *QueueHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/study_sessions/:id/next", h.GetNextWord)
+}
+
+// GetNextWord handles GET /api/study_sessions/:id/next?strategy=&new_ratio=&no_repeat=
+func (h *QueueHandler) GetNextWord(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid session ID"))
+		return
+	}
+
+	opts := service.QueueOptions{Strategy: c.Query("strategy")}
+	if v, ok := c.GetQuery("new_ratio"); ok {
+		ratio, err := strconv.ParseFloat(v, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			response.BadRequest(c, errors.New("new_ratio must be between 0 and 1"))
+			return
+		}
+		opts.NewRatio = &ratio
+	}
+	if v, ok := c.GetQuery("no_repeat"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			response.BadRequest(c, errors.New("no_repeat must be a non-negative integer"))
+			return
+		}
+		opts.NoRepeat = &n
+	}
+
+	item, err := h.queueService.Next(c.Request.Context(), sessionID, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSessionNotFound), errors.Is(err, service.ErrQueueEmpty):
+			response.NotFound(c, err)
+		case errors.Is(err, service.ErrUnknownStrategy):
+			response.BadRequest(c, err)
+		default:
+			response.InternalError(c, err)
+		}
+		return
+	}
+
+	response.Success(c, item)
+}
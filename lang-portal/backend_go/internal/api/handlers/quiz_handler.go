@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/api/response"
+	"lang-portal/internal/models"
+	"lang-portal/internal/service"
+)
+
+// QuizHandler handles multiple-choice question routes
+type QuizHandler struct {
+	quizService *service.QuizService
+}
+
+// NewQuizHandler creates a new QuizHandler
+func NewQuizHandler(quizService *service.QuizService) *QuizHandler {
+	return &QuizHandler{quizService: quizService}
+}
+
+// RegisterRoutes registers quiz-related routes
+func (h *QuizHandler) RegisterRoutes(r *gin.RouterGroup) {
+	questions := r.Group("/study_sessions/:id/questions")
+	{
+		questions.GET("", h.GetQuestions)
+		questions.POST("/:question_id/answer", h.AnswerQuestion)
+	}
+}
+
+// GetQuestions handles GET /api/study_sessions/:id/questions?count=&direction=.
+// A count above MaxQuizCount is capped.
+func (h *QuizHandler) GetQuestions(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid session ID"))
+		return
+	}
+
+	count, err := strconv.Atoi(c.DefaultQuery("count", strconv.Itoa(service.DefaultQuizCount)))
+	if err != nil || count < 1 {
+		response.BadRequest(c, errors.New("count must be a positive number"))
+		return
+	}
+	count = min(count, service.MaxQuizCount)
+
+	direction := c.DefaultQuery("direction", models.DirectionChineseToEnglish)
+	if direction != models.DirectionChineseToEnglish && direction != models.DirectionEnglishToChinese {
+		response.BadRequest(c, errors.New("direction must be zh_en or en_zh"))
+		return
+	}
+
+	questions, err := h.quizService.GenerateQuestions(c.Request.Context(), sessionID, count, direction)
+	if err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			response.NotFound(c, err)
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"items": questions})
+}
+
+// AnswerQuestion handles POST /api/study_sessions/:id/questions/:question_id/answer
+func (h *QuizHandler) AnswerQuestion(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid session ID"))
+		return
+	}
+	questionID, err := strconv.ParseInt(c.Param("question_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid question ID"))
+		return
+	}
+
+	var req struct {
+		Choice *int `json:"choice"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	if req.Choice == nil {
+		response.BadRequest(c, errors.New("choice is required"))
+		return
+	}
+
+	result, err := h.quizService.AnswerQuestion(c.Request.Context(), sessionID, questionID, *req.Choice)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrQuestionNotFound):
+			response.NotFound(c, err)
+		case errors.Is(err, service.ErrQuestionAnswered), errors.Is(err, service.ErrInvalidChoice):
+			response.BadRequest(c, err)
+		default:
+			response.InternalError(c, err)
+		}
+		return
+	}
+
+	response.Success(c, result)
+}
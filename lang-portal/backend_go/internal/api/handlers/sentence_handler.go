@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/api/response"
+	"lang-portal/internal/models"
+	"lang-portal/internal/service"
+)
+
+// SentenceHandler handles sentence translation exercise routes
+type SentenceHandler struct {
+	sentenceService *service.SentenceService
+}
+
+// NewSentenceHandler creates a new SentenceHandler
+func NewSentenceHandler(sentenceService *service.SentenceService) *SentenceHandler {
+	return &SentenceHandler{sentenceService: sentenceService}
+}
+
+// RegisterRoutes registers sentence-related routes
+func (h *SentenceHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/groups/:id/sentence", h.GetGroupSentence)
+
+	sentences := r.Group("/sentences")
+	{
+		sentences.POST("", h.CreateSentence)
+		sentences.GET("/:id/hints", h.GetSentenceHint)
+		sentences.POST("/:id/grade", h.GradeSentence)
+	}
+}
+
+// GetGroupSentence handles GET /api/groups/:id/sentence
+func (h *SentenceHandler) GetGroupSentence(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid group ID"))
+		return
+	}
+
+	sentence, err := h.sentenceService.GetRandomSentence(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrSentenceNotFound) {
+			response.NotFound(c, err)
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, sentence)
+}
+
+// CreateSentence handles POST /api/sentences
+func (h *SentenceHandler) CreateSentence(c *gin.Context) {
+	var req struct {
+		GroupID int64    `json:"group_id" binding:"required"`
+		Chinese string   `json:"chinese" binding:"required"`
+		English string   `json:"english" binding:"required"`
+		Roles   []string `json:"roles"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	sentence, err := h.sentenceService.CreateSentence(c.Request.Context(), req.GroupID, req.Chinese, req.English, req.Roles)
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, sentence)
+}
+
+// GetSentenceHint handles GET /api/sentences/:id/hints?level=1..3&study_session_id=
+func (h *SentenceHandler) GetSentenceHint(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid sentence ID"))
+		return
+	}
+
+	sessionID, err := strconv.ParseInt(c.Query("study_session_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("study_session_id is required"))
+		return
+	}
+
+	level, err := strconv.Atoi(c.DefaultQuery("level", "1"))
+	if err != nil || level < models.HintLevelPattern || level > models.HintLevelPartial {
+		response.BadRequest(c, errors.New("level must be between 1 and 3"))
+		return
+	}
+
+	hint, err := h.sentenceService.GetHint(c.Request.Context(), id, sessionID, level)
+	if err != nil {
+		if errors.Is(err, service.ErrSentenceNotFound) || errors.Is(err, service.ErrSessionNotFound) {
+			response.NotFound(c, err)
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, hint)
+}
+
+// GradeSentence handles POST /api/sentences/:id/grade
+func (h *SentenceHandler) GradeSentence(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid sentence ID"))
+		return
+	}
+
+	var req struct {
+		StudySessionID int64  `json:"study_session_id" binding:"required"`
+		Answer         string `json:"answer"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	grade, err := h.sentenceService.GradeSentence(c.Request.Context(), id, req.StudySessionID, req.Answer)
+	if err != nil {
+		if errors.Is(err, service.ErrSentenceNotFound) || errors.Is(err, service.ErrSessionNotFound) {
+			response.NotFound(c, err)
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, grade)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/api/response"
+	"lang-portal/internal/service"
+)
+
+// SettingsHandler handles learner settings routes
+type SettingsHandler struct {
+	settingsService *service.SettingsService
+}
+
+// NewSettingsHandler creates a new SettingsHandler
+func NewSettingsHandler(settingsService *service.SettingsService) *SettingsHandler {
+	return &SettingsHandler{settingsService: settingsService}
+}
+
+// RegisterRoutes registers settings-related routes
+func (h *SettingsHandler) RegisterRoutes(r *gin.RouterGroup) {
+	settings := r.Group("/settings")
+	{
+		settings.GET("", h.GetSettings)
+		settings.PUT("", h.UpdateSettings)
+		settings.POST("/rebuild_mastery", h.RebuildMastery)
+	}
+}
+
+// GetSettings handles GET /api/settings
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	settings, err := h.settingsService.GetSettings(c.Request.Context())
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+	response.Success(c, settings)
+}
+
+// UpdateSettings handles PUT /api/settings with a partial settings object
+func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil || len(body) == 0 {
+		response.BadRequest(c, errors.New("request body is required"))
+		return
+	}
+
+	settings, err := h.settingsService.UpdateSettings(c.Request.Context(), body)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSettings) {
+			response.BadRequest(c, err)
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// RebuildMastery handles POST /api/settings/rebuild_mastery
+func (h *SettingsHandler) RebuildMastery(c *gin.Context) {
+	breakdown, err := h.settingsService.RebuildMastery(c.Request.Context())
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+	response.Success(c, breakdown)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"lang-portal/internal/api/response"
+	"lang-portal/internal/models"
 	"lang-portal/internal/service"
 )
 
@@ -28,6 +29,7 @@ func (h *StudyHandler) RegisterRoutes(r *gin.RouterGroup) {
 		dashboard.GET("/last_study_session", h.GetLastStudySession)
 		dashboard.GET("/study_progress", h.GetStudyProgress)
 		dashboard.GET("/quick_stats", h.GetQuickStats)
+		dashboard.GET("/streak", h.GetStreak)
 	}
 
 	studyActivities := r.Group("/study_activities")
@@ -43,15 +45,20 @@ func (h *StudyHandler) RegisterRoutes(r *gin.RouterGroup) {
 		studySessions.GET("/:id", h.GetStudySession)
 		studySessions.GET("/:id/words", h.GetStudySessionWords)
 		studySessions.POST("/:id/words/:word_id/review", h.RecordWordReview)
+		studySessions.POST("/:id/reviews", h.RecordReviews)
+		studySessions.DELETE("/:id/reviews/last", h.UndoLastReview)
+		studySessions.GET("/:id/reviews/audit", h.GetReviewAudit)
 	}
 
+	r.PATCH("/reviews/:id", h.UpdateReview)
+
 	r.POST("/reset_history", h.ResetHistory)
 	r.POST("/full_reset", h.FullReset)
 }
 
 // GetLastStudySession handles GET /api/dashboard/last_study_session
 func (h *StudyHandler) GetLastStudySession(c *gin.Context) {
-	session, err := h.studyService.GetLastStudySession()
+	session, err := h.studyService.GetLastStudySession(c.Request.Context())
 	if err != nil {
 		response.InternalError(c, err)
 		return
@@ -73,7 +80,7 @@ func (h *StudyHandler) GetLastStudySession(c *gin.Context) {
 
 // GetStudyProgress handles GET /api/dashboard/study_progress
 func (h *StudyHandler) GetStudyProgress(c *gin.Context) {
-	progress, err := h.studyService.GetStudyProgress()
+	progress, err := h.studyService.GetStudyProgress(c.Request.Context())
 	if err != nil {
 		response.InternalError(c, err)
 		return
@@ -83,7 +90,7 @@ func (h *StudyHandler) GetStudyProgress(c *gin.Context) {
 
 // GetQuickStats handles GET /api/dashboard/quick_stats
 func (h *StudyHandler) GetQuickStats(c *gin.Context) {
-	stats, err := h.studyService.GetQuickStats()
+	stats, err := h.studyService.GetQuickStats(c.Request.Context())
 	if err != nil {
 		response.InternalError(c, err)
 		return
@@ -91,6 +98,16 @@ func (h *StudyHandler) GetQuickStats(c *gin.Context) {
 	response.Success(c, stats)
 }
 
+// GetStreak handles GET /api/dashboard/streak
+func (h *StudyHandler) GetStreak(c *gin.Context) {
+	streak, err := h.studyService.GetStreak(c.Request.Context())
+	if err != nil {
+		response.InternalError(c, err)
+		return
+	}
+	response.Success(c, streak)
+}
+
 // StartStudyActivity handles POST /api/study-activities
 func (h *StudyHandler) StartStudyActivity(c *gin.Context) {
 	var req struct {
@@ -103,9 +120,14 @@ func (h *StudyHandler) StartStudyActivity(c *gin.Context) {
 		return
 	}
 
-	session, err := h.studyService.StartStudySession(req.GroupID, req.StudyActivityID)
+	session, err := h.studyService.StartStudySession(c.Request.Context(), req.GroupID, req.StudyActivityID)
 	if err != nil {
-		response.InternalError(c, err)
+		switch {
+		case errors.Is(err, service.ErrGroupNotFound), errors.Is(err, service.ErrActivityNotFound):
+			response.NotFound(c, err)
+		default:
+			response.InternalError(c, err)
+		}
 		return
 	}
 
@@ -116,7 +138,7 @@ func (h *StudyHandler) StartStudyActivity(c *gin.Context) {
 func (h *StudyHandler) GetStudySessions(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
-	sessions, err := h.studyService.GetStudySessions(page, perPage)
+	sessions, err := h.studyService.GetStudySessions(c.Request.Context(), page, perPage)
 	if err != nil {
 		response.InternalError(c, err)
 		return
@@ -131,7 +153,7 @@ func (h *StudyHandler) GetStudySession(c *gin.Context) {
 		response.BadRequest(c, errors.New("invalid session ID"))
 		return
 	}
-	session, err := h.studyService.GetStudySession(id)
+	session, err := h.studyService.GetStudySession(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			response.NotFound(c, errors.New("study session not found"))
@@ -152,7 +174,7 @@ func (h *StudyHandler) GetStudySessionWords(c *gin.Context) {
 	}
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
-	words, err := h.studyService.GetStudySessionWords(id, page, perPage)
+	words, err := h.studyService.GetStudySessionWords(c.Request.Context(), id, page, perPage)
 	if err != nil {
 		response.InternalError(c, err)
 		return
@@ -175,7 +197,9 @@ func (h *StudyHandler) RecordWordReview(c *gin.Context) {
 	}
 
 	var req struct {
-		Correct *bool `json:"correct"`
+		Correct    *bool  `json:"correct"`
+		Answer     string `json:"answer"`
+		ResponseMs *int   `json:"response_ms"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -187,7 +211,22 @@ func (h *StudyHandler) RecordWordReview(c *gin.Context) {
 		return
 	}
 
-	review, err := h.studyService.RecordWordReview(sessionID, wordID, *req.Correct)
+	if req.ResponseMs != nil && *req.ResponseMs < 0 {
+		response.BadRequest(c, errors.New("response_ms must not be negative"))
+		return
+	}
+
+	review, err := h.studyService.RecordWordReview(c.Request.Context(), models.WordReviewItem{
+		WordID:         wordID,
+		StudySessionID: sessionID,
+		Correct:        *req.Correct,
+		Answer:         req.Answer,
+		ResponseMs:     req.ResponseMs,
+	})
+	if errors.Is(err, service.ErrSessionNotFound) || errors.Is(err, service.ErrWordNotFound) {
+		response.NotFound(c, err)
+		return
+	}
 	if err != nil {
 		response.InternalError(c, err)
 		return
@@ -196,6 +235,110 @@ func (h *StudyHandler) RecordWordReview(c *gin.Context) {
 	response.Success(c, review)
 }
 
+// RecordReviews handles POST /api/study_sessions/:id/reviews with a JSON
+// array of reviews, recorded together or not at all
+func (h *StudyHandler) RecordReviews(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid session ID"))
+		return
+	}
+
+	var req []models.ReviewSubmission
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	result, err := h.studyService.RecordReviews(c.Request.Context(), sessionID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSessionNotFound):
+			response.NotFound(c, err)
+		case errors.Is(err, service.ErrInvalidReviews):
+			response.BadRequest(c, err)
+		default:
+			response.InternalError(c, err)
+		}
+		return
+	}
+	response.Success(c, result)
+}
+
+// UndoLastReview handles DELETE /api/study_sessions/:id/reviews/last
+func (h *StudyHandler) UndoLastReview(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid session ID"))
+		return
+	}
+	change, err := h.studyService.UndoLastReview(c.Request.Context(), sessionID)
+	if err != nil {
+		handleReviewChangeError(c, err)
+		return
+	}
+	response.Success(c, change)
+}
+
+// UpdateReview handles PATCH /api/reviews/:id with correct and/or a 0-5
+// grade. Without a body, or without either, the review is flipped.
+func (h *StudyHandler) UpdateReview(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid review ID"))
+		return
+	}
+
+	var req struct {
+		Correct *bool `json:"correct"`
+		Grade   *int  `json:"grade"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+
+	change, err := h.studyService.UpdateReview(c.Request.Context(), id, req.Correct, req.Grade)
+	if err != nil {
+		handleReviewChangeError(c, err)
+		return
+	}
+	response.Success(c, change)
+}
+
+// GetReviewAudit handles GET /api/study_sessions/:id/reviews/audit
+func (h *StudyHandler) GetReviewAudit(c *gin.Context) {
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid session ID"))
+		return
+	}
+	entries, err := h.studyService.GetReviewAudit(c.Request.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			response.NotFound(c, err)
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+	response.Success(c, entries)
+}
+
+// handleReviewChangeError responds to a failed undo or re-grade
+func handleReviewChangeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSessionNotFound), errors.Is(err, service.ErrNoReviews), errors.Is(err, service.ErrReviewNotFound):
+		response.NotFound(c, err)
+	case errors.Is(err, service.ErrReviewLocked), errors.Is(err, service.ErrReviewUnchanged), errors.Is(err, service.ErrInvalidGrade):
+		response.BadRequest(c, err)
+	default:
+		response.InternalError(c, err)
+	}
+}
+
 // ResetHistory handles POST /api/reset-history
 func (h *StudyHandler) ResetHistory(c *gin.Context) {
 	// TODO: Implement after adding service method
@@ -221,16 +364,16 @@ func (h *StudyHandler) GetStudyActivity(c *gin.Context) {
 		response.BadRequest(c, errors.New("invalid activity ID"))
 		return
 	}
-	details, err := h.studyService.GetStudyActivityDetails(id)
+	activity, err := h.studyService.GetStudyActivity(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(c, errors.New("activity not found"))
+		if errors.Is(err, service.ErrActivityNotFound) {
+			response.NotFound(c, err)
 			return
 		}
 		response.InternalError(c, err)
 		return
 	}
-	response.Success(c, details)
+	response.Success(c, activity)
 }
 
 // GetActivityStudySessions handles GET /api/study_activities/:id/study_sessions
@@ -242,7 +385,7 @@ func (h *StudyHandler) GetActivityStudySessions(c *gin.Context) {
 	}
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "100"))
-	sessions, err := h.studyService.GetStudySessionsByActivity(id, page, perPage)
+	sessions, err := h.studyService.GetStudySessionsByActivity(c.Request.Context(), id, page, perPage)
 	if err != nil {
 		response.InternalError(c, err)
 		return
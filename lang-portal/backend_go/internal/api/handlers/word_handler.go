@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"lang-portal/internal/api/response"
+	"lang-portal/internal/models"
 	"lang-portal/internal/service"
 )
 
@@ -27,6 +28,9 @@ func (h *WordHandler) RegisterRoutes(r *gin.RouterGroup) {
 	{
 		words.GET("", h.GetWords)
 		words.GET("/:id", h.GetWord)
+		words.GET("/:id/history", h.GetWordHistory)
+		words.POST("/:id/suspend", h.SuspendWord)
+		words.POST("/:id/unsuspend", h.UnsuspendWord)
 	}
 }
 
@@ -42,7 +46,9 @@ func (h *WordHandler) GetWords(c *gin.Context) {
 		perPage = 100
 	}
 
-	words, err := h.wordService.GetWords(page, perPage)
+	leechOnly, _ := strconv.ParseBool(c.DefaultQuery("leech", "false"))
+
+	words, err := h.wordService.GetWords(c.Request.Context(), page, perPage, leechOnly)
 	if err != nil {
 		response.InternalError(c, err)
 		return
@@ -54,6 +60,8 @@ func (h *WordHandler) GetWords(c *gin.Context) {
 		English      string `json:"english"`
 		CorrectCount int    `json:"correct_count"`
 		WrongCount   int    `json:"wrong_count"`
+		Leech        bool   `json:"leech"`
+		Suspended    bool   `json:"suspended"`
 	}
 	items := make([]listItem, 0, len(words.Items))
 	for _, w := range words.Items {
@@ -62,6 +70,8 @@ func (h *WordHandler) GetWords(c *gin.Context) {
 			English:      w.English,
 			CorrectCount: w.Stats.CorrectCount,
 			WrongCount:   w.Stats.WrongCount,
+			Leech:        w.Stats.Leech,
+			Suspended:    w.Stats.Suspended,
 		})
 	}
 
@@ -79,7 +89,7 @@ func (h *WordHandler) GetWord(c *gin.Context) {
 		return
 	}
 
-	word, err := h.wordService.GetWordByID(id)
+	word, err := h.wordService.GetWordByID(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			response.NotFound(c, errors.New("word not found"))
@@ -88,7 +98,7 @@ func (h *WordHandler) GetWord(c *gin.Context) {
 		response.InternalError(c, err)
 		return
 	}
-	groups, err := h.wordService.GetGroupsForWord(id)
+	groups, err := h.wordService.GetGroupsForWord(c.Request.Context(), id)
 	if err != nil {
 		response.InternalError(c, err)
 		return
@@ -99,7 +109,66 @@ func (h *WordHandler) GetWord(c *gin.Context) {
 		"stats": gin.H{
 			"correct_count": word.Stats.CorrectCount,
 			"wrong_count":   word.Stats.WrongCount,
+			"mastery":       word.Stats.Mastery,
+			"leech":         word.Stats.Leech,
+			"suspended":     word.Stats.Suspended,
 		},
 		"groups": groups,
 	})
 }
+
+// GetWordHistory handles GET /api/words/:id/history
+func (h *WordHandler) GetWordHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid word ID"))
+		return
+	}
+
+	history, err := h.wordService.GetWordHistory(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrWordNotFound) {
+			response.NotFound(c, errors.New("word not found"))
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, history)
+}
+
+// SuspendWord handles POST /api/words/:id/suspend
+func (h *WordHandler) SuspendWord(c *gin.Context) {
+	h.setSuspended(c, true)
+}
+
+// UnsuspendWord handles POST /api/words/:id/unsuspend
+func (h *WordHandler) UnsuspendWord(c *gin.Context) {
+	h.setSuspended(c, false)
+}
+
+func (h *WordHandler) setSuspended(c *gin.Context, suspended bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, errors.New("invalid word ID"))
+		return
+	}
+
+	var flags *models.WordFlags
+	if suspended {
+		flags, err = h.wordService.SuspendWord(c.Request.Context(), id)
+	} else {
+		flags, err = h.wordService.UnsuspendWord(c.Request.Context(), id)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrWordNotFound) {
+			response.NotFound(c, errors.New("word not found"))
+			return
+		}
+		response.InternalError(c, err)
+		return
+	}
+
+	response.Success(c, flags)
+}
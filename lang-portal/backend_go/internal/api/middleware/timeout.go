@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/api/response"
+)
+
+// TimeoutConfig sets how long a request may run before its context is cancelled
+type TimeoutConfig struct {
+	// Default applies to every route without an entry in Routes; zero disables it
+	Default time.Duration
+	// Routes overrides the default per route, keyed by the registered path
+	// (e.g. "/api/analytics/heatmap")
+	Routes map[string]time.Duration
+}
+
+// Timeout bounds each request's context by the route's timeout. Services
+// stop waiting on the database once it fires; if the handler has not
+// responded by then, the client gets a 504 (deadline reached) or a 503
+// (request cancelled) instead of a generic error.
+func Timeout(cfg TimeoutConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := cfg.Default
+		if d, ok := cfg.Routes[c.FullPath()]; ok {
+			timeout = d
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if c.Writer.Written() {
+			return
+		}
+		switch err := ctx.Err(); {
+		case errors.Is(err, context.DeadlineExceeded):
+			response.Error(c, http.StatusGatewayTimeout, fmt.Errorf("%w after %s", response.ErrTimeout, timeout))
+		case errors.Is(err, context.Canceled):
+			response.Error(c, http.StatusServiceUnavailable, response.ErrCanceled)
+		}
+	}
+}
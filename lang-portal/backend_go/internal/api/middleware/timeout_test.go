@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/api/response"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Timeout(TimeoutConfig{
+		Default: 20 * time.Millisecond,
+		Routes:  map[string]time.Duration{"/long": time.Second, "/unbounded": 0},
+	}))
+	// wait blocks until the request's context ends, then leaves the response to the middleware
+	wait := func(c *gin.Context) { <-c.Request.Context().Done() }
+	r.GET("/silent", wait)
+	r.GET("/failed", func(c *gin.Context) {
+		wait(c)
+		response.InternalError(c, c.Request.Context().Err())
+	})
+	slowOK := func(c *gin.Context) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			c.String(http.StatusOK, "done")
+		case <-c.Request.Context().Done():
+		}
+	}
+	r.GET("/long", slowOK)
+	r.GET("/unbounded", slowOK)
+
+	for _, tc := range []struct {
+		path   string
+		cancel bool
+		code   int
+		want   string
+	}{
+		{"/silent", false, http.StatusGatewayTimeout, "request timed out after 20ms"},
+		{"/failed", false, http.StatusGatewayTimeout, "request timed out: context deadline exceeded"},
+		{"/silent", true, http.StatusServiceUnavailable, "request was cancelled"},
+		{"/failed", true, http.StatusServiceUnavailable, "request was cancelled: context canceled"},
+		{"/long", false, http.StatusOK, "done"},
+		{"/unbounded", false, http.StatusOK, "done"},
+	} {
+		ctx, cancel := context.WithCancel(context.Background())
+		if tc.cancel {
+			// The client goes away before the deadline
+			time.AfterFunc(time.Millisecond, cancel)
+		}
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		cancel()
+
+		if w.Code != tc.code || !strings.Contains(w.Body.String(), tc.want) {
+			t.Errorf("GET %s (cancelled %v) = %d %s, want %d with %q", tc.path, tc.cancel, w.Code, w.Body, tc.code, tc.want)
+		}
+	}
+}
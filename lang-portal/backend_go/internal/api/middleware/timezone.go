@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/internal/api/response"
+)
+
+// Timezone renders a request's timestamps in the IANA zone named by its
+// optional tz query parameter (e.g. ?tz=Asia/Shanghai) instead of UTC. It
+// only changes how times are written; day boundaries still follow the
+// learner's timezone setting. An unknown zone is a 400.
+func Timezone() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Query("tz")
+		if name == "" {
+			c.Next()
+			return
+		}
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			response.BadRequest(c, fmt.Errorf("unknown tz %q", name))
+			c.Abort()
+			return
+		}
+		response.SetLocation(c, loc)
+		c.Next()
+	}
+}
@@ -0,0 +1,76 @@
+package response
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// locationKey is the gin context key of the zone responses render times in
+const locationKey = "response.location"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SetLocation has Success render the request's timestamps in loc instead of UTC
+func SetLocation(c *gin.Context, loc *time.Location) {
+	c.Set(locationKey, loc)
+}
+
+// inLocation returns data with every time.Time it holds moved to loc. Zero
+// times are left alone so they still read as unset.
+func inLocation(data interface{}, loc *time.Location) interface{} {
+	if data == nil {
+		return nil
+	}
+	v := reflect.New(reflect.TypeOf(data)).Elem()
+	v.Set(reflect.ValueOf(data))
+	localize(v, loc)
+	return v.Interface()
+}
+
+// localize moves the times in v, which must be settable, to loc. Pointers
+// are followed and the values they point at changed in place.
+func localize(v reflect.Value, loc *time.Location) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			localize(v.Elem(), loc)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		localize(elem, loc)
+		v.Set(elem)
+	case reflect.Struct:
+		if v.Type() == timeType {
+			if t := v.Interface().(time.Time); !t.IsZero() {
+				v.Set(reflect.ValueOf(t.In(loc)))
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				localize(f, loc)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			localize(v.Index(i), loc)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			localize(elem, loc)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+}
@@ -1,18 +1,33 @@
 package response
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+var (
+	// ErrTimeout is reported when a request runs past its deadline
+	ErrTimeout = errors.New("request timed out")
+	// ErrCanceled is reported when a request is cancelled before it completes
+	ErrCanceled = errors.New("request was cancelled")
+)
+
 // Error response structure
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
-// Success sends a successful JSON response
+// Success sends a successful JSON response. Timestamps are RFC 3339, in UTC
+// or in the zone set with SetLocation.
 func Success(c *gin.Context, data interface{}) {
+	if loc, ok := c.Get(locationKey); ok {
+		data = inLocation(data, loc.(*time.Location))
+	}
 	c.JSON(http.StatusOK, data)
 }
 
@@ -31,7 +46,16 @@ func NotFound(c *gin.Context, err error) {
 	Error(c, http.StatusNotFound, err)
 }
 
-// InternalError sends a 500 internal server error response
+// InternalError sends a 500 internal server error response. Errors caused by
+// the request's context ending are reported as 504 (deadline exceeded) or
+// 503 (cancelled) instead.
 func InternalError(c *gin.Context, err error) {
-	Error(c, http.StatusInternalServerError, err)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		Error(c, http.StatusGatewayTimeout, fmt.Errorf("%w: %v", ErrTimeout, err))
+	case errors.Is(err, context.Canceled):
+		Error(c, http.StatusServiceUnavailable, fmt.Errorf("%w: %v", ErrCanceled, err))
+	default:
+		Error(c, http.StatusInternalServerError, err)
+	}
 }
@@ -3,14 +3,31 @@ package server
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 
 	"lang-portal/internal/api/handlers"
+	"lang-portal/internal/api/middleware"
 	"lang-portal/internal/service"
 )
 
+// RequestTimeouts bounds how long API requests may hold the database.
+// Reports that scan the whole review history get longer than the default.
+var RequestTimeouts = middleware.TimeoutConfig{
+	Default: 10 * time.Second,
+	Routes: map[string]time.Duration{
+		"/api/analytics/reviews":        30 * time.Second,
+		"/api/analytics/heatmap":        30 * time.Second,
+		"/api/analytics/forecast":       30 * time.Second,
+		"/api/analytics/confusions":     30 * time.Second,
+		"/api/settings/rebuild_mastery": 2 * time.Minute,
+		"/api/admin/integrity":          2 * time.Minute,
+		"/api/admin/integrity/repair":   2 * time.Minute,
+	},
+}
+
 // Config holds server configuration
 type Config struct {
 	Port int
@@ -23,7 +40,7 @@ type Server struct {
 	service *service.Services
 }
 
-// NewServer creates a new server instance
+// NewServer creates a new server instance with every API route registered
 func NewServer(config Config, services *service.Services) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
@@ -41,19 +58,22 @@ func NewServer(config Config, services *service.Services) *Server {
 		AllowCredentials: true,
 	}))
 
-	return &Server{
+	s := &Server{
 		router:  router,
 		config:  config,
 		service: services,
 	}
+	s.registerRoutes()
+	return s
+}
+
+// ServeHTTP serves a request through the server's routes
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
 }
 
 // Start starts the HTTP server
 func (s *Server) Start() error {
-	// Register routes
-	s.registerRoutes()
-
-	// Start server
 	addr := fmt.Sprintf(":%d", s.config.Port)
 	return s.router.Run(addr)
 }
@@ -67,15 +87,34 @@ func (s *Server) registerRoutes() {
 
 	// API routes
 	api := s.router.Group("/api")
+	api.Use(middleware.Timeout(RequestTimeouts), middleware.Timezone())
 	{
 		// Register handlers
 		wordHandler := handlers.NewWordHandler(s.service.Word)
 		groupHandler := handlers.NewGroupHandler(s.service.Group)
 		studyHandler := handlers.NewStudyHandler(s.service.Study)
+		analysisHandler := handlers.NewAnalysisHandler(s.service.Analysis)
+		sentenceHandler := handlers.NewSentenceHandler(s.service.Sentence)
+		exampleHandler := handlers.NewExampleHandler(s.service.Example)
+		quizHandler := handlers.NewQuizHandler(s.service.Quiz)
+		queueHandler := handlers.NewQueueHandler(s.service.Queue)
+		settingsHandler := handlers.NewSettingsHandler(s.service.Settings)
+		analyticsHandler := handlers.NewAnalyticsHandler(s.service.Analytics, s.service.Group)
+		goalHandler := handlers.NewGoalHandler(s.service.Goal)
+		adminHandler := handlers.NewAdminHandler(s.service.Integrity)
 
 		// Register routes
 		wordHandler.RegisterRoutes(api)
 		groupHandler.RegisterRoutes(api)
 		studyHandler.RegisterRoutes(api)
+		analysisHandler.RegisterRoutes(api)
+		sentenceHandler.RegisterRoutes(api)
+		exampleHandler.RegisterRoutes(api)
+		quizHandler.RegisterRoutes(api)
+		queueHandler.RegisterRoutes(api)
+		settingsHandler.RegisterRoutes(api)
+		analyticsHandler.RegisterRoutes(api)
+		goalHandler.RegisterRoutes(api)
+		adminHandler.RegisterRoutes(api)
 	}
 }
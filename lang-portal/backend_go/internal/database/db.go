@@ -1,65 +1,148 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sync"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
 
 var (
-	db   *sql.DB
+	db   *DB
 	once sync.Once
 )
 
 // Config holds database configuration
 type Config struct {
 	DBPath string
+	// ReadConns is the size of the read-only connection pool
+	ReadConns int
+	// BusyTimeout is how long SQLite waits on a lock before returning SQLITE_BUSY
+	BusyTimeout time.Duration
 }
 
 // DefaultConfig returns the default database configuration
 func DefaultConfig() Config {
 	return Config{
-		DBPath: "words.db",
+		DBPath:      "words.db",
+		ReadConns:   max(4, runtime.NumCPU()),
+		BusyTimeout: 5 * time.Second,
 	}
 }
 
-// Initialize sets up the database connection
-func Initialize(cfg Config) error {
-	var err error
-	once.Do(func() {
-		// Ensure the directory exists
-		dir := filepath.Dir(cfg.DBPath)
-		if dir != "." {
-			if err = os.MkdirAll(dir, 0755); err != nil {
-				return
-			}
-		}
+// DB is the application's SQLite database. SQLite allows one writer at a
+// time, so all writes go through a single connection; in WAL mode readers
+// don't block it (or each other), so reads get a pool of read-only connections.
+type DB struct {
+	Writer *sql.DB
+	Reader *sql.DB
+}
 
-		// Open database connection
-		db, err = sql.Open("sqlite", cfg.DBPath)
-		if err != nil {
-			return
+// Open opens the database with WAL, busy_timeout and foreign keys enabled on
+// every connection. An in-memory database has no separate readers, so its
+// Reader is the writer connection.
+func Open(cfg Config) (*DB, error) {
+	defaults := DefaultConfig()
+	if cfg.ReadConns <= 0 {
+		cfg.ReadConns = defaults.ReadConns
+	}
+	if cfg.BusyTimeout <= 0 {
+		cfg.BusyTimeout = defaults.BusyTimeout
+	}
+
+	// Ensure the directory exists
+	if dir := filepath.Dir(cfg.DBPath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
 		}
+	}
+
+	// BEGIN IMMEDIATE takes the write lock up front, so a transaction never
+	// has to upgrade a read lock (which fails with SQLITE_BUSY without waiting)
+	writer, err := openPool(dsn(cfg, false), 1)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DBPath == ":memory:" {
+		return &DB{Writer: writer, Reader: writer}, nil
+	}
 
-		// Set connection parameters
-		db.SetMaxOpenConns(1) // SQLite only supports one writer at a time
-		db.SetMaxIdleConns(1)
+	// Make sure the file exists and is in WAL mode before opening it read-only
+	var mode string
+	if err := writer.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		writer.Close()
+		return nil, fmt.Errorf("failed to check journal mode: %w", err)
+	}
 
-		// Test the connection
-		if err = db.Ping(); err != nil {
-			return
+	reader, err := openPool(dsn(cfg, true), cfg.ReadConns)
+	if err != nil {
+		writer.Close()
+		return nil, err
+	}
+	return &DB{Writer: writer, Reader: reader}, nil
+}
+
+// openPool opens a connection pool of at most n connections and checks it works
+func openPool(dsn string, n int) (*sql.DB, error) {
+	pool, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	pool.SetMaxOpenConns(n)
+	pool.SetMaxIdleConns(n)
+	if err := pool.Ping(); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return pool, nil
+}
+
+// dsn builds the connection string; every connection in the pool runs its pragmas
+func dsn(cfg Config, readOnly bool) string {
+	q := url.Values{}
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", cfg.BusyTimeout.Milliseconds()))
+	q.Add("_pragma", "foreign_keys(1)")
+	if cfg.DBPath == ":memory:" {
+		return ":memory:?" + q.Encode()
+	}
+	if readOnly {
+		q.Set("mode", "ro")
+		q.Add("_pragma", "query_only(1)")
+	} else {
+		q.Add("_pragma", "journal_mode(WAL)")
+		q.Add("_pragma", "synchronous(NORMAL)")
+		q.Set("_txlock", "immediate")
+	}
+	return "file:" + filepath.ToSlash(cfg.DBPath) + "?" + q.Encode()
+}
+
+// Close closes both connection pools
+func (d *DB) Close() error {
+	err := d.Writer.Close()
+	if d.Reader != d.Writer {
+		if rerr := d.Reader.Close(); err == nil {
+			err = rerr
 		}
+	}
+	return err
+}
 
-		// Enable foreign key constraints
-		if _, err = db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+// Initialize sets up the database connection
+func Initialize(cfg Config) error {
+	var err error
+	once.Do(func() {
+		db, err = Open(cfg)
+		if err != nil {
 			return
 		}
-
 		log.Printf("Successfully connected to database: %s", cfg.DBPath)
 	})
 
@@ -67,7 +150,7 @@ func Initialize(cfg Config) error {
 }
 
 // GetDB returns the database instance
-func GetDB() *sql.DB {
+func GetDB() *DB {
 	if db == nil {
 		panic("Database not initialized. Call Initialize() first")
 	}
@@ -82,30 +165,7 @@ func Close() error {
 	return nil
 }
 
-// Transaction executes a function within a database transaction
-func Transaction(fn func(*sql.Tx) error) error {
-	tx, err := GetDB().Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p)
-		}
-	}()
-
-	if err := fn(tx); err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return fmt.Errorf("error rolling back transaction: %v (original error: %w)", rbErr, err)
-		}
-		return err
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+// Transaction runs fn in a write transaction (see TxRunner.InTx)
+func Transaction(ctx context.Context, fn func(ctx context.Context, tx DBTX) error) error {
+	return NewTxRunner(GetDB().Writer).InTx(ctx, fn)
 }
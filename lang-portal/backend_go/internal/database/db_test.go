@@ -0,0 +1,266 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func openTestDB(tb testing.TB) *DB {
+	tb.Helper()
+	cfg := DefaultConfig()
+	cfg.DBPath = filepath.Join(tb.TempDir(), "test.db")
+	db, err := Open(cfg)
+	if err != nil {
+		tb.Fatalf("Open() error = %v", err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	seedItems(tb, db)
+	return db
+}
+
+// seedItems creates a table big enough for reads to take a little while
+func seedItems(tb testing.TB, db *DB) {
+	tb.Helper()
+	if _, err := db.Writer.Exec(`
+		CREATE TABLE items (id INTEGER PRIMARY KEY, bucket INTEGER NOT NULL, value INTEGER NOT NULL);
+		WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 20000)
+		INSERT INTO items (bucket, value) SELECT i % 50, i FROM n;
+	`); err != nil {
+		tb.Fatalf("failed to seed: %v", err)
+	}
+}
+
+func TestOpenConfiguresEveryConnection(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	// Hold several reader connections at once so the pragmas are checked on
+	// more than the first connection
+	for i := 0; i < 3; i++ {
+		conn, err := db.Reader.Conn(ctx)
+		if err != nil {
+			t.Fatalf("Conn() error = %v", err)
+		}
+		defer conn.Close()
+
+		var journal string
+		var fk, busy int
+		if err := conn.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&journal); err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&fk); err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busy); err != nil {
+			t.Fatal(err)
+		}
+		if journal != "wal" || fk != 1 || busy != 5000 {
+			t.Errorf("reader %d: journal_mode=%s foreign_keys=%d busy_timeout=%d, want wal/1/5000", i, journal, fk, busy)
+		}
+		if _, err := conn.ExecContext(ctx, "INSERT INTO items (bucket, value) VALUES (0, 0)"); err == nil {
+			t.Errorf("reader %d accepted a write", i)
+		}
+	}
+
+	var fk int
+	if err := db.Writer.QueryRow("PRAGMA foreign_keys").Scan(&fk); err != nil || fk != 1 {
+		t.Errorf("writer foreign_keys = %d (%v), want 1", fk, err)
+	}
+}
+
+func TestRetryBusy(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	// A second writer, as another process would be, that gives up on locks immediately
+	other, err := openPool(dsn(Config{DBPath: db.path(t), BusyTimeout: time.Millisecond}, false), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+
+	tx, err := db.Writer.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec("UPDATE items SET value = value + 1 WHERE id = 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = other.Exec("UPDATE items SET value = 0 WHERE id = 2")
+	if !IsBusy(err) {
+		t.Fatalf("write while locked: err = %v, want SQLITE_BUSY", err)
+	}
+
+	time.AfterFunc(50*time.Millisecond, func() { tx.Commit() })
+	attempts := 0
+	err = RetryBusy(ctx, func() error {
+		attempts++
+		_, err := other.Exec("UPDATE items SET value = 0 WHERE id = 2")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("RetryBusy() error = %v", err)
+	}
+	if attempts < 2 {
+		t.Errorf("RetryBusy() made %d attempts, want a retry", attempts)
+	}
+
+	notBusy := errors.New("boom")
+	attempts = 0
+	if err := RetryBusy(ctx, func() error { attempts++; return notBusy }); err != notBusy || attempts != 1 {
+		t.Errorf("RetryBusy() = %v after %d attempts, want the error after 1", err, attempts)
+	}
+}
+
+// path returns the file the writer connection has open
+func (d *DB) path(t *testing.T) string {
+	t.Helper()
+	var seq int
+	var name, file string
+	if err := d.Writer.QueryRow("PRAGMA database_list").Scan(&seq, &name, &file); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// openSingleConnection opens the database the way it was before the reader
+// pool: one connection for everything and the default rollback journal
+func openSingleConnection(tb testing.TB) *DB {
+	tb.Helper()
+	pool, err := openPool(filepath.Join(tb.TempDir(), "single.db"), 1)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { pool.Close() })
+	db := &DB{Writer: pool, Reader: pool}
+	seedItems(tb, db)
+	return db
+}
+
+// writeHold is how long the load test's writer keeps each transaction open,
+// standing in for the work a request does between its statements
+const writeHold = 5 * time.Millisecond
+
+// writeLoop runs write transactions back to back until stop is closed
+func writeLoop(tb testing.TB, db *DB, stop <-chan struct{}) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctx := context.Background()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			err := RetryBusy(ctx, func() error {
+				tx, err := db.Writer.BeginTx(ctx, nil)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback()
+				if _, err := tx.ExecContext(ctx, "UPDATE items SET value = value + 1 WHERE id = ?", i%20000+1); err != nil {
+					return err
+				}
+				time.Sleep(writeHold)
+				return tx.Commit()
+			})
+			if err != nil {
+				tb.Errorf("writer: %v", err)
+				return
+			}
+		}
+	}()
+	return &wg
+}
+
+// readOp is one read of the load test: an aggregate over part of the table
+func readOp(ctx context.Context, db *DB, i int) error {
+	var sum int64
+	return db.Reader.QueryRowContext(ctx, "SELECT SUM(value) FROM items WHERE bucket = ?", i%50).Scan(&sum)
+}
+
+// runLoad runs readers alongside a writer for the given duration and
+// returns the reads per second
+func runLoad(tb testing.TB, db *DB, readers int, duration time.Duration) float64 {
+	tb.Helper()
+	ctx := context.Background()
+	stop := make(chan struct{})
+	writer := writeLoop(tb, db, stop)
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		reads int
+	)
+	deadline := time.Now().Add(duration)
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+			n := 0
+			for ; time.Now().Before(deadline); n++ {
+				if err := readOp(ctx, db, r+n); err != nil {
+					tb.Errorf("reader %d: %v", r, err)
+					return
+				}
+			}
+			mu.Lock()
+			reads += n
+			mu.Unlock()
+		}(r)
+	}
+	wg.Wait()
+	close(stop)
+	writer.Wait()
+	return float64(reads) / duration.Seconds()
+}
+
+func TestConcurrentLoad(t *testing.T) {
+	if testing.Short() {
+		t.Skip("load test")
+	}
+	const readers = 8
+	single := runLoad(t, openSingleConnection(t), readers, time.Second)
+	pooled := runLoad(t, openTestDB(t), readers, time.Second)
+	t.Logf("%d readers with a busy writer: single connection %.0f reads/s, reader pool %.0f reads/s (%.1fx)",
+		readers, single, pooled, pooled/single)
+}
+
+// BenchmarkConcurrentReads measures reads while a writer keeps transactions open
+func BenchmarkConcurrentReads(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		open func(testing.TB) *DB
+	}{
+		{"single_connection", openSingleConnection},
+		{"reader_pool", openTestDB},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			db := bc.open(b)
+			ctx := context.Background()
+			stop := make(chan struct{})
+			writer := writeLoop(b, db, stop)
+
+			b.SetParallelism(8)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					if err := readOp(ctx, db, i); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.StopTimer()
+			close(stop)
+			writer.Wait()
+		})
+	}
+}
@@ -0,0 +1,15 @@
+-- Slot roles for sentence words and a log of hints shown to the learner
+
+ALTER TABLE sentence_words ADD COLUMN role TEXT;
+
+CREATE TABLE IF NOT EXISTS sentence_hints (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    sentence_id INTEGER NOT NULL,
+    study_session_id INTEGER NOT NULL,
+    level INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (sentence_id) REFERENCES sentences(id),
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sentence_hints_session ON sentence_hints(study_session_id, sentence_id);
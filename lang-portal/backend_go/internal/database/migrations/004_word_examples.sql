@@ -0,0 +1,13 @@
+-- Example sentences for words, used by the cloze exercise
+
+CREATE TABLE IF NOT EXISTS word_examples (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    word_id INTEGER NOT NULL,
+    sentence TEXT NOT NULL,
+    translation TEXT NOT NULL,
+    source TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (word_id) REFERENCES words(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_examples_word_id ON word_examples(word_id);
@@ -0,0 +1,18 @@
+-- Multiple-choice questions; the correct option stays on the server
+
+CREATE TABLE IF NOT EXISTS quiz_questions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    study_session_id INTEGER NOT NULL,
+    word_id INTEGER NOT NULL,
+    direction TEXT NOT NULL,
+    prompt TEXT NOT NULL,
+    options JSON NOT NULL,
+    correct_index INTEGER NOT NULL,
+    chosen_index INTEGER,
+    answered_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id),
+    FOREIGN KEY (word_id) REFERENCES words(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_session ON quiz_questions(study_session_id);
@@ -0,0 +1,38 @@
+-- Per-word review schedule and persisted study session queues
+
+CREATE TABLE IF NOT EXISTS word_schedules (
+    word_id INTEGER PRIMARY KEY,
+    repetitions INTEGER NOT NULL DEFAULT 0,
+    interval_days REAL NOT NULL DEFAULT 0,
+    ease REAL NOT NULL DEFAULT 2.5,
+    due_at DATETIME NOT NULL,
+    last_reviewed_at DATETIME NOT NULL,
+    FOREIGN KEY (word_id) REFERENCES words(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_schedules_due_at ON word_schedules(due_at);
+
+CREATE TABLE IF NOT EXISTS session_queues (
+    study_session_id INTEGER PRIMARY KEY,
+    strategy TEXT NOT NULL,
+    new_ratio REAL NOT NULL,
+    no_repeat INTEGER NOT NULL,
+    current_word_id INTEGER,
+    current_reason TEXT,
+    served_after_review_id INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id),
+    FOREIGN KEY (current_word_id) REFERENCES words(id)
+);
+
+CREATE TABLE IF NOT EXISTS session_queue_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    study_session_id INTEGER NOT NULL,
+    word_id INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id),
+    FOREIGN KEY (word_id) REFERENCES words(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_queue_items_session ON session_queue_items(study_session_id);
@@ -0,0 +1,12 @@
+-- Per-word flags: leeches (words that keep lapsing) and suspended words
+
+CREATE TABLE IF NOT EXISTS word_flags (
+    word_id INTEGER PRIMARY KEY,
+    leech BOOLEAN NOT NULL DEFAULT 0,
+    suspended BOOLEAN NOT NULL DEFAULT 0,
+    leech_at DATETIME,
+    suspended_at DATETIME,
+    FOREIGN KEY (word_id) REFERENCES words(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_flags_suspended ON word_flags(suspended);
@@ -0,0 +1,6 @@
+-- Keep what the learner answered and which word a wrong answer matched
+
+ALTER TABLE word_review_items ADD COLUMN answer TEXT;
+ALTER TABLE word_review_items ADD COLUMN answer_word_id INTEGER REFERENCES words(id);
+
+CREATE INDEX IF NOT EXISTS idx_word_review_items_answer_word_id ON word_review_items(answer_word_id);
@@ -0,0 +1,9 @@
+-- Streak freezes and indexes for daily activity lookups
+
+CREATE TABLE IF NOT EXISTS streak_freezes (
+    day TEXT PRIMARY KEY, -- learner-local date, YYYY-MM-DD
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_study_sessions_created_at ON study_sessions(created_at);
+CREATE INDEX IF NOT EXISTS idx_word_review_items_created_at ON word_review_items(created_at);
@@ -0,0 +1,18 @@
+-- Daily goals and the streak freeze inventory
+
+CREATE TABLE IF NOT EXISTS goals (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    reviews INTEGER NOT NULL DEFAULT 0,
+    new_words INTEGER NOT NULL DEFAULT 0,
+    minutes INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS streak_state (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    freezes_available INTEGER NOT NULL DEFAULT 0,
+    settled_through TEXT, -- last learner-local day (YYYY-MM-DD) checked for freezes
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_review_items_word_id ON word_review_items(word_id);
@@ -0,0 +1,3 @@
+-- How long the learner took to answer, when the activity measures it
+
+ALTER TABLE word_review_items ADD COLUMN response_ms INTEGER;
@@ -0,0 +1,32 @@
+-- Per-word review totals kept up to date as reviews are recorded, replacing
+-- the correlated COUNT(*) subqueries on word_review_items
+
+CREATE TABLE IF NOT EXISTS word_stats (
+    word_id INTEGER PRIMARY KEY,
+    correct_count INTEGER NOT NULL DEFAULT 0,
+    wrong_count INTEGER NOT NULL DEFAULT 0,
+    last_reviewed_at DATETIME,
+    streak INTEGER NOT NULL DEFAULT 0, -- correct answers since the last wrong one
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (word_id) REFERENCES words(id)
+);
+
+WITH last_wrong AS (
+    SELECT word_id, MAX(id) AS id FROM word_review_items WHERE correct = 0 GROUP BY word_id
+)
+INSERT OR REPLACE INTO word_stats (word_id, correct_count, wrong_count, last_reviewed_at, streak)
+SELECT
+    wri.word_id,
+    COUNT(CASE WHEN wri.correct = 1 THEN 1 END),
+    COUNT(CASE WHEN wri.correct = 0 THEN 1 END),
+    MAX(wri.created_at),
+    COUNT(CASE WHEN wri.correct = 1 AND wri.id > COALESCE(lw.id, 0) THEN 1 END)
+FROM word_review_items wri
+LEFT JOIN last_wrong lw ON lw.word_id = wri.word_id
+GROUP BY wri.word_id;
+
+CREATE INDEX IF NOT EXISTS idx_words_created_at ON words(created_at);
+CREATE INDEX IF NOT EXISTS idx_words_groups_word_id ON words_groups(word_id);
+CREATE INDEX IF NOT EXISTS idx_words_groups_group_id ON words_groups(group_id);
+CREATE INDEX IF NOT EXISTS idx_word_review_items_session ON word_review_items(study_session_id);
+CREATE INDEX IF NOT EXISTS idx_word_flags_leech ON word_flags(leech);
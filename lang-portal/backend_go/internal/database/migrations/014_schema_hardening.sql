@@ -0,0 +1,236 @@
+-- Rebuild every table with a foreign key so references declare what happens
+-- on delete, words_groups holds each word once per group, study sessions
+-- reference their activity, and study_activities becomes the activity catalog.
+--
+-- SQLite can't alter constraints, so each table is recreated, copied, dropped
+-- and renamed. The manager runs migrations with foreign keys off and checks
+-- them before committing; its pre-check reports rows that would fail (orphans
+-- and duplicate group members) before anything is changed.
+--
+-- Deleting a word or group removes everything recorded about it; deleting a
+-- study activity is refused while sessions use it.
+
+-- The catalog of activities sessions are started from. The old rows logged
+-- activity launches, which nothing reads, so they are not kept.
+CREATE TABLE study_activities_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    thumbnail_url TEXT,
+    description TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO study_activities_new (id, name, thumbnail_url, description)
+VALUES (1, 'Vocabulary Quiz', 'https://example.com/thumbnail.jpg', 'Practice your vocabulary with flashcards');
+-- Sessions may name activities that were never catalogued
+INSERT INTO study_activities_new (id, name)
+SELECT DISTINCT study_activity_id, 'Study activity ' || study_activity_id
+FROM study_sessions
+WHERE study_activity_id != 1;
+DROP TABLE study_activities;
+ALTER TABLE study_activities_new RENAME TO study_activities;
+
+CREATE TABLE words_groups_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    word_id INTEGER NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (word_id, group_id)
+);
+INSERT INTO words_groups_new (id, word_id, group_id, created_at)
+SELECT id, word_id, group_id, created_at FROM words_groups;
+DROP TABLE words_groups;
+ALTER TABLE words_groups_new RENAME TO words_groups;
+CREATE INDEX idx_words_groups_group_id ON words_groups(group_id);
+
+CREATE TABLE study_sessions_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    study_activity_id INTEGER NOT NULL REFERENCES study_activities(id) ON DELETE RESTRICT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO study_sessions_new (id, group_id, study_activity_id, created_at)
+SELECT id, group_id, study_activity_id, created_at FROM study_sessions;
+DROP TABLE study_sessions;
+ALTER TABLE study_sessions_new RENAME TO study_sessions;
+CREATE INDEX idx_study_sessions_group_id ON study_sessions(group_id);
+CREATE INDEX idx_study_sessions_activity_id ON study_sessions(study_activity_id);
+CREATE INDEX idx_study_sessions_created_at ON study_sessions(created_at);
+
+CREATE TABLE word_review_items_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    word_id INTEGER NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    study_session_id INTEGER NOT NULL REFERENCES study_sessions(id) ON DELETE CASCADE,
+    correct BOOLEAN,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    answer TEXT,
+    answer_word_id INTEGER REFERENCES words(id) ON DELETE SET NULL,
+    response_ms INTEGER
+);
+INSERT INTO word_review_items_new (id, word_id, study_session_id, correct, created_at, answer, answer_word_id, response_ms)
+SELECT id, word_id, study_session_id, correct, created_at, answer, answer_word_id, response_ms FROM word_review_items;
+DROP TABLE word_review_items;
+ALTER TABLE word_review_items_new RENAME TO word_review_items;
+CREATE INDEX idx_word_review_items_word_id ON word_review_items(word_id);
+CREATE INDEX idx_word_review_items_session ON word_review_items(study_session_id);
+CREATE INDEX idx_word_review_items_answer_word_id ON word_review_items(answer_word_id);
+CREATE INDEX idx_word_review_items_created_at ON word_review_items(created_at);
+
+CREATE TABLE sentences_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    chinese TEXT NOT NULL,
+    english TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO sentences_new (id, group_id, chinese, english, created_at)
+SELECT id, group_id, chinese, english, created_at FROM sentences;
+DROP TABLE sentences;
+ALTER TABLE sentences_new RENAME TO sentences;
+CREATE INDEX idx_sentences_group_id ON sentences(group_id);
+
+CREATE TABLE sentence_words_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    sentence_id INTEGER NOT NULL REFERENCES sentences(id) ON DELETE CASCADE,
+    word_id INTEGER NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    role TEXT
+);
+INSERT INTO sentence_words_new (id, sentence_id, word_id, position, created_at, role)
+SELECT id, sentence_id, word_id, position, created_at, role FROM sentence_words;
+DROP TABLE sentence_words;
+ALTER TABLE sentence_words_new RENAME TO sentence_words;
+CREATE INDEX idx_sentence_words_sentence_id ON sentence_words(sentence_id);
+CREATE INDEX idx_sentence_words_word_id ON sentence_words(word_id);
+
+CREATE TABLE sentence_hints_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    sentence_id INTEGER NOT NULL REFERENCES sentences(id) ON DELETE CASCADE,
+    study_session_id INTEGER NOT NULL REFERENCES study_sessions(id) ON DELETE CASCADE,
+    level INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO sentence_hints_new (id, sentence_id, study_session_id, level, created_at)
+SELECT id, sentence_id, study_session_id, level, created_at FROM sentence_hints;
+DROP TABLE sentence_hints;
+ALTER TABLE sentence_hints_new RENAME TO sentence_hints;
+CREATE INDEX idx_sentence_hints_session ON sentence_hints(study_session_id, sentence_id);
+CREATE INDEX idx_sentence_hints_sentence_id ON sentence_hints(sentence_id);
+
+CREATE TABLE word_examples_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    word_id INTEGER NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    sentence TEXT NOT NULL,
+    translation TEXT NOT NULL,
+    source TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO word_examples_new (id, word_id, sentence, translation, source, created_at)
+SELECT id, word_id, sentence, translation, source, created_at FROM word_examples;
+DROP TABLE word_examples;
+ALTER TABLE word_examples_new RENAME TO word_examples;
+CREATE INDEX idx_word_examples_word_id ON word_examples(word_id);
+
+CREATE TABLE quiz_questions_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    study_session_id INTEGER NOT NULL REFERENCES study_sessions(id) ON DELETE CASCADE,
+    word_id INTEGER NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    direction TEXT NOT NULL,
+    prompt TEXT NOT NULL,
+    options JSON NOT NULL,
+    correct_index INTEGER NOT NULL,
+    chosen_index INTEGER,
+    answered_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO quiz_questions_new (id, study_session_id, word_id, direction, prompt, options, correct_index, chosen_index, answered_at, created_at)
+SELECT id, study_session_id, word_id, direction, prompt, options, correct_index, chosen_index, answered_at, created_at FROM quiz_questions;
+DROP TABLE quiz_questions;
+ALTER TABLE quiz_questions_new RENAME TO quiz_questions;
+CREATE INDEX idx_quiz_questions_session ON quiz_questions(study_session_id);
+CREATE INDEX idx_quiz_questions_word_id ON quiz_questions(word_id);
+
+CREATE TABLE word_schedules_new (
+    word_id INTEGER PRIMARY KEY REFERENCES words(id) ON DELETE CASCADE,
+    repetitions INTEGER NOT NULL DEFAULT 0,
+    interval_days REAL NOT NULL DEFAULT 0,
+    ease REAL NOT NULL DEFAULT 2.5,
+    due_at DATETIME NOT NULL,
+    last_reviewed_at DATETIME NOT NULL
+);
+INSERT INTO word_schedules_new (word_id, repetitions, interval_days, ease, due_at, last_reviewed_at)
+SELECT word_id, repetitions, interval_days, ease, due_at, last_reviewed_at FROM word_schedules;
+DROP TABLE word_schedules;
+ALTER TABLE word_schedules_new RENAME TO word_schedules;
+CREATE INDEX idx_word_schedules_due_at ON word_schedules(due_at);
+
+CREATE TABLE session_queues_new (
+    study_session_id INTEGER PRIMARY KEY REFERENCES study_sessions(id) ON DELETE CASCADE,
+    strategy TEXT NOT NULL,
+    new_ratio REAL NOT NULL,
+    no_repeat INTEGER NOT NULL,
+    current_word_id INTEGER REFERENCES words(id) ON DELETE SET NULL,
+    current_reason TEXT,
+    served_after_review_id INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO session_queues_new (study_session_id, strategy, new_ratio, no_repeat, current_word_id, current_reason, served_after_review_id, updated_at)
+SELECT study_session_id, strategy, new_ratio, no_repeat, current_word_id, current_reason, served_after_review_id, updated_at FROM session_queues;
+DROP TABLE session_queues;
+ALTER TABLE session_queues_new RENAME TO session_queues;
+
+CREATE TABLE session_queue_items_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    study_session_id INTEGER NOT NULL REFERENCES study_sessions(id) ON DELETE CASCADE,
+    word_id INTEGER NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO session_queue_items_new (id, study_session_id, word_id, reason, created_at)
+SELECT id, study_session_id, word_id, reason, created_at FROM session_queue_items;
+DROP TABLE session_queue_items;
+ALTER TABLE session_queue_items_new RENAME TO session_queue_items;
+CREATE INDEX idx_session_queue_items_session ON session_queue_items(study_session_id);
+CREATE INDEX idx_session_queue_items_word_id ON session_queue_items(word_id);
+
+CREATE TABLE word_mastery_new (
+    word_id INTEGER PRIMARY KEY REFERENCES words(id) ON DELETE CASCADE,
+    state TEXT NOT NULL,
+    streak INTEGER NOT NULL DEFAULT 0,
+    lapses INTEGER NOT NULL DEFAULT 0,
+    correct_count INTEGER NOT NULL DEFAULT 0,
+    wrong_count INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO word_mastery_new (word_id, state, streak, lapses, correct_count, wrong_count, updated_at)
+SELECT word_id, state, streak, lapses, correct_count, wrong_count, updated_at FROM word_mastery;
+DROP TABLE word_mastery;
+ALTER TABLE word_mastery_new RENAME TO word_mastery;
+CREATE INDEX idx_word_mastery_state ON word_mastery(state);
+
+CREATE TABLE word_flags_new (
+    word_id INTEGER PRIMARY KEY REFERENCES words(id) ON DELETE CASCADE,
+    leech BOOLEAN NOT NULL DEFAULT 0,
+    suspended BOOLEAN NOT NULL DEFAULT 0,
+    leech_at DATETIME,
+    suspended_at DATETIME
+);
+INSERT INTO word_flags_new (word_id, leech, suspended, leech_at, suspended_at)
+SELECT word_id, leech, suspended, leech_at, suspended_at FROM word_flags;
+DROP TABLE word_flags;
+ALTER TABLE word_flags_new RENAME TO word_flags;
+CREATE INDEX idx_word_flags_suspended ON word_flags(suspended);
+CREATE INDEX idx_word_flags_leech ON word_flags(leech);
+
+CREATE TABLE word_stats_new (
+    word_id INTEGER PRIMARY KEY REFERENCES words(id) ON DELETE CASCADE,
+    correct_count INTEGER NOT NULL DEFAULT 0,
+    wrong_count INTEGER NOT NULL DEFAULT 0,
+    last_reviewed_at DATETIME,
+    streak INTEGER NOT NULL DEFAULT 0, -- correct answers since the last wrong one
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO word_stats_new (word_id, correct_count, wrong_count, last_reviewed_at, streak, updated_at)
+SELECT word_id, correct_count, wrong_count, last_reviewed_at, streak, updated_at FROM word_stats;
+DROP TABLE word_stats;
+ALTER TABLE word_stats_new RENAME TO word_stats;
@@ -0,0 +1,208 @@
+-- Stores every timestamp the way CURRENT_TIMESTAMP writes it: UTC,
+-- "YYYY-MM-DD HH:MM:SS". Older versions also stored Go's time.Time String()
+-- ("2025-03-10 17:30:15.5 +0800 CST m=+0.01"), which SQLite's date functions
+-- can't read, and RFC 3339 or fractional seconds, which compare wrongly as text.
+--
+-- datetime() reads the standard forms and converts offsets to UTC. Go's form
+-- (it has a second space) is rewritten to "YYYY-MM-DD HH:MM:SS.fff+HH:MM"
+-- first. Values neither can read are left as they are.
+
+UPDATE words SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
+
+UPDATE groups SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
+
+UPDATE words_groups SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
+
+UPDATE study_activities SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
+
+UPDATE study_sessions SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
+
+UPDATE word_review_items SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
+
+UPDATE sentences SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
+
+UPDATE sentence_words SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
+
+UPDATE sentence_hints SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
+
+UPDATE word_examples SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
+
+UPDATE quiz_questions SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
+
+UPDATE quiz_questions SET answered_at = COALESCE(CASE
+    WHEN answered_at LIKE '% % %' THEN datetime(
+            substr(answered_at, 1, 11) || substr(substr(answered_at, 12), 1, instr(substr(answered_at, 12), ' ') - 1)
+            || substr(substr(substr(answered_at, 12), instr(substr(answered_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(answered_at, 12), instr(substr(answered_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(answered_at)
+END, answered_at)
+WHERE answered_at IS NOT NULL AND datetime(answered_at) IS NOT answered_at;
+
+UPDATE word_schedules SET due_at = COALESCE(CASE
+    WHEN due_at LIKE '% % %' THEN datetime(
+            substr(due_at, 1, 11) || substr(substr(due_at, 12), 1, instr(substr(due_at, 12), ' ') - 1)
+            || substr(substr(substr(due_at, 12), instr(substr(due_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(due_at, 12), instr(substr(due_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(due_at)
+END, due_at)
+WHERE due_at IS NOT NULL AND datetime(due_at) IS NOT due_at;
+
+UPDATE word_schedules SET last_reviewed_at = COALESCE(CASE
+    WHEN last_reviewed_at LIKE '% % %' THEN datetime(
+            substr(last_reviewed_at, 1, 11) || substr(substr(last_reviewed_at, 12), 1, instr(substr(last_reviewed_at, 12), ' ') - 1)
+            || substr(substr(substr(last_reviewed_at, 12), instr(substr(last_reviewed_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(last_reviewed_at, 12), instr(substr(last_reviewed_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(last_reviewed_at)
+END, last_reviewed_at)
+WHERE last_reviewed_at IS NOT NULL AND datetime(last_reviewed_at) IS NOT last_reviewed_at;
+
+UPDATE session_queues SET updated_at = COALESCE(CASE
+    WHEN updated_at LIKE '% % %' THEN datetime(
+            substr(updated_at, 1, 11) || substr(substr(updated_at, 12), 1, instr(substr(updated_at, 12), ' ') - 1)
+            || substr(substr(substr(updated_at, 12), instr(substr(updated_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(updated_at, 12), instr(substr(updated_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(updated_at)
+END, updated_at)
+WHERE updated_at IS NOT NULL AND datetime(updated_at) IS NOT updated_at;
+
+UPDATE session_queue_items SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
+
+UPDATE word_mastery SET updated_at = COALESCE(CASE
+    WHEN updated_at LIKE '% % %' THEN datetime(
+            substr(updated_at, 1, 11) || substr(substr(updated_at, 12), 1, instr(substr(updated_at, 12), ' ') - 1)
+            || substr(substr(substr(updated_at, 12), instr(substr(updated_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(updated_at, 12), instr(substr(updated_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(updated_at)
+END, updated_at)
+WHERE updated_at IS NOT NULL AND datetime(updated_at) IS NOT updated_at;
+
+UPDATE word_flags SET leech_at = COALESCE(CASE
+    WHEN leech_at LIKE '% % %' THEN datetime(
+            substr(leech_at, 1, 11) || substr(substr(leech_at, 12), 1, instr(substr(leech_at, 12), ' ') - 1)
+            || substr(substr(substr(leech_at, 12), instr(substr(leech_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(leech_at, 12), instr(substr(leech_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(leech_at)
+END, leech_at)
+WHERE leech_at IS NOT NULL AND datetime(leech_at) IS NOT leech_at;
+
+UPDATE word_flags SET suspended_at = COALESCE(CASE
+    WHEN suspended_at LIKE '% % %' THEN datetime(
+            substr(suspended_at, 1, 11) || substr(substr(suspended_at, 12), 1, instr(substr(suspended_at, 12), ' ') - 1)
+            || substr(substr(substr(suspended_at, 12), instr(substr(suspended_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(suspended_at, 12), instr(substr(suspended_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(suspended_at)
+END, suspended_at)
+WHERE suspended_at IS NOT NULL AND datetime(suspended_at) IS NOT suspended_at;
+
+UPDATE word_stats SET last_reviewed_at = COALESCE(CASE
+    WHEN last_reviewed_at LIKE '% % %' THEN datetime(
+            substr(last_reviewed_at, 1, 11) || substr(substr(last_reviewed_at, 12), 1, instr(substr(last_reviewed_at, 12), ' ') - 1)
+            || substr(substr(substr(last_reviewed_at, 12), instr(substr(last_reviewed_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(last_reviewed_at, 12), instr(substr(last_reviewed_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(last_reviewed_at)
+END, last_reviewed_at)
+WHERE last_reviewed_at IS NOT NULL AND datetime(last_reviewed_at) IS NOT last_reviewed_at;
+
+UPDATE word_stats SET updated_at = COALESCE(CASE
+    WHEN updated_at LIKE '% % %' THEN datetime(
+            substr(updated_at, 1, 11) || substr(substr(updated_at, 12), 1, instr(substr(updated_at, 12), ' ') - 1)
+            || substr(substr(substr(updated_at, 12), instr(substr(updated_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(updated_at, 12), instr(substr(updated_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(updated_at)
+END, updated_at)
+WHERE updated_at IS NOT NULL AND datetime(updated_at) IS NOT updated_at;
+
+UPDATE settings SET updated_at = COALESCE(CASE
+    WHEN updated_at LIKE '% % %' THEN datetime(
+            substr(updated_at, 1, 11) || substr(substr(updated_at, 12), 1, instr(substr(updated_at, 12), ' ') - 1)
+            || substr(substr(substr(updated_at, 12), instr(substr(updated_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(updated_at, 12), instr(substr(updated_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(updated_at)
+END, updated_at)
+WHERE updated_at IS NOT NULL AND datetime(updated_at) IS NOT updated_at;
+
+UPDATE goals SET updated_at = COALESCE(CASE
+    WHEN updated_at LIKE '% % %' THEN datetime(
+            substr(updated_at, 1, 11) || substr(substr(updated_at, 12), 1, instr(substr(updated_at, 12), ' ') - 1)
+            || substr(substr(substr(updated_at, 12), instr(substr(updated_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(updated_at, 12), instr(substr(updated_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(updated_at)
+END, updated_at)
+WHERE updated_at IS NOT NULL AND datetime(updated_at) IS NOT updated_at;
+
+UPDATE streak_state SET updated_at = COALESCE(CASE
+    WHEN updated_at LIKE '% % %' THEN datetime(
+            substr(updated_at, 1, 11) || substr(substr(updated_at, 12), 1, instr(substr(updated_at, 12), ' ') - 1)
+            || substr(substr(substr(updated_at, 12), instr(substr(updated_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(updated_at, 12), instr(substr(updated_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(updated_at)
+END, updated_at)
+WHERE updated_at IS NOT NULL AND datetime(updated_at) IS NOT updated_at;
+
+UPDATE streak_freezes SET created_at = COALESCE(CASE
+    WHEN created_at LIKE '% % %' THEN datetime(
+            substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1)
+            || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 1, 3) || ':' || substr(substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1), 4, 2))
+    ELSE datetime(created_at)
+END, created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT created_at;
@@ -0,0 +1,17 @@
+-- Audit trail of reviews undone or re-graded after they were recorded.
+-- review_id has no foreign key: an undone review is deleted, and its
+-- snapshot here is all that remains of it.
+
+CREATE TABLE IF NOT EXISTS review_audit (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    review_id INTEGER NOT NULL,
+    study_session_id INTEGER NOT NULL REFERENCES study_sessions(id) ON DELETE CASCADE,
+    word_id INTEGER NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    action TEXT NOT NULL CHECK (action IN ('undo', 'regrade')),
+    before TEXT NOT NULL, -- the review as JSON before the change
+    after TEXT,           -- the review as JSON after it; NULL when undone
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_audit_review ON review_audit(review_id);
+CREATE INDEX IF NOT EXISTS idx_review_audit_session ON review_audit(study_session_id);
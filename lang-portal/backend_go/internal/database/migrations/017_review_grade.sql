@@ -0,0 +1,4 @@
+-- How well the learner recalled the word (0-5, 3 and up is a pass), when the
+-- activity grades answers more finely than right or wrong
+
+ALTER TABLE word_review_items ADD COLUMN grade INTEGER CHECK (grade BETWEEN 0 AND 5);
@@ -1,6 +1,7 @@
 package migrations
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"io/ioutil"
@@ -75,55 +76,137 @@ func (m *Manager) getAppliedMigrations() (map[string]bool, error) {
 	return applied, rows.Err()
 }
 
-// Migrate runs all pending migrations
-func (m *Manager) Migrate() error {
+// pendingMigrations returns the migration files not applied yet, in order
+func (m *Manager) pendingMigrations() ([]string, error) {
 	if err := m.Initialize(); err != nil {
-		return fmt.Errorf("failed to initialize migrations table: %w", err)
+		return nil, fmt.Errorf("failed to initialize migrations table: %w", err)
 	}
 
 	files, err := m.getMigrationFiles()
 	if err != nil {
-		return fmt.Errorf("failed to get migration files: %w", err)
+		return nil, fmt.Errorf("failed to get migration files: %w", err)
 	}
 
 	applied, err := m.getAppliedMigrations()
 	if err != nil {
-		return fmt.Errorf("failed to get applied migrations: %w", err)
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
 	}
 
+	var pending []string
 	for _, file := range files {
-		if applied[file] {
+		if !applied[file] {
+			pending = append(pending, file)
+		}
+	}
+	return pending, nil
+}
+
+// Check runs the pre-checks of pending migrations without applying anything
+// and returns the rows that would stop them
+func (m *Manager) Check() ([]Violation, error) {
+	pending, err := m.pendingMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []Violation
+	for _, file := range pending {
+		check, ok := prechecks[file]
+		if !ok {
 			continue
 		}
+		found, err := check(context.Background(), m.db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pre-check migration %s: %w", file, err)
+		}
+		violations = append(violations, found...)
+	}
+	return violations, nil
+}
+
+// Migrate runs all pending migrations. A migration whose pre-check finds
+// rows that would break it is not applied, and neither are the ones after it.
+func (m *Manager) Migrate() error {
+	pending, err := m.pendingMigrations()
+	if err != nil {
+		return err
+	}
 
+	for _, file := range pending {
 		log.Printf("Applying migration: %s", file)
-		
+
 		content, err := ioutil.ReadFile(filepath.Join(m.path, file))
 		if err != nil {
 			return fmt.Errorf("failed to read migration file %s: %w", file, err)
 		}
 
-		tx, err := m.db.Begin()
-		if err != nil {
-			return fmt.Errorf("failed to begin transaction: %w", err)
+		if check, ok := prechecks[file]; ok {
+			violations, err := check(context.Background(), m.db)
+			if err != nil {
+				return fmt.Errorf("failed to pre-check migration %s: %w", file, err)
+			}
+			if len(violations) > 0 {
+				return &ViolationError{Migration: file, Violations: violations}
+			}
 		}
 
-		if _, err := tx.Exec(string(content)); err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to execute migration %s: %w", file, err)
+		if err := m.apply(context.Background(), file, string(content)); err != nil {
+			return err
 		}
 
-		if _, err := tx.Exec("INSERT INTO migrations (name) VALUES (?)", file); err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to record migration %s: %w", file, err)
-		}
+		log.Printf("Successfully applied migration: %s", file)
+	}
 
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("failed to commit migration %s: %w", file, err)
-		}
+	return nil
+}
 
-		log.Printf("Successfully applied migration: %s", file)
+// apply runs one migration in a transaction. Foreign keys are off while it
+// runs, so tables can be rebuilt (create, copy, drop, rename) as SQLite
+// recommends, and are checked before the transaction commits.
+func (m *Manager) apply(ctx context.Context, file, content string) error {
+	// PRAGMA foreign_keys only applies to its own connection, and only outside a transaction
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
 	}
+	defer conn.Close()
 
+	var fk int
+	if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&fk); err != nil {
+		return fmt.Errorf("failed to read foreign_keys: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	defer conn.ExecContext(ctx, fmt.Sprintf("PRAGMA foreign_keys = %d", fk))
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, content); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to execute migration %s: %w", file, err)
+	}
+
+	violations, err := foreignKeyViolations(ctx, tx)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to check foreign keys after migration %s: %w", file, err)
+	}
+	if len(violations) > 0 {
+		tx.Rollback()
+		return &ViolationError{Migration: file, Violations: violations}
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO migrations (name) VALUES (?)", file); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record migration %s: %w", file, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", file, err)
+	}
 	return nil
 }
@@ -0,0 +1,205 @@
+package migrations
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lang-portal/internal/database"
+)
+
+// migrateBefore opens an in-memory database migrated up to, but not
+// including, the migration named last, and returns it with a directory
+// holding those migrations
+func migrateBefore(t *testing.T, last string) (*sql.DB, string) {
+	t.Helper()
+	files, err := filepath.Glob("*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for _, f := range files {
+		if f >= last {
+			continue
+		}
+		copyMigration(t, f, dir)
+	}
+
+	db, err := database.Open(database.Config{DBPath: ":memory:"})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := NewManager(db.Writer, dir).Migrate(); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db.Writer, dir
+}
+
+func copyMigration(t *testing.T, name, dir string) {
+	t.Helper()
+	content, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exec(t *testing.T, db *sql.DB, query string, args ...interface{}) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+}
+
+func TestSchemaHardeningPrecheckBlocksBadRows(t *testing.T) {
+	db, dir := migrateBefore(t, "014_schema_hardening.sql")
+	exec(t, db, "INSERT INTO words (id, chinese, english) VALUES (1, '一', 'one')")
+	exec(t, db, "INSERT INTO groups (id, name) VALUES (1, 'Numbers')")
+	// The old schema lets these in with foreign keys off
+	exec(t, db, "PRAGMA foreign_keys = OFF")
+	exec(t, db, "INSERT INTO words_groups (id, word_id, group_id) VALUES (1, 1, 1), (2, 1, 1)")
+	exec(t, db, "INSERT INTO study_sessions (id, group_id, study_activity_id) VALUES (1, 99, 1)")
+	exec(t, db, "PRAGMA foreign_keys = ON")
+	copyMigration(t, "014_schema_hardening.sql", dir)
+
+	m := NewManager(db, dir)
+	violations, err := m.Check()
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	want := []string{
+		"study_sessions row 1: references a missing groups row",
+		"words_groups row 2: word 1 is already in group 1",
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("Check() = %v, want %v", violations, want)
+	}
+	for i, v := range violations {
+		if v.String() != want[i] {
+			t.Errorf("violation %d = %q, want %q", i, v, want[i])
+		}
+	}
+
+	err = m.Migrate()
+	var verr *ViolationError
+	if !errors.As(err, &verr) || verr.Migration != "014_schema_hardening.sql" || len(verr.Violations) != 2 {
+		t.Fatalf("Migrate() error = %v, want a ViolationError for 014 with 2 rows", err)
+	}
+	if !strings.Contains(err.Error(), want[1]) {
+		t.Errorf("Migrate() error = %q, want it to list %q", err, want[1])
+	}
+	var applied int
+	if err := db.QueryRow("SELECT COUNT(*) FROM migrations WHERE name = '014_schema_hardening.sql'").Scan(&applied); err != nil || applied != 0 {
+		t.Errorf("014 recorded %d times (%v), want 0", applied, err)
+	}
+
+	// Once the rows are fixed the migration goes through
+	exec(t, db, "DELETE FROM words_groups WHERE id = 2")
+	exec(t, db, "DELETE FROM study_sessions WHERE id = 1")
+	if err := m.Migrate(); err != nil {
+		t.Fatalf("Migrate() after fixing rows error = %v", err)
+	}
+}
+
+func TestSchemaHardeningConstraints(t *testing.T) {
+	db, dir := migrateBefore(t, "014_schema_hardening.sql")
+	exec(t, db, "INSERT INTO words (id, chinese, english) VALUES (1, '一', 'one'), (2, '二', 'two')")
+	exec(t, db, "INSERT INTO groups (id, name) VALUES (1, 'Numbers'), (2, 'Colours')")
+	exec(t, db, "INSERT INTO words_groups (word_id, group_id) VALUES (1, 1), (2, 1), (1, 2)")
+	exec(t, db, "INSERT INTO study_sessions (id, group_id, study_activity_id) VALUES (1, 1, 1), (2, 2, 3)")
+	exec(t, db, "INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES (1, 1, 1), (2, 1, 0), (1, 2, 1)")
+	copyMigration(t, "014_schema_hardening.sql", dir)
+	if err := NewManager(db, dir).Migrate(); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	var name string
+	if err := db.QueryRow("SELECT name FROM study_activities WHERE id = 1").Scan(&name); err != nil || name != "Vocabulary Quiz" {
+		t.Errorf("activity 1 = %q (%v), want Vocabulary Quiz", name, err)
+	}
+	// Activities sessions already used get a placeholder so their sessions stay valid
+	if err := db.QueryRow("SELECT name FROM study_activities WHERE id = 3").Scan(&name); err != nil || name != "Study activity 3" {
+		t.Errorf("activity 3 = %q (%v), want a placeholder", name, err)
+	}
+
+	if _, err := db.Exec("INSERT INTO words_groups (word_id, group_id) VALUES (1, 1)"); err == nil {
+		t.Error("adding a word to a group twice succeeded")
+	}
+	if _, err := db.Exec("INSERT INTO study_sessions (group_id, study_activity_id) VALUES (1, 99)"); err == nil {
+		t.Error("starting a session of a missing activity succeeded")
+	}
+	if _, err := db.Exec("DELETE FROM study_activities WHERE id = 3"); err == nil {
+		t.Error("deleting an activity with sessions succeeded")
+	}
+
+	count := func(query string) int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(query).Scan(&n); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		return n
+	}
+	// Deleting a group takes its memberships, sessions and their reviews with it
+	exec(t, db, "DELETE FROM groups WHERE id = 1")
+	if n := count("SELECT COUNT(*) FROM words_groups"); n != 1 {
+		t.Errorf("words_groups rows = %d, want 1", n)
+	}
+	if n := count("SELECT COUNT(*) FROM study_sessions"); n != 1 {
+		t.Errorf("study_sessions rows = %d, want 1", n)
+	}
+	if n := count("SELECT COUNT(*) FROM word_review_items"); n != 1 {
+		t.Errorf("word_review_items rows = %d, want 1", n)
+	}
+	// Deleting a word takes its reviews and memberships
+	exec(t, db, "DELETE FROM words WHERE id = 1")
+	if n := count("SELECT COUNT(*) FROM words_groups") + count("SELECT COUNT(*) FROM word_review_items"); n != 0 {
+		t.Errorf("%d rows still reference word 1, want 0", n)
+	}
+}
+
+func TestNormalizeTimestamps(t *testing.T) {
+	db, dir := migrateBefore(t, "015_normalize_timestamps.sql")
+	exec(t, db, `INSERT INTO words (id, chinese, english, created_at) VALUES
+		(1, '一', 'one', '2025-03-10 09:30:15'),
+		(2, '二', 'two', '2025-03-10 17:30:15.25 +0800 CST m=+0.012'),
+		(3, '三', 'three', '2025-03-10T04:30:15-05:00'),
+		(4, '四', 'four', '2025-03-10 09:30:15.999'),
+		(5, '五', 'five', 'not a time')`)
+	exec(t, db, "INSERT INTO word_schedules (word_id, repetitions, interval_days, ease, due_at, last_reviewed_at) VALUES (1, 1, 1, 2.5, '2025-03-11 09:30:15 +0000 UTC', '2025-03-10T09:30:15Z')")
+	copyMigration(t, "015_normalize_timestamps.sql", dir)
+	if err := NewManager(db, dir).Migrate(); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	want := map[int]string{1: "2025-03-10 09:30:15", 2: "2025-03-10 09:30:15", 3: "2025-03-10 09:30:15", 4: "2025-03-10 09:30:15", 5: "not a time"}
+	rows, err := db.Query("SELECT id, CAST(created_at AS TEXT) FROM words ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var got string
+		if err := rows.Scan(&id, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want[id] {
+			t.Errorf("word %d created_at = %q, want %q", id, got, want[id])
+		}
+	}
+
+	var due, last string
+	if err := db.QueryRow("SELECT CAST(due_at AS TEXT), CAST(last_reviewed_at AS TEXT) FROM word_schedules").Scan(&due, &last); err != nil {
+		t.Fatal(err)
+	}
+	if due != "2025-03-11 09:30:15" || last != "2025-03-10 09:30:15" {
+		t.Errorf("schedule due %q, last reviewed %q; want 2025-03-11 09:30:15 and 2025-03-10 09:30:15", due, last)
+	}
+}
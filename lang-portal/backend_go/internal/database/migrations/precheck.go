@@ -0,0 +1,134 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Violation is a row that breaks, or would break, a constraint
+type Violation struct {
+	Table   string `json:"table"`
+	RowID   int64  `json:"row_id"`
+	Problem string `json:"problem"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s row %d: %s", v.Table, v.RowID, v.Problem)
+}
+
+// ViolationError is returned when rows stop a migration from being applied
+type ViolationError struct {
+	Migration  string
+	Violations []Violation
+}
+
+func (e *ViolationError) Error() string {
+	lines := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		lines[i] = "  " + v.String()
+	}
+	return fmt.Sprintf("migration %s blocked by %d row(s) breaking its constraints:\n%s",
+		e.Migration, len(e.Violations), strings.Join(lines, "\n"))
+}
+
+// querier is the part of *sql.DB and *sql.Tx the checks need
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// precheck finds rows that would make a migration fail
+type precheck func(ctx context.Context, q querier) ([]Violation, error)
+
+// prechecks holds the pre-check of each migration that adds constraints
+var prechecks = map[string]precheck{
+	"014_schema_hardening.sql": checkSchemaHardening,
+}
+
+// checkSchemaHardening reports rows that reference missing rows, which the
+// rebuilt tables would reject, and words listed in a group more than once
+func checkSchemaHardening(ctx context.Context, q querier) ([]Violation, error) {
+	violations, err := foreignKeyViolations(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	// study_activities is replaced wholesale, so its old rows don't matter
+	kept := violations[:0]
+	for _, v := range violations {
+		if v.Table != "study_activities" {
+			kept = append(kept, v)
+		}
+	}
+	violations = kept
+
+	// On a new database the earlier migrations have not made the table yet
+	exists, err := tableExists(ctx, q, "words_groups")
+	if err != nil || !exists {
+		return violations, err
+	}
+	rows, err := q.QueryContext(ctx, `
+		SELECT wg.id, wg.word_id, wg.group_id
+		FROM words_groups wg
+		WHERE wg.id NOT IN (SELECT MIN(id) FROM words_groups GROUP BY word_id, group_id)
+		ORDER BY wg.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate group words: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, wordID, groupID int64
+		if err := rows.Scan(&id, &wordID, &groupID); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate group word: %w", err)
+		}
+		violations = append(violations, Violation{
+			Table:   "words_groups",
+			RowID:   id,
+			Problem: fmt.Sprintf("word %d is already in group %d", wordID, groupID),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating duplicate group words: %w", err)
+	}
+	return violations, nil
+}
+
+// tableExists reports whether the database has a table called name
+func tableExists(ctx context.Context, q querier, name string) (bool, error) {
+	rows, err := q.QueryContext(ctx, "SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?", name)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up table %s: %w", name, err)
+	}
+	defer rows.Close()
+	return rows.Next(), rows.Err()
+}
+
+// foreignKeyViolations lists rows whose declared references point at missing
+// rows, whether or not foreign keys are being enforced
+func foreignKeyViolations(ctx context.Context, q querier) ([]Violation, error) {
+	rows, err := q.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var violations []Violation
+	for rows.Next() {
+		var table, parent string
+		var rowID sql.NullInt64
+		var fkID int
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return nil, fmt.Errorf("failed to scan foreign key violation: %w", err)
+		}
+		violations = append(violations, Violation{
+			Table:   table,
+			RowID:   rowID.Int64,
+			Problem: fmt.Sprintf("references a missing %s row", parent),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating foreign key violations: %w", err)
+	}
+	return violations, nil
+}
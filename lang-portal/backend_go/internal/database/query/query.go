@@ -1,10 +1,17 @@
 package query
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 )
 
+// Querier is satisfied by *sql.DB and *sql.Tx
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 // QueryBuilder helps build SQL queries
 type QueryBuilder struct {
 	base    string
@@ -88,17 +95,17 @@ func (q *QueryBuilder) Count() (string, []interface{}) {
 }
 
 // Execute runs the query and returns rows
-func (q *QueryBuilder) Execute(db *sql.DB) (*sql.Rows, error) {
+func (q *QueryBuilder) Execute(ctx context.Context, db Querier) (*sql.Rows, error) {
 	query, args := q.Build()
-	return db.Query(query, args...)
+	return db.QueryContext(ctx, query, args...)
 }
 
 // ExecuteCount runs the count query and returns total
-func (q *QueryBuilder) ExecuteCount(db *sql.DB) (int, error) {
+func (q *QueryBuilder) ExecuteCount(ctx context.Context, db Querier) (int, error) {
 	query, args := q.Count()
 
 	var count int
-	err := db.QueryRow(query, args...).Scan(&count)
+	err := db.QueryRowContext(ctx, query, args...).Scan(&count)
 	return count, err
 }
 
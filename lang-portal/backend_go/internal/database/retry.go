@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	// retryAttempts is how many times a busy operation is tried in total
+	retryAttempts = 6
+	// retryBackoff is the first delay between attempts; it doubles each time
+	retryBackoff = 20 * time.Millisecond
+)
+
+// IsBusy reports whether err is SQLite reporting a locked database. With
+// busy_timeout set this only happens once the timeout has run out, or
+// straight away when waiting could deadlock.
+func IsBusy(err error) bool {
+	var serr *sqlite.Error
+	if !errors.As(err, &serr) {
+		return false
+	}
+	switch serr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return true
+	}
+	return false
+}
+
+// RetryBusy runs fn, running it again with exponential backoff while it fails
+// because the database is busy. It gives up when ctx is done.
+func RetryBusy(ctx context.Context, fn func() error) error {
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !IsBusy(err) || attempt == retryAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
@@ -1,46 +1,81 @@
 package seeds
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+
+	"lang-portal/internal/database"
 )
 
 // Seeder handles database seeding
 type Seeder struct {
-	db   *sql.DB
+	tx   *database.TxRunner
 	path string
 }
 
 // NewSeeder creates a new seeder
 func NewSeeder(db *sql.DB, seedsPath string) *Seeder {
 	return &Seeder{
-		db:   db,
+		tx:   database.NewTxRunner(db),
 		path: seedsPath,
 	}
 }
 
 // SeedData represents seed data configuration
 type SeedData struct {
-	Groups []struct {
-		Name  string `json:"name"`
-		Words []struct {
-			Chinese string                 `json:"chinese"`
-			English string                 `json:"english"`
-			Parts   map[string]interface{} `json:"parts"`
-		} `json:"words"`
-	} `json:"groups"`
+	Groups []SeedGroup `json:"groups"`
+}
+
+// SeedGroup is a group of words with optional example sentences
+type SeedGroup struct {
+	Name      string         `json:"name"`
+	Words     []SeedWord     `json:"words"`
+	Sentences []SeedSentence `json:"sentences"`
+}
+
+// SeedWord is a single vocabulary word
+type SeedWord struct {
+	Chinese  string                 `json:"chinese"`
+	English  string                 `json:"english"`
+	Parts    map[string]interface{} `json:"parts"`
+	Examples []SeedExample          `json:"examples"`
+}
+
+// SeedExample is an example sentence for a word
+type SeedExample struct {
+	Sentence    string `json:"sentence"`
+	Translation string `json:"translation"`
+	Source      string `json:"source"`
 }
 
-// Seed runs the database seeding process
-func (s *Seeder) Seed() error {
+// SeedSentence is a reference sentence and the group words it uses, in order.
+// Roles optionally name the slot of each word, e.g. "subject" or "verb".
+type SeedSentence struct {
+	Chinese string   `json:"chinese"`
+	English string   `json:"english"`
+	Words   []string `json:"words"`
+	Roles   []string `json:"roles"`
+}
+
+// Seed runs the database seeding process. All files are seeded in one
+// transaction, so a bad file leaves the database as it was.
+func (s *Seeder) Seed(ctx context.Context) error {
 	files, err := ioutil.ReadDir(s.path)
 	if err != nil {
 		return fmt.Errorf("failed to read seeds directory: %w", err)
 	}
 
+	return s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		return s.seedFiles(ctx, tx, files)
+	})
+}
+
+func (s *Seeder) seedFiles(ctx context.Context, tx database.DBTX, files []os.FileInfo) error {
 	for _, file := range files {
 		if filepath.Ext(file.Name()) != ".json" {
 			continue
@@ -56,7 +91,7 @@ func (s *Seeder) Seed() error {
 			return fmt.Errorf("failed to parse seed file %s: %w", file.Name(), err)
 		}
 
-		if err := s.seedGroups(data.Groups); err != nil {
+		if err := s.seedGroups(ctx, tx, data.Groups); err != nil {
 			return fmt.Errorf("failed to seed groups from %s: %w", file.Name(), err)
 		}
 	}
@@ -64,18 +99,11 @@ func (s *Seeder) Seed() error {
 	return nil
 }
 
-func (s *Seeder) seedGroups(groups []struct {
-	Name  string `json:"name"`
-	Words []struct {
-		Chinese string                 `json:"chinese"`
-		English string                 `json:"english"`
-		Parts   map[string]interface{} `json:"parts"`
-	} `json:"words"`
-}) error {
+func (s *Seeder) seedGroups(ctx context.Context, tx database.DBTX, groups []SeedGroup) error {
 	for _, group := range groups {
 		// Insert group
 		var groupID int64
-		err := s.db.QueryRow(
+		err := tx.QueryRowContext(ctx,
 			"INSERT INTO groups (name) VALUES (?) RETURNING id",
 			group.Name,
 		).Scan(&groupID)
@@ -84,6 +112,7 @@ func (s *Seeder) seedGroups(groups []struct {
 		}
 
 		// Insert words and create word-group relationships
+		wordIDs := make(map[string]int64, len(group.Words))
 		for _, word := range group.Words {
 			parts, err := json.Marshal(word.Parts)
 			if err != nil {
@@ -91,21 +120,69 @@ func (s *Seeder) seedGroups(groups []struct {
 			}
 
 			var wordID int64
-			err = s.db.QueryRow(
+			err = tx.QueryRowContext(ctx,
 				"INSERT INTO words (chinese, english, parts) VALUES (?, ?, ?) RETURNING id",
 				word.Chinese, word.English, parts,
 			).Scan(&wordID)
 			if err != nil {
 				return fmt.Errorf("failed to insert word %s: %w", word.Chinese, err)
 			}
+			wordIDs[word.Chinese] = wordID
 
-			_, err = s.db.Exec(
+			_, err = tx.ExecContext(ctx,
 				"INSERT INTO words_groups (word_id, group_id) VALUES (?, ?)",
 				wordID, groupID,
 			)
 			if err != nil {
 				return fmt.Errorf("failed to create word-group relationship for word %s: %w", word.Chinese, err)
 			}
+
+			for _, example := range word.Examples {
+				_, err = tx.ExecContext(ctx,
+					"INSERT INTO word_examples (word_id, sentence, translation, source) VALUES (?, ?, ?, ?)",
+					wordID, example.Sentence, example.Translation, example.Source,
+				)
+				if err != nil {
+					return fmt.Errorf("failed to insert example for word %s: %w", word.Chinese, err)
+				}
+			}
+		}
+
+		if err := s.seedSentences(ctx, tx, groupID, group.Sentences, wordIDs); err != nil {
+			return fmt.Errorf("failed to seed sentences for group %s: %w", group.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Seeder) seedSentences(ctx context.Context, tx database.DBTX, groupID int64, sentences []SeedSentence, wordIDs map[string]int64) error {
+	for _, sentence := range sentences {
+		var sentenceID int64
+		err := tx.QueryRowContext(ctx,
+			"INSERT INTO sentences (group_id, chinese, english) VALUES (?, ?, ?) RETURNING id",
+			groupID, sentence.Chinese, sentence.English,
+		).Scan(&sentenceID)
+		if err != nil {
+			return fmt.Errorf("failed to insert sentence %s: %w", sentence.Chinese, err)
+		}
+
+		for pos, chinese := range sentence.Words {
+			wordID, ok := wordIDs[chinese]
+			if !ok {
+				return fmt.Errorf("sentence %s uses unknown word %s", sentence.Chinese, chinese)
+			}
+			var role sql.NullString
+			if pos < len(sentence.Roles) && sentence.Roles[pos] != "" {
+				role = sql.NullString{String: sentence.Roles[pos], Valid: true}
+			}
+			_, err = tx.ExecContext(ctx,
+				"INSERT INTO sentence_words (sentence_id, word_id, position, role) VALUES (?, ?, ?, ?)",
+				sentenceID, wordID, pos, role,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to link word %s to sentence %s: %w", chinese, sentence.Chinese, err)
+			}
 		}
 	}
 
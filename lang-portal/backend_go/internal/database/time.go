@@ -0,0 +1,99 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeLayout is how timestamps are stored: UTC to the second, the format of
+// SQLite's CURRENT_TIMESTAMP, so stored values compare as text and work with
+// SQLite's date functions
+const TimeLayout = "2006-01-02 15:04:05"
+
+// timeLayouts are the formats ParseTime accepts. Besides TimeLayout they are
+// what older versions stored: the driver's default time.Time encoding
+// (t.String()), its sqlite format and RFC 3339.
+var timeLayouts = []string{
+	TimeLayout,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999",
+	time.RFC3339Nano,
+}
+
+// FormatTime returns t as it is stored
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(TimeLayout)
+}
+
+// ParseTime parses a stored timestamp into UTC. Times without a zone are UTC.
+func ParseTime(s string) (time.Time, error) {
+	// t.String() appends the monotonic clock reading of time.Now() values
+	if i := strings.Index(s, " m="); i >= 0 {
+		s = s[:i]
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse timestamp %q", s)
+}
+
+// ScanTime returns a sql.Scanner that reads a timestamp into t. A NULL
+// leaves t zero.
+func ScanTime(t *time.Time) sql.Scanner {
+	return timeScanner{dst: t}
+}
+
+// ScanNullTime returns a sql.Scanner that reads a nullable timestamp into t,
+// setting it to nil for NULL
+func ScanNullTime(t **time.Time) sql.Scanner {
+	return timeScanner{null: t}
+}
+
+type timeScanner struct {
+	dst  *time.Time
+	null **time.Time
+}
+
+// Scan accepts the time.Time the driver makes of DATETIME columns and the
+// text it returns for expressions and unparsed values
+func (s timeScanner) Scan(src interface{}) error {
+	var t time.Time
+	switch v := src.(type) {
+	case nil:
+		if s.null != nil {
+			*s.null = nil
+		} else {
+			*s.dst = time.Time{}
+		}
+		return nil
+	case time.Time:
+		t = v.UTC()
+	case string:
+		parsed, err := ParseTime(v)
+		if err != nil {
+			return err
+		}
+		t = parsed
+	case []byte:
+		parsed, err := ParseTime(string(v))
+		if err != nil {
+			return err
+		}
+		t = parsed
+	default:
+		return fmt.Errorf("cannot scan %T into a timestamp", src)
+	}
+
+	if s.null != nil {
+		*s.null = &t
+	} else {
+		*s.dst = t
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2025, 3, 10, 9, 30, 15, 0, time.UTC)
+	for _, s := range []string{
+		"2025-03-10 09:30:15",
+		"2025-03-10 09:30:15 +0000 UTC",
+		"2025-03-10 17:30:15 +0800 CST m=+0.012345678",
+		"2025-03-10 17:30:15+08:00",
+		"2025-03-10T09:30:15Z",
+		"2025-03-10T04:30:15-05:00",
+		"2025-03-10T09:30:15",
+	} {
+		got, err := ParseTime(s)
+		if err != nil || !got.Equal(want) || got.Location() != time.UTC {
+			t.Errorf("ParseTime(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseTime("yesterday"); err == nil {
+		t.Error("ParseTime(yesterday) succeeded")
+	}
+	if got := FormatTime(time.Date(2025, 3, 10, 17, 30, 15, 500, time.FixedZone("CST", 8*3600))); got != "2025-03-10 09:30:15" {
+		t.Errorf("FormatTime() = %q, want 2025-03-10 09:30:15", got)
+	}
+}
+
+func TestScanTime(t *testing.T) {
+	db := openTestDB(t)
+	// DATETIME columns come back as time.Time, expressions as text
+	for _, query := range []string{
+		"SELECT created_at FROM stamps",
+		"SELECT MAX(created_at) FROM stamps",
+	} {
+		if _, err := db.Writer.Exec("CREATE TABLE IF NOT EXISTS stamps (created_at DATETIME)"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Writer.Exec("DELETE FROM stamps; INSERT INTO stamps VALUES ('2025-03-10 17:30:15 +0800 CST')"); err != nil {
+			t.Fatal(err)
+		}
+		var got time.Time
+		if err := db.Writer.QueryRow(query).Scan(ScanTime(&got)); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		if want := time.Date(2025, 3, 10, 9, 30, 15, 0, time.UTC); !got.Equal(want) || got.Location() != time.UTC {
+			t.Errorf("%s = %v, want %v", query, got, want)
+		}
+	}
+
+	at := new(time.Time)
+	if err := db.Writer.QueryRow("SELECT NULL").Scan(ScanNullTime(&at)); err != nil || at != nil {
+		t.Errorf("ScanNullTime(NULL) = %v, %v; want nil", at, err)
+	}
+	if err := db.Writer.QueryRow("SELECT CURRENT_TIMESTAMP").Scan(ScanNullTime(&at)); err != nil || at == nil {
+		t.Errorf("ScanNullTime(CURRENT_TIMESTAMP) = %v, %v; want a time", at, err)
+	}
+}
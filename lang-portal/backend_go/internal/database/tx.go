@@ -0,0 +1,135 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DBTX is the part of *sql.DB and *sql.Tx that services query through
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// txKey is the context key of the transaction opened by TxRunner.InTx
+type txKey struct{}
+
+// txState is an open transaction and how many savepoints are nested in it
+type txState struct {
+	tx    *sql.Tx
+	depth int
+}
+
+// TxRunner runs work in transactions on a database. The transaction travels
+// in the context, so work started inside InTx (including nested InTx calls
+// from other services) joins it rather than opening a second one.
+type TxRunner struct {
+	db *sql.DB
+}
+
+// NewTxRunner creates a TxRunner that opens transactions on db
+func NewTxRunner(db *sql.DB) *TxRunner {
+	return &TxRunner{db: db}
+}
+
+// InTx runs fn in a transaction, committing if it returns nil and rolling
+// back otherwise. Called inside another InTx, fn runs in a SAVEPOINT of the
+// outer transaction instead, so its failure only undoes its own work. The
+// outermost transaction is retried if the database is busy, so fn must not
+// have side effects outside the database.
+func (r *TxRunner) InTx(ctx context.Context, fn func(ctx context.Context, tx DBTX) error) error {
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		return st.savepoint(ctx, fn)
+	}
+
+	return RetryBusy(ctx, func() (err error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				_ = tx.Rollback()
+				panic(p)
+			}
+			if err != nil {
+				_ = tx.Rollback()
+			}
+		}()
+
+		if err = fn(context.WithValue(ctx, txKey{}, &txState{tx: tx}), tx); err != nil {
+			return err
+		}
+		if err = tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	})
+}
+
+// savepoint runs fn inside a new savepoint of the transaction
+func (st *txState) savepoint(ctx context.Context, fn func(ctx context.Context, tx DBTX) error) (err error) {
+	st.depth++
+	name := fmt.Sprintf("sp_%d", st.depth)
+	defer func() { st.depth-- }()
+
+	if _, err := st.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = st.tx.ExecContext(ctx, "ROLLBACK TO "+name)
+			_, _ = st.tx.ExecContext(ctx, "RELEASE "+name)
+			panic(p)
+		}
+	}()
+
+	if err = fn(ctx, st.tx); err != nil {
+		if _, rbErr := st.tx.ExecContext(ctx, "ROLLBACK TO "+name); rbErr != nil {
+			return fmt.Errorf("error rolling back savepoint: %v (original error: %w)", rbErr, err)
+		}
+		_, _ = st.tx.ExecContext(ctx, "RELEASE "+name)
+		return err
+	}
+	if _, err = st.tx.ExecContext(ctx, "RELEASE "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+// handle is a connection pool whose queries join the context's transaction
+type handle struct {
+	db *sql.DB
+}
+
+// Handle wraps db so that queries run inside the transaction carried by
+// their context (see TxRunner.InTx), and directly on db otherwise. Services
+// query through a Handle so they can take part in a caller's transaction.
+func Handle(db *sql.DB) DBTX {
+	return handle{db: db}
+}
+
+// Executor returns the transaction carried by ctx, or q when there is none
+func Executor(ctx context.Context, q DBTX) DBTX {
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		return st.tx
+	}
+	return q
+}
+
+// ExecContext runs a statement in the context's transaction, if any
+func (h handle) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return Executor(ctx, h.db).ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs a query in the context's transaction, if any
+func (h handle) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return Executor(ctx, h.db).QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs a single-row query in the context's transaction, if any
+func (h handle) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return Executor(ctx, h.db).QueryRowContext(ctx, query, args...)
+}
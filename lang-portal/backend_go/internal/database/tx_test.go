@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func countItems(t *testing.T, ctx context.Context, q DBTX) int {
+	t.Helper()
+	var n int
+	if err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM items WHERE bucket = -1").Scan(&n); err != nil {
+		t.Fatalf("failed to count items: %v", err)
+	}
+	return n
+}
+
+func insertItem(ctx context.Context, q DBTX, value int) error {
+	_, err := q.ExecContext(ctx, "INSERT INTO items (bucket, value) VALUES (-1, ?)", value)
+	return err
+}
+
+func TestInTxNestedSavepoints(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewTxRunner(db.Writer)
+	h := Handle(db.Writer)
+	ctx := t.Context()
+	errInner := errors.New("inner failed")
+
+	err := runner.InTx(ctx, func(ctx context.Context, tx DBTX) error {
+		if err := insertItem(ctx, tx, 1); err != nil {
+			return err
+		}
+		// A failed nested call only undoes its own work
+		err := runner.InTx(ctx, func(ctx context.Context, tx DBTX) error {
+			if err := insertItem(ctx, tx, 2); err != nil {
+				return err
+			}
+			return errInner
+		})
+		if !errors.Is(err, errInner) {
+			t.Errorf("nested InTx() error = %v, want %v", err, errInner)
+		}
+		// A successful one, through a Handle, joins the outer transaction
+		if err := runner.InTx(ctx, func(ctx context.Context, _ DBTX) error {
+			return insertItem(ctx, h, 3)
+		}); err != nil {
+			return err
+		}
+		if n := countItems(t, ctx, h); n != 2 {
+			t.Errorf("inside transaction: %d items, want 2", n)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("InTx() error = %v", err)
+	}
+	if n := countItems(t, ctx, db.Writer); n != 2 {
+		t.Errorf("after commit: %d items, want 2", n)
+	}
+}
+
+func TestInTxRollsBackNestedWork(t *testing.T) {
+	db := openTestDB(t)
+	runner := NewTxRunner(db.Writer)
+	ctx := t.Context()
+	errOuter := errors.New("outer failed")
+
+	err := runner.InTx(ctx, func(ctx context.Context, tx DBTX) error {
+		if err := insertItem(ctx, tx, 1); err != nil {
+			return err
+		}
+		if err := runner.InTx(ctx, func(ctx context.Context, tx DBTX) error {
+			return insertItem(ctx, tx, 2)
+		}); err != nil {
+			return err
+		}
+		return errOuter
+	})
+	if !errors.Is(err, errOuter) {
+		t.Fatalf("InTx() error = %v, want %v", err, errOuter)
+	}
+	if n := countItems(t, ctx, db.Writer); n != 0 {
+		t.Errorf("after rollback: %d items, want 0", n)
+	}
+
+	// Outside a transaction a Handle queries the pool directly
+	if err := insertItem(ctx, Handle(db.Writer), 4); err != nil {
+		t.Fatalf("insert error = %v", err)
+	}
+	if n := countItems(t, ctx, db.Reader); n != 1 {
+		t.Errorf("reader sees %d items, want 1", n)
+	}
+}
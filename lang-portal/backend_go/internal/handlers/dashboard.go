@@ -1,43 +0,0 @@
-package handlers
-
-import (
-	"net/http"
-
-	"github.com/gin-gonic/gin"
-	"lang-portal/internal/service"
-)
-
-type DashboardHandler struct {
-	service *service.DashboardService
-}
-
-func NewDashboardHandler(service *service.DashboardService) *DashboardHandler {
-	return &DashboardHandler{service: service}
-}
-
-func (h *DashboardHandler) GetLastStudySession(c *gin.Context) {
-	session, err := h.service.GetLastStudySession()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, session)
-}
-
-func (h *DashboardHandler) GetStudyProgress(c *gin.Context) {
-	progress, err := h.service.GetStudyProgress()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, progress)
-}
-
-func (h *DashboardHandler) GetQuickStats(c *gin.Context) {
-	stats, err := h.service.GetQuickStats()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, stats)
-}
@@ -1,71 +0,0 @@
-package handlers
-
-import (
-	"net/http"
-	"strconv"
-
-	"github.com/gin-gonic/gin"
-	"lang-portal/internal/service"
-)
-
-type StudyHandler struct {
-	service *service.StudyService
-}
-
-func NewStudyHandler(service *service.StudyService) *StudyHandler {
-	return &StudyHandler{service: service}
-}
-
-func (h *StudyHandler) GetActivity(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activity ID"})
-		return
-	}
-
-	activity, err := h.service.GetActivity(id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, activity)
-}
-
-func (h *StudyHandler) GetActivitySessions(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activity ID"})
-		return
-	}
-
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	sessions, err := h.service.GetActivitySessions(id, page)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, sessions)
-}
-
-func (h *StudyHandler) CreateStudySession(c *gin.Context) {
-	var request struct {
-		GroupID         int `json:"group_id" binding:"required"`
-		StudyActivityID int `json:"study_activity_id" binding:"required"`
-	}
-
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	session, err := h.service.CreateStudySession(request.GroupID, request.StudyActivityID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusCreated, session)
-}
@@ -1,46 +0,0 @@
-package handlers
-
-import (
-	"net/http"
-	"strconv"
-
-	"github.com/gin-gonic/gin"
-	"lang-portal/internal/service"
-)
-
-type WordHandler struct {
-	service *service.WordService
-}
-
-func NewWordHandler(service *service.WordService) *WordHandler {
-	return &WordHandler{service: service}
-}
-
-func (h *WordHandler) GetWords(c *gin.Context) {
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	words, err := h.service.GetWords(page)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, words)
-}
-
-func (h *WordHandler) GetWordByID(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid word ID"})
-		return
-	}
-
-	word, err := h.service.GetWordByID(id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, word)
-}
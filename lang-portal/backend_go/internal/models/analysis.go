@@ -0,0 +1,38 @@
+package models
+
+// Coverage statuses for a token of analysed text
+const (
+	CoverageKnown      = "known"
+	CoverageInProgress = "in_progress"
+	CoverageUnknown    = "unknown"
+)
+
+// CoverageToken is a single segmented token with its mastery status
+type CoverageToken struct {
+	Text   string `json:"text"`
+	WordID int64  `json:"word_id,omitempty"`
+	Status string `json:"status"`
+}
+
+// CoverageWord is a vocabulary word found in the text that the learner does not know yet
+type CoverageWord struct {
+	ID          int64  `json:"id"`
+	Chinese     string `json:"chinese"`
+	English     string `json:"english"`
+	Occurrences int    `json:"occurrences"`
+}
+
+// CoverageReport describes how much of a text a learner can read
+type CoverageReport struct {
+	TotalTokens       int             `json:"total_tokens"`
+	KnownTokens       int             `json:"known_tokens"`
+	InProgressTokens  int             `json:"in_progress_tokens"`
+	UnknownTokens     int             `json:"unknown_tokens"`
+	KnownPercent      float64         `json:"known_percent"`
+	InProgressPercent float64         `json:"in_progress_percent"`
+	UnknownPercent    float64         `json:"unknown_percent"`
+	Tokens            []CoverageToken `json:"tokens"`
+	UnknownWords      []CoverageWord  `json:"unknown_words"`
+	UnknownWordIDs    []int64         `json:"unknown_word_ids"`
+	UnmatchedTokens   []string        `json:"unmatched_tokens"`
+}
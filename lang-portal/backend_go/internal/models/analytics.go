@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// ConfusionWord is one side of a confused word pair
+type ConfusionWord struct {
+	ID      int64  `json:"id"`
+	Chinese string `json:"chinese"`
+	English string `json:"english"`
+}
+
+// ConfusionPair is two words the learner mixed up, in either direction
+type ConfusionPair struct {
+	Words          [2]ConfusionWord `json:"words"`
+	Count          int              `json:"count"`
+	LastConfusedAt time.Time        `json:"last_confused_at"`
+}
+
+// ConfusionGroup lists the most confused pairs in a group's study sessions
+type ConfusionGroup struct {
+	GroupID   int64           `json:"group_id"`
+	GroupName string          `json:"group_name"`
+	Pairs     []ConfusionPair `json:"pairs"`
+}
+
+// Review series bucket sizes
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+// ReviewBucket is the review activity in one period of a time series
+type ReviewBucket struct {
+	// First local day of the period (YYYY-MM-DD); weeks start on Monday
+	Start    string  `json:"start"`
+	Reviews  int     `json:"reviews"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"`
+	NewWords int     `json:"new_words"`
+}
+
+// ReviewSeries is review activity over time in the learner's timezone
+type ReviewSeries struct {
+	Bucket   string         `json:"bucket"`
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+	Timezone string         `json:"timezone"`
+	Buckets  []ReviewBucket `json:"buckets"`
+}
+
+// HeatmapDay is one cell of a calendar heatmap
+type HeatmapDay struct {
+	Date    string `json:"date"`
+	Reviews int    `json:"reviews"`
+	// Intensity from 0 (no reviews) to 4 (busiest days)
+	Level int `json:"level"`
+}
+
+// Heatmap is a year of daily review counts
+type Heatmap struct {
+	Year         int          `json:"year"`
+	Timezone     string       `json:"timezone"`
+	TotalReviews int          `json:"total_reviews"`
+	ActiveDays   int          `json:"active_days"`
+	MaxReviews   int          `json:"max_reviews"`
+	Days         []HeatmapDay `json:"days"`
+}
+
+// ForecastGroup is a group's share of the forecast workload
+type ForecastGroup struct {
+	GroupID   int64  `json:"group_id"`
+	GroupName string `json:"group_name"`
+	Due       int    `json:"due"`
+}
+
+// ForecastDay is the expected workload on one future local day
+type ForecastDay struct {
+	Date string `json:"date"`
+	// Reviews of words already being studied; overdue words count today
+	Due    int             `json:"due"`
+	Groups []ForecastGroup `json:"groups"`
+	// What-if mode: hypothetical new words introduced and their follow-up reviews
+	NewWords      int `json:"new_words,omitempty"`
+	WhatIfReviews int `json:"what_if_reviews,omitempty"`
+	Total         int `json:"total"`
+}
+
+// ReviewForecast is the expected review workload for the coming days
+type ReviewForecast struct {
+	Days      int             `json:"days"`
+	Timezone  string          `json:"timezone"`
+	NewPerDay int             `json:"new_per_day"`
+	Groups    []ForecastGroup `json:"groups"`
+	Forecast  []ForecastDay   `json:"forecast"`
+}
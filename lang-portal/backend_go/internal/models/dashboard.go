@@ -2,14 +2,20 @@ package models
 
 // StudyProgress represents study progress statistics
 type StudyProgress struct {
-	TotalWordsStudied   int `json:"total_words_studied"`
-	TotalAvailableWords int `json:"total_available_words"`
+	TotalWordsStudied   int              `json:"total_words_studied"`
+	TotalAvailableWords int              `json:"total_available_words"`
+	Mastery             MasteryBreakdown `json:"mastery"`
+	Goal                *GoalProgress    `json:"goal"`
 }
 
 // QuickStats represents quick study statistics
 type QuickStats struct {
-	SuccessRate        float64 `json:"success_rate"`
-	TotalStudySessions int     `json:"total_study_sessions"`
-	TotalActiveGroups  int     `json:"total_active_groups"`
-	StudyStreakDays    int     `json:"study_streak_days"`
+	SuccessRate        float64       `json:"success_rate"`
+	TotalStudySessions int           `json:"total_study_sessions"`
+	TotalActiveGroups  int           `json:"total_active_groups"`
+	StudyStreakDays    int           `json:"study_streak_days"`
+	LongestStreakDays  int           `json:"longest_streak_days"`
+	WordsLearned       int           `json:"words_learned"`
+	WordsInProgress    int           `json:"words_in_progress"`
+	Goal               *GoalProgress `json:"goal"`
 }
@@ -0,0 +1,21 @@
+package models
+
+// DailyGoals are the learner's daily targets; zero means no target
+type DailyGoals struct {
+	Reviews  int `json:"reviews"`
+	NewWords int `json:"new_words"`
+	Minutes  int `json:"minutes"`
+}
+
+// GoalProgress reports a day's activity against the daily goals
+type GoalProgress struct {
+	Date      string     `json:"date"`
+	Timezone  string     `json:"timezone"`
+	Goals     DailyGoals `json:"goals"`
+	Reviews   int        `json:"reviews"`
+	NewWords  int        `json:"new_words"`
+	Minutes   int        `json:"minutes"`
+	Completed bool       `json:"completed"`
+	// Streak freezes earned on perfect weeks and not spent yet
+	StreakFreezes int `json:"streak_freezes"`
+}
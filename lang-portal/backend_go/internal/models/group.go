@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Group represents a thematic group of words
 type Group struct {
 	Base
@@ -12,10 +14,38 @@ type GroupWithWords struct {
 	Words []Word `json:"words"`
 }
 
+// Group statistics scopes
+const (
+	// StatsScopeGroup counts only reviews from the group's own study sessions
+	StatsScopeGroup = "group"
+	// StatsScopeAll counts every review of the group's words
+	StatsScopeAll = "all"
+)
+
 // GroupStats represents statistics for a group
 type GroupStats struct {
-	TotalWords     int     `json:"total_words"`
-	StudiedWords   int     `json:"studied_words"`
-	SuccessRate    float64 `json:"success_rate"`
-	LastStudiedAt  string  `json:"last_studied_at,omitempty"`
+	Scope         string             `json:"scope"`
+	TotalWords    int                `json:"total_words"`
+	StudiedWords  int                `json:"studied_words"`
+	TotalReviews  int                `json:"total_reviews"`
+	SuccessRate   float64            `json:"success_rate"`
+	LastStudiedAt *time.Time         `json:"last_studied_at,omitempty"`
+	DueCount      int                `json:"due_count"`
+	Mastery       MasteryBreakdown   `json:"mastery"`
+	Activities    []ActivityAccuracy `json:"activities"`
+}
+
+// ActivityAccuracy is how well a group's words were answered in one study activity
+type ActivityAccuracy struct {
+	ActivityID   int64   `json:"activity_id"`
+	ActivityName string  `json:"activity_name,omitempty"`
+	Reviews      int     `json:"reviews"`
+	Correct      int     `json:"correct"`
+	SuccessRate  float64 `json:"success_rate"`
+}
+
+// GroupWithStats combines a group with its statistics
+type GroupWithStats struct {
+	Group
+	Stats GroupStats `json:"stats"`
 }
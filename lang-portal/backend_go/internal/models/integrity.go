@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Ways an integrity issue is repaired
+const (
+	// RepairQuarantine moves the row to integrity_quarantine
+	RepairQuarantine = "quarantine"
+	// RepairRebuild recomputes the row from the review history
+	RepairRebuild = "rebuild"
+	// RepairNone means the issue needs a backup or manual work
+	RepairNone = "none"
+)
+
+// IntegrityIssue is a row, or for the SQLite check a page or index, that
+// breaks a rule
+type IntegrityIssue struct {
+	// Rule names the check that found it, e.g. "foreign_key" or "duplicate_membership"
+	Rule    string `json:"rule"`
+	Table   string `json:"table,omitempty"`
+	RowID   int64  `json:"row_id,omitempty"`
+	Problem string `json:"problem"`
+	Repair  string `json:"repair"`
+}
+
+// IntegrityReport is the result of checking, and possibly repairing, the database
+type IntegrityReport struct {
+	CheckedAt time.Time        `json:"checked_at"`
+	OK        bool             `json:"ok"`
+	Issues    []IntegrityIssue `json:"issues"`
+	// Repaired lists the issues a repair fixed; Issues then holds what is left
+	Repaired []IntegrityIssue `json:"repaired,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// Mastery states of a word, derived from its review history
+const (
+	MasteryNew       = "new"
+	MasteryLearning  = "learning"
+	MasteryReviewing = "reviewing"
+	MasteryMastered  = "mastered"
+	MasteryLapsed    = "lapsed"
+)
+
+// WordMastery is the cached mastery state of a word
+type WordMastery struct {
+	WordID       int64  `json:"word_id" db:"word_id"`
+	State        string `json:"state" db:"state"`
+	Streak       int    `json:"streak" db:"streak"`
+	Lapses       int    `json:"lapses" db:"lapses"`
+	CorrectCount int    `json:"correct_count" db:"correct_count"`
+	WrongCount   int    `json:"wrong_count" db:"wrong_count"`
+}
+
+// MasteryBreakdown counts words in each mastery state. Suspended words are
+// counted separately and left out of the states.
+type MasteryBreakdown struct {
+	New       int `json:"new"`
+	Learning  int `json:"learning"`
+	Reviewing int `json:"reviewing"`
+	Mastered  int `json:"mastered"`
+	Lapsed    int `json:"lapsed"`
+	Suspended int `json:"suspended"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// Reasons a word was chosen by the session queue
+const (
+	QueueReasonNew     = "new"
+	QueueReasonWeakest = "weakest"
+	QueueReasonDue     = "due"
+)
+
+// QueueSettings controls how a study session picks its next word
+type QueueSettings struct {
+	Strategy string  `json:"strategy"`
+	NewRatio float64 `json:"new_ratio"`
+	NoRepeat int     `json:"no_repeat"`
+}
+
+// QueueItem is the next word to study in a session
+type QueueItem struct {
+	Word     Word          `json:"word"`
+	Reason   string        `json:"reason"`
+	Position int           `json:"position"`
+	Resumed  bool          `json:"resumed"`
+	Settings QueueSettings `json:"settings"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// Quiz directions: which side of the word is shown as the prompt
+const (
+	DirectionChineseToEnglish = "zh_en"
+	DirectionEnglishToChinese = "en_zh"
+)
+
+// QuizQuestion is a multiple-choice item; the correct option is kept server-side
+type QuizQuestion struct {
+	ID             int64    `json:"id"`
+	StudySessionID int64    `json:"study_session_id"`
+	WordID         int64    `json:"word_id"`
+	Direction      string   `json:"direction"`
+	Prompt         string   `json:"prompt"`
+	Options        []string `json:"options"`
+}
+
+// QuizAnswerResult is the outcome of answering a multiple-choice item
+type QuizAnswerResult struct {
+	QuestionID   int64          `json:"question_id"`
+	Choice       int            `json:"choice"`
+	CorrectIndex int            `json:"correct_index"`
+	Correct      bool           `json:"correct"`
+	Review       WordReviewItem `json:"review"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// WordSchedule is the spaced-repetition state of a word
+type WordSchedule struct {
+	WordID         int64     `json:"word_id" db:"word_id"`
+	Repetitions    int       `json:"repetitions" db:"repetitions"`
+	IntervalDays   float64   `json:"interval_days" db:"interval_days"`
+	Ease           float64   `json:"ease" db:"ease"`
+	DueAt          time.Time `json:"due_at" db:"due_at"`
+	LastReviewedAt time.Time `json:"last_reviewed_at" db:"last_reviewed_at"`
+}
@@ -0,0 +1,70 @@
+package models
+
+// Token feedback statuses for a graded sentence
+const (
+	TokenCorrect = "correct"
+	TokenWrong   = "wrong"
+	TokenMissing = "missing"
+	TokenExtra   = "extra"
+)
+
+// Sentence represents a reference sentence for the translation exercise
+type Sentence struct {
+	Base
+	GroupID int64  `json:"group_id" db:"group_id"`
+	Chinese string `json:"chinese" db:"chinese"`
+	English string `json:"english" db:"english"`
+}
+
+// SentenceWord links a vocabulary word to its position in a sentence
+type SentenceWord struct {
+	Base
+	SentenceID int64  `json:"sentence_id" db:"sentence_id"`
+	WordID     int64  `json:"word_id" db:"word_id"`
+	Position   int    `json:"position" db:"position"`
+	Role       string `json:"role,omitempty" db:"role"`
+}
+
+// SentencePrompt is the learner-facing shape of a sentence, without the answer
+type SentencePrompt struct {
+	ID         int64  `json:"id"`
+	GroupID    int64  `json:"group_id"`
+	English    string `json:"english"`
+	TokenCount int    `json:"token_count"`
+}
+
+// TokenFeedback describes how one token of the answer lines up with the reference
+type TokenFeedback struct {
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	WordID   int64  `json:"word_id,omitempty"`
+	Status   string `json:"status"`
+}
+
+// SentenceGrade is the result of grading a submitted sentence
+type SentenceGrade struct {
+	SentenceID int64            `json:"sentence_id"`
+	Correct    bool             `json:"correct"`
+	Score      float64          `json:"score"`
+	Answer     string           `json:"answer"`
+	Reference  string           `json:"reference"`
+	HintLevel  int              `json:"hint_level"`
+	Tokens     []TokenFeedback  `json:"tokens"`
+	Reviews    []WordReviewItem `json:"reviews"`
+}
+
+// Hint levels for the sentence exercise, each revealing more than the last
+const (
+	HintLevelPattern    = 1
+	HintLevelCandidates = 2
+	HintLevelPartial    = 3
+)
+
+// SentenceHint is the help shown for a sentence at a given level
+type SentenceHint struct {
+	SentenceID    int64  `json:"sentence_id"`
+	Level         int    `json:"level"`
+	Pattern       string `json:"pattern"`
+	Candidates    []Word `json:"candidates,omitempty"`
+	PartialAnswer string `json:"partial_answer,omitempty"`
+}
@@ -0,0 +1,33 @@
+package models
+
+// Settings holds the learner's tunable preferences and thresholds
+type Settings struct {
+	// Correct answers in a row before a word moves from learning to reviewing
+	MasteryReviewingStreak int `json:"mastery_reviewing_streak"`
+	// Correct answers in a row before a word counts as mastered
+	MasteryMasteredStreak int `json:"mastery_mastered_streak"`
+	// Minimum overall success rate (0-1) for a word to count as mastered
+	MasteryMasteredAccuracy float64 `json:"mastery_mastered_accuracy"`
+	// Lapses after which a word is flagged as a leech; zero disables detection
+	LeechThreshold int `json:"leech_threshold"`
+	// Whether a word is suspended from study when it becomes a leech
+	LeechAutoSuspend bool `json:"leech_auto_suspend"`
+	// IANA timezone the learner's days are counted in, e.g. "America/Los_Angeles"
+	Timezone string `json:"timezone"`
+	// Minutes after a review is recorded during which it can be undone or
+	// re-graded; zero disables changes
+	ReviewEditWindowMinutes int `json:"review_edit_window_minutes"`
+}
+
+// DefaultSettings returns the settings used until the learner changes them
+func DefaultSettings() Settings {
+	return Settings{
+		MasteryReviewingStreak:  2,
+		MasteryMasteredStreak:   5,
+		MasteryMasteredAccuracy: 0.8,
+		LeechThreshold:          8,
+		LeechAutoSuspend:        true,
+		Timezone:                "UTC",
+		ReviewEditWindowMinutes: 10,
+	}
+}
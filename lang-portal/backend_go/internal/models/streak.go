@@ -0,0 +1,16 @@
+package models
+
+// StudyStreak summarises consecutive study days in the learner's timezone
+type StudyStreak struct {
+	// Days studied in the current run; today counts once the learner studies
+	Current int `json:"current"`
+	// Days studied in the longest run ever
+	Longest int `json:"longest"`
+	// Missed days in the current run that a streak freeze covered
+	FreezeDays int `json:"freeze_days"`
+	// Whether the learner has studied today
+	StudiedToday bool `json:"studied_today"`
+	// Last day with any study activity (YYYY-MM-DD), empty if none
+	LastStudyDate string `json:"last_study_date,omitempty"`
+	Timezone      string `json:"timezone"`
+}
@@ -1,16 +1,10 @@
 package models
 
-// StudyActivity represents a specific study activity
+// StudyActivity is an activity study sessions are started from, such as a
+// vocabulary quiz
 type StudyActivity struct {
 	Base
-	StudySessionID int64 `json:"study_session_id" db:"study_session_id"`
-	GroupID        int64 `json:"group_id" db:"group_id"`
-}
-
-// StudyActivityDetails includes additional presentation details
-type StudyActivityDetails struct {
-	StudyActivity
-	Name         string `json:"name"`
-	ThumbnailURL string `json:"thumbnail_url"`
-	Description  string `json:"description"`
+	Name         string `json:"name" db:"name"`
+	ThumbnailURL string `json:"thumbnail_url" db:"thumbnail_url"`
+	Description  string `json:"description" db:"description"`
 }
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // StudySession represents a study session
 type StudySession struct {
 	Base
@@ -23,12 +25,24 @@ type StudySessionSummary struct {
 	GroupName       string `json:"group_name"`
 }
 
+// StudySessionReviewSummary adds a session's review totals to its summary
+type StudySessionReviewSummary struct {
+	StudySessionSummary
+	ReviewItemsCount int `json:"review_items_count"`
+	CorrectCount     int `json:"correct_count"`
+	WrongCount       int `json:"wrong_count"`
+	WordsReviewed    int `json:"words_reviewed"`
+	// Time of the last review, null until the session has one
+	EndTime *time.Time `json:"end_time"`
+}
+
 // ActivitySessionListItem matches spec for study activity sessions list
 type ActivitySessionListItem struct {
-	ID               int64  `json:"id"`
-	ActivityName     string `json:"activity_name"`
-	GroupName        string `json:"group_name"`
-	StartTime        string `json:"start_time"`
-	EndTime          string `json:"end_time"`
-	ReviewItemsCount int    `json:"review_items_count"`
+	ID           int64     `json:"id"`
+	ActivityName string    `json:"activity_name"`
+	GroupName    string    `json:"group_name"`
+	StartTime    time.Time `json:"start_time"`
+	// Time of the last review, null until the session has one
+	EndTime          *time.Time `json:"end_time"`
+	ReviewItemsCount int        `json:"review_items_count"`
 }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"time"
 )
 
 // Word represents a vocabulary word in the system
@@ -14,8 +15,13 @@ type Word struct {
 
 // WordStats represents statistics for a word
 type WordStats struct {
-	CorrectCount int `json:"correct_count"`
-	WrongCount   int `json:"wrong_count"`
+	CorrectCount   int        `json:"correct_count"`
+	WrongCount     int        `json:"wrong_count"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+	Streak         int        `json:"streak"`
+	Mastery        string     `json:"mastery,omitempty"`
+	Leech          bool       `json:"leech"`
+	Suspended      bool       `json:"suspended"`
 }
 
 // WordWithStats combines Word with its statistics
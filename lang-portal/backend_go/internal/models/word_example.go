@@ -0,0 +1,27 @@
+package models
+
+// WordExample is an example sentence showing a word in use
+type WordExample struct {
+	Base
+	WordID      int64  `json:"word_id" db:"word_id"`
+	Sentence    string `json:"sentence" db:"sentence"`
+	Translation string `json:"translation" db:"translation"`
+	Source      string `json:"source" db:"source"`
+}
+
+// ClozeItem is an example sentence with its target word blanked out
+type ClozeItem struct {
+	ExampleID   int64    `json:"example_id"`
+	WordID      int64    `json:"word_id"`
+	Sentence    string   `json:"sentence"`
+	Translation string   `json:"translation"`
+	Answer      string   `json:"answer"`
+	Options     []string `json:"options"`
+}
+
+// ClozeResult is the outcome of answering a cloze item
+type ClozeResult struct {
+	Correct  bool           `json:"correct"`
+	Expected string         `json:"expected"`
+	Review   WordReviewItem `json:"review"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// WordFlags marks a word as a leech and/or suspended from study
+type WordFlags struct {
+	WordID      int64      `json:"word_id" db:"word_id"`
+	Leech       bool       `json:"leech" db:"leech"`
+	Suspended   bool       `json:"suspended" db:"suspended"`
+	LeechAt     *time.Time `json:"leech_at,omitempty" db:"leech_at"`
+	SuspendedAt *time.Time `json:"suspended_at,omitempty" db:"suspended_at"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// WordHistoryEntry is one review of a word with the schedule that followed it
+type WordHistoryEntry struct {
+	ReviewID       int64     `json:"review_id"`
+	StudySessionID int64     `json:"study_session_id"`
+	ActivityID     int64     `json:"activity_id"`
+	ActivityName   string    `json:"activity_name,omitempty"`
+	GroupID        int64     `json:"group_id"`
+	GroupName      string    `json:"group_name"`
+	CreatedAt      time.Time `json:"created_at"`
+	Correct        bool      `json:"correct"`
+	Answer         string    `json:"answer,omitempty"`
+	ResponseMs     *int      `json:"response_ms,omitempty"`
+	Grade          *int      `json:"grade,omitempty"`
+	// Days since the previous review, absent for the first one
+	ElapsedDays *float64 `json:"elapsed_days,omitempty"`
+	// Predicted chance of recall when the review happened, absent for the first one
+	RetentionAtReview *float64 `json:"retention_at_review,omitempty"`
+	// Scheduler state after the review
+	IntervalDays float64   `json:"interval_days"`
+	Ease         float64   `json:"ease"`
+	DueAt        time.Time `json:"due_at"`
+}
+
+// RetentionPoint is the predicted chance of recall some days after the last review
+type RetentionPoint struct {
+	Day       float64 `json:"day"`
+	Retention float64 `json:"retention"`
+}
+
+// WordHistory is the full review timeline of a word and its forgetting curve
+type WordHistory struct {
+	WordID  int64              `json:"word_id"`
+	Chinese string             `json:"chinese"`
+	English string             `json:"english"`
+	Reviews []WordHistoryEntry `json:"reviews"`
+	// Predicted recall now, absent if the word was never reviewed
+	CurrentRetention *float64         `json:"current_retention,omitempty"`
+	Retention        []RetentionPoint `json:"retention"`
+}
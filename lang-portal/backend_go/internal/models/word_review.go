@@ -1,17 +1,86 @@
 package models
 
+import "time"
+
 // WordReviewItem represents a word review record
 type WordReviewItem struct {
 	Base
-	WordID          int64 `json:"word_id" db:"word_id"`
-	StudySessionID  int64 `json:"study_session_id" db:"study_session_id"`
-	Correct         bool  `json:"correct" db:"correct"`
+	WordID         int64 `json:"word_id" db:"word_id"`
+	StudySessionID int64 `json:"study_session_id" db:"study_session_id"`
+	Correct        bool  `json:"correct" db:"correct"`
+	// What the learner answered, when the activity knows it
+	Answer string `json:"answer,omitempty" db:"answer"`
+	// The word a wrong answer matches, if any
+	AnswerWordID *int64 `json:"answer_word_id,omitempty" db:"answer_word_id"`
+	// How long the learner took to answer, in milliseconds
+	ResponseMs *int `json:"response_ms,omitempty" db:"response_ms"`
+	// How well the learner recalled the word, 0-5, when the activity grades it
+	Grade *int `json:"grade,omitempty" db:"grade"`
+}
+
+// PassingGrade is the lowest grade that counts as a correct answer
+const PassingGrade = 3
+
+// ReviewSubmission is one answer in a batch of reviews. Correct may be left
+// out when a grade is given; when both are, they must agree.
+type ReviewSubmission struct {
+	WordID  int64 `json:"word_id"`
+	Correct *bool `json:"correct"`
+	Grade   *int  `json:"grade"`
+	// When the learner answered; defaults to when the batch is recorded
+	AnsweredAt *time.Time `json:"answered_at"`
+}
+
+// ReviewBatchItem is one review of a batch as it was recorded
+type ReviewBatchItem struct {
+	// Position of the review in the submitted batch
+	Index  int            `json:"index"`
+	Review WordReviewItem `json:"review"`
+	// The word's mastery state after this review
+	Mastery string `json:"mastery"`
+}
+
+// ReviewBatchResult is the outcome of recording a batch of reviews
+type ReviewBatchResult struct {
+	Items   []ReviewBatchItem         `json:"items"`
+	Session StudySessionReviewSummary `json:"session"`
+}
+
+// Ways a recorded review can be changed
+const (
+	// ReviewUndo deletes the review
+	ReviewUndo = "undo"
+	// ReviewRegrade flips whether the review was correct, or sets its 0-5 grade
+	ReviewRegrade = "regrade"
+)
+
+// ReviewAudit records an undone or re-graded review
+type ReviewAudit struct {
+	ID             int64          `json:"id"`
+	ReviewID       int64          `json:"review_id"`
+	StudySessionID int64          `json:"study_session_id"`
+	WordID         int64          `json:"word_id"`
+	Action         string         `json:"action"`
+	Before         WordReviewItem `json:"before"`
+	// The review after the change; nil when it was undone
+	After     *WordReviewItem `json:"after,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ReviewChange is the audit entry of a change together with its word's
+// stats, schedule and mastery recomputed from the remaining reviews
+type ReviewChange struct {
+	Audit ReviewAudit `json:"audit"`
+	Stats WordStats   `json:"stats"`
+	// Nil once the word has no reviews left
+	Schedule *WordSchedule `json:"schedule"`
+	Mastery  WordMastery   `json:"mastery"`
 }
 
 // WordReviewStats represents statistics for word reviews
 type WordReviewStats struct {
-	TotalReviews  int     `json:"total_reviews"`
-	CorrectCount  int     `json:"correct_count"`
-	WrongCount    int     `json:"wrong_count"`
-	SuccessRate   float64 `json:"success_rate"`
+	TotalReviews int     `json:"total_reviews"`
+	CorrectCount int     `json:"correct_count"`
+	WrongCount   int     `json:"wrong_count"`
+	SuccessRate  float64 `json:"success_rate"`
 }
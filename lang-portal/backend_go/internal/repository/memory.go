@@ -0,0 +1,512 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"lang-portal/internal/models"
+)
+
+// memoryStore holds every record of the in-memory repositories. It enforces
+// the same references as the SQLite schema's foreign keys.
+type memoryStore struct {
+	mu         sync.Mutex
+	now        func() time.Time
+	words      map[int64]models.Word
+	groups     map[int64]models.Group
+	groupWords map[int64]map[int64]bool
+	sessions   map[int64]models.StudySession
+	reviews    map[int64]models.WordReviewItem
+	activities map[int64]models.StudyActivity
+	stats      map[int64]models.WordStats
+	schedules  map[int64]models.WordSchedule
+	mastery    map[int64]models.WordMastery
+	flags      map[int64]models.WordFlags
+	lastID     map[string]int64
+}
+
+// NewMemory returns repositories that keep records in memory, holding what a
+// freshly migrated database does: the Vocabulary Quiz activity and nothing
+// else. They are safe for concurrent use but have no transactions.
+func NewMemory() *Repositories {
+	s := &memoryStore{
+		now:        time.Now,
+		words:      make(map[int64]models.Word),
+		groups:     make(map[int64]models.Group),
+		groupWords: make(map[int64]map[int64]bool),
+		sessions:   make(map[int64]models.StudySession),
+		reviews:    make(map[int64]models.WordReviewItem),
+		activities: make(map[int64]models.StudyActivity),
+		stats:      make(map[int64]models.WordStats),
+		schedules:  make(map[int64]models.WordSchedule),
+		mastery:    make(map[int64]models.WordMastery),
+		flags:      make(map[int64]models.WordFlags),
+		lastID:     make(map[string]int64),
+	}
+	quiz := models.StudyActivity{
+		Name:         "Vocabulary Quiz",
+		ThumbnailURL: "https://example.com/thumbnail.jpg",
+		Description:  "Practice your vocabulary with flashcards",
+	}
+	s.create("study_activities", &quiz.Base)
+	s.activities[quiz.ID] = quiz
+	return &Repositories{
+		Words:      memoryWords{s},
+		Groups:     memoryGroups{s},
+		Sessions:   memorySessions{s},
+		Reviews:    memoryReviews{s},
+		Activities: memoryActivities{s},
+		Progress:   memoryProgress{s},
+	}
+}
+
+// create assigns the next ID of a table and the creation time, like an
+// AUTOINCREMENT key and a CURRENT_TIMESTAMP default
+func (s *memoryStore) create(table string, b *models.Base) {
+	s.lastID[table]++
+	b.ID = s.lastID[table]
+	if b.CreatedAt.IsZero() {
+		b.CreatedAt = s.now()
+	}
+	b.CreatedAt = storedTime(b.CreatedAt)
+}
+
+// sorted returns the values of m that keep says to, ordered by less
+func sorted[T any](m map[int64]T, keep func(T) bool, less func(a, b T) bool) []T {
+	out := []T{}
+	for _, v := range m {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return out
+}
+
+// page returns the slice of items an OFFSET/LIMIT query would
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := len(items)
+	if limit >= 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return items[offset:end]
+}
+
+func all[T any](T) bool { return true }
+
+func byID(a, b models.Base) bool { return a.ID < b.ID }
+
+// byTime orders records oldest first, then by ID
+func byTime(a, b models.Base) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+func missing(what string) error {
+	return fmt.Errorf("failed to create %s: %w", what, ErrInvalidReference)
+}
+
+// copyTime returns a stored copy of an optional time
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	stored := storedTime(*t)
+	return &stored
+}
+
+// lowerASCII lowercases s like SQLite's LOWER, which leaves non-ASCII letters alone
+func lowerASCII(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
+
+type memoryWords struct{ s *memoryStore }
+
+func (r memoryWords) Create(ctx context.Context, w *models.Word) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.create("words", &w.Base)
+	stored := *w
+	stored.Parts = nil
+	if len(w.Parts) > 0 {
+		stored.Parts = append([]byte(nil), w.Parts...)
+	}
+	r.s.words[w.ID] = stored
+	return nil
+}
+
+func (r memoryWords) Get(ctx context.Context, id int64) (*models.Word, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	w, ok := r.s.words[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &w, nil
+}
+
+func (r memoryWords) List(ctx context.Context, offset, limit int) ([]models.Word, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	words := sorted(r.s.words, all[models.Word], func(a, b models.Word) bool { return byID(a.Base, b.Base) })
+	return page(words, offset, limit), len(words), nil
+}
+
+func (r memoryWords) FindByForm(ctx context.Context, form string, excludeID int64) (*models.Word, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var found *models.Word
+	for _, w := range r.s.words {
+		chinese := w.Chinese == form
+		if w.ID == excludeID || (!chinese && lowerASCII(w.English) != lowerASCII(form)) {
+			continue
+		}
+		if found == nil || chinese && found.Chinese != form || chinese == (found.Chinese == form) && w.ID < found.ID {
+			match := w
+			found = &match
+		}
+	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	return found, nil
+}
+
+type memoryGroups struct{ s *memoryStore }
+
+func (r memoryGroups) Create(ctx context.Context, g *models.Group) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.create("groups", &g.Base)
+	r.s.groups[g.ID] = *g
+	return nil
+}
+
+func (r memoryGroups) Get(ctx context.Context, id int64) (*models.Group, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	g, ok := r.s.groups[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &g, nil
+}
+
+func (r memoryGroups) List(ctx context.Context, offset, limit int) ([]models.Group, int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	groups := sorted(r.s.groups, all[models.Group], func(a, b models.Group) bool { return byID(a.Base, b.Base) })
+	return page(groups, offset, limit), len(groups), nil
+}
+
+func (r memoryGroups) AddWords(ctx context.Context, groupID int64, wordIDs ...int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	// Words before a missing one are still added, as with SQLite outside a transaction
+	for _, wordID := range wordIDs {
+		if _, ok := r.s.groups[groupID]; !ok {
+			return missing("group word")
+		}
+		if _, ok := r.s.words[wordID]; !ok {
+			return missing("group word")
+		}
+		if r.s.groupWords[groupID] == nil {
+			r.s.groupWords[groupID] = make(map[int64]bool)
+		}
+		r.s.groupWords[groupID][wordID] = true
+	}
+	return nil
+}
+
+func (r memoryGroups) Words(ctx context.Context, groupID int64) ([]models.Word, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.groups[groupID]; !ok {
+		return nil, ErrNotFound
+	}
+	in := r.s.groupWords[groupID]
+	return sorted(r.s.words, func(w models.Word) bool { return in[w.ID] }, func(a, b models.Word) bool { return byID(a.Base, b.Base) }), nil
+}
+
+type memorySessions struct{ s *memoryStore }
+
+func (r memorySessions) Create(ctx context.Context, ss *models.StudySession) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.groups[ss.GroupID]; !ok {
+		return missing("study session")
+	}
+	if _, ok := r.s.activities[ss.StudyActivityID]; !ok {
+		return missing("study session")
+	}
+	r.s.create("study_sessions", &ss.Base)
+	r.s.sessions[ss.ID] = *ss
+	return nil
+}
+
+func (r memorySessions) Get(ctx context.Context, id int64) (*models.StudySession, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	ss, ok := r.s.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &ss, nil
+}
+
+func (r memorySessions) ListByGroup(ctx context.Context, groupID int64) ([]models.StudySession, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return sorted(r.s.sessions,
+		func(ss models.StudySession) bool { return ss.GroupID == groupID },
+		func(a, b models.StudySession) bool { return byID(a.Base, b.Base) }), nil
+}
+
+type memoryReviews struct{ s *memoryStore }
+
+func (r memoryReviews) Create(ctx context.Context, rv *models.WordReviewItem) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.words[rv.WordID]; !ok {
+		return missing("word review")
+	}
+	if _, ok := r.s.sessions[rv.StudySessionID]; !ok {
+		return missing("word review")
+	}
+	if rv.AnswerWordID != nil {
+		if _, ok := r.s.words[*rv.AnswerWordID]; !ok {
+			return missing("word review")
+		}
+	}
+	if rv.Grade != nil && (*rv.Grade < 0 || *rv.Grade > 5) {
+		return fmt.Errorf("failed to create word review: %w", ErrInvalidValue)
+	}
+	r.s.create("word_review_items", &rv.Base)
+	stored := *rv
+	if rv.AnswerWordID != nil {
+		id := *rv.AnswerWordID
+		stored.AnswerWordID = &id
+	}
+	if rv.ResponseMs != nil {
+		ms := *rv.ResponseMs
+		stored.ResponseMs = &ms
+	}
+	if rv.Grade != nil {
+		g := *rv.Grade
+		stored.Grade = &g
+	}
+	r.s.reviews[rv.ID] = stored
+	return nil
+}
+
+func (r memoryReviews) ListByWord(ctx context.Context, wordID int64) ([]models.WordReviewItem, error) {
+	return r.list(func(rv models.WordReviewItem) bool { return rv.WordID == wordID }), nil
+}
+
+func (r memoryReviews) ListBySession(ctx context.Context, sessionID int64) ([]models.WordReviewItem, error) {
+	return r.list(func(rv models.WordReviewItem) bool { return rv.StudySessionID == sessionID }), nil
+}
+
+func (r memoryReviews) list(keep func(models.WordReviewItem) bool) []models.WordReviewItem {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return sorted(r.s.reviews, keep, func(a, b models.WordReviewItem) bool { return byTime(a.Base, b.Base) })
+}
+
+type memoryActivities struct{ s *memoryStore }
+
+func (r memoryActivities) Create(ctx context.Context, a *models.StudyActivity) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, other := range r.s.activities {
+		if other.Name == a.Name {
+			return fmt.Errorf("failed to create study activity: %w", ErrDuplicate)
+		}
+	}
+	r.s.create("study_activities", &a.Base)
+	r.s.activities[a.ID] = *a
+	return nil
+}
+
+func (r memoryActivities) Get(ctx context.Context, id int64) (*models.StudyActivity, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	a, ok := r.s.activities[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &a, nil
+}
+
+func (r memoryActivities) List(ctx context.Context) ([]models.StudyActivity, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return sorted(r.s.activities, all[models.StudyActivity], func(a, b models.StudyActivity) bool { return byID(a.Base, b.Base) }), nil
+}
+
+type memoryProgress struct{ s *memoryStore }
+
+// word checks that a word exists before progress is saved for it
+func (r memoryProgress) word(wordID int64, what string) error {
+	if _, ok := r.s.words[wordID]; !ok {
+		return missing(what)
+	}
+	return nil
+}
+
+func (r memoryProgress) Stats(ctx context.Context, wordID int64) (*models.WordStats, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	s, ok := r.s.stats[wordID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	s.LastReviewedAt = copyTime(s.LastReviewedAt)
+	return &s, nil
+}
+
+func (r memoryProgress) SaveStats(ctx context.Context, wordID int64, s models.WordStats) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.word(wordID, "word stats"); err != nil {
+		return err
+	}
+	r.s.stats[wordID] = models.WordStats{
+		CorrectCount:   s.CorrectCount,
+		WrongCount:     s.WrongCount,
+		LastReviewedAt: copyTime(s.LastReviewedAt),
+		Streak:         s.Streak,
+	}
+	return nil
+}
+
+func (r memoryProgress) Schedule(ctx context.Context, wordID int64) (*models.WordSchedule, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	s, ok := r.s.schedules[wordID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &s, nil
+}
+
+func (r memoryProgress) SaveSchedule(ctx context.Context, s models.WordSchedule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.word(s.WordID, "word schedule"); err != nil {
+		return err
+	}
+	s.DueAt = storedTime(s.DueAt)
+	s.LastReviewedAt = storedTime(s.LastReviewedAt)
+	r.s.schedules[s.WordID] = s
+	return nil
+}
+
+func (r memoryProgress) Mastery(ctx context.Context, wordID int64) (*models.WordMastery, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	m, ok := r.s.mastery[wordID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &m, nil
+}
+
+func (r memoryProgress) SaveMastery(ctx context.Context, m models.WordMastery) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.word(m.WordID, "word mastery"); err != nil {
+		return err
+	}
+	r.s.mastery[m.WordID] = m
+	return nil
+}
+
+func (r memoryProgress) Clear(ctx context.Context, wordID int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.stats, wordID)
+	delete(r.s.schedules, wordID)
+	delete(r.s.mastery, wordID)
+	return nil
+}
+
+func (r memoryProgress) Flags(ctx context.Context, wordID int64) (*models.WordFlags, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	f, ok := r.s.flags[wordID]
+	if !ok {
+		return &models.WordFlags{WordID: wordID}, nil
+	}
+	f.LeechAt = copyTime(f.LeechAt)
+	f.SuspendedAt = copyTime(f.SuspendedAt)
+	return &f, nil
+}
+
+func (r memoryProgress) FlagLeech(ctx context.Context, wordID int64, suspend bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.word(wordID, "word flags"); err != nil {
+		return err
+	}
+	now := storedTime(r.s.now())
+	f := r.s.flags[wordID]
+	f.WordID = wordID
+	f.Leech = true
+	f.LeechAt = &now
+	if suspend && !f.Suspended {
+		f.Suspended = true
+		f.SuspendedAt = &now
+	}
+	r.s.flags[wordID] = f
+	return nil
+}
+
+func (r memoryProgress) ClearLeech(ctx context.Context, wordID int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	f, ok := r.s.flags[wordID]
+	if !ok || !f.Leech {
+		return nil
+	}
+	if f.SuspendedAt != nil && f.LeechAt != nil && f.SuspendedAt.Equal(*f.LeechAt) {
+		f.Suspended = false
+		f.SuspendedAt = nil
+	}
+	f.Leech = false
+	f.LeechAt = nil
+	r.s.flags[wordID] = f
+	return nil
+}
+
+func (r memoryProgress) SetSuspended(ctx context.Context, wordID int64, suspended bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.word(wordID, "word flags"); err != nil {
+		return err
+	}
+	f := r.s.flags[wordID]
+	f.WordID = wordID
+	f.Suspended = suspended
+	f.SuspendedAt = nil
+	if suspended {
+		now := storedTime(r.s.now())
+		f.SuspendedAt = &now
+	}
+	r.s.flags[wordID] = f
+	return nil
+}
@@ -0,0 +1,123 @@
+// Package repository stores the core records (words, groups, study sessions,
+// reviews and study activities) and the progress derived from reviews behind
+// interfaces, with a SQLite implementation for the app and an in-memory one
+// for tests. Both behave the same; the conformance tests in this package hold
+// them to it.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lang-portal/internal/models"
+)
+
+var (
+	// ErrNotFound is returned when a record does not exist
+	ErrNotFound = errors.New("record not found")
+	// ErrInvalidReference is returned when a record refers to another that does not exist
+	ErrInvalidReference = errors.New("referenced record does not exist")
+	// ErrDuplicate is returned when a record would repeat a unique value
+	ErrDuplicate = errors.New("record already exists")
+	// ErrInvalidValue is returned when a field is outside the values it may hold
+	ErrInvalidValue = errors.New("value out of range")
+)
+
+// WordRepository stores vocabulary words
+type WordRepository interface {
+	// Create stores a word and fills in its ID and, if unset, CreatedAt
+	Create(ctx context.Context, w *models.Word) error
+	Get(ctx context.Context, id int64) (*models.Word, error)
+	// List returns a page of words by ID and the total number of words
+	List(ctx context.Context, offset, limit int) ([]models.Word, int, error)
+	// FindByForm returns the word, other than excludeID, whose Chinese form
+	// is form or whose English form is form ignoring ASCII case. A Chinese
+	// match wins, then the lowest ID.
+	FindByForm(ctx context.Context, form string, excludeID int64) (*models.Word, error)
+}
+
+// GroupRepository stores groups and which words they hold
+type GroupRepository interface {
+	// Create stores a group and fills in its ID and, if unset, CreatedAt
+	Create(ctx context.Context, g *models.Group) error
+	Get(ctx context.Context, id int64) (*models.Group, error)
+	// List returns a page of groups by ID and the total number of groups
+	List(ctx context.Context, offset, limit int) ([]models.Group, int, error)
+	// AddWords adds words to a group; words already in it are skipped
+	AddWords(ctx context.Context, groupID int64, wordIDs ...int64) error
+	// Words returns the words of a group by ID
+	Words(ctx context.Context, groupID int64) ([]models.Word, error)
+}
+
+// SessionRepository stores study sessions
+type SessionRepository interface {
+	// Create stores a session and fills in its ID and, if unset, CreatedAt
+	Create(ctx context.Context, s *models.StudySession) error
+	Get(ctx context.Context, id int64) (*models.StudySession, error)
+	// ListByGroup returns a group's sessions by ID
+	ListByGroup(ctx context.Context, groupID int64) ([]models.StudySession, error)
+}
+
+// ReviewRepository stores word reviews. It only records them; the services
+// update the word's progress.
+type ReviewRepository interface {
+	// Create stores a review and fills in its ID and, if unset, CreatedAt
+	Create(ctx context.Context, r *models.WordReviewItem) error
+	// ListByWord returns a word's reviews, oldest first
+	ListByWord(ctx context.Context, wordID int64) ([]models.WordReviewItem, error)
+	// ListBySession returns a session's reviews, oldest first
+	ListBySession(ctx context.Context, sessionID int64) ([]models.WordReviewItem, error)
+}
+
+// ProgressRepository stores what the services derive from a word's reviews:
+// its running totals, schedule, mastery and flags. Saving progress of a word
+// that does not exist fails with ErrInvalidReference.
+type ProgressRepository interface {
+	// Stats returns a word's review totals; Mastery, Leech and Suspended are
+	// left unset. A word never reviewed has none.
+	Stats(ctx context.Context, wordID int64) (*models.WordStats, error)
+	SaveStats(ctx context.Context, wordID int64, s models.WordStats) error
+	Schedule(ctx context.Context, wordID int64) (*models.WordSchedule, error)
+	SaveSchedule(ctx context.Context, s models.WordSchedule) error
+	Mastery(ctx context.Context, wordID int64) (*models.WordMastery, error)
+	SaveMastery(ctx context.Context, m models.WordMastery) error
+	// Clear removes a word's totals, schedule and mastery, leaving its flags
+	Clear(ctx context.Context, wordID int64) error
+
+	// Flags returns a word's flags; a word never flagged gets a zero value
+	Flags(ctx context.Context, wordID int64) (*models.WordFlags, error)
+	// FlagLeech marks a word as a leech now and, if suspend is set and the
+	// word is not suspended yet, suspends it at the same time
+	FlagLeech(ctx context.Context, wordID int64, suspend bool) error
+	// ClearLeech removes a word's leech flag, and its suspension if the flag made it
+	ClearLeech(ctx context.Context, wordID int64) error
+	// SetSuspended suspends a word now or unsuspends it
+	SetSuspended(ctx context.Context, wordID int64, suspended bool) error
+}
+
+// ActivityRepository stores the catalog of study activities
+type ActivityRepository interface {
+	// Create stores an activity and fills in its ID and, if unset, CreatedAt.
+	// Names are unique.
+	Create(ctx context.Context, a *models.StudyActivity) error
+	Get(ctx context.Context, id int64) (*models.StudyActivity, error)
+	// List returns every activity by ID
+	List(ctx context.Context) ([]models.StudyActivity, error)
+}
+
+// Repositories holds one implementation of every repository
+type Repositories struct {
+	Words      WordRepository
+	Groups     GroupRepository
+	Sessions   SessionRepository
+	Reviews    ReviewRepository
+	Activities ActivityRepository
+	Progress   ProgressRepository
+}
+
+// storedTime is t as it reads back after being stored: UTC to the second,
+// as database.FormatTime keeps it, in both implementations
+func storedTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
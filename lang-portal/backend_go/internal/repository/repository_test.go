@@ -0,0 +1,437 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/database/migrations"
+	"lang-portal/internal/models"
+)
+
+func TestSQLiteRepositories(t *testing.T) {
+	runConformance(t, func(t *testing.T) *Repositories {
+		db, err := database.Open(database.Config{DBPath: ":memory:"})
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		if err := migrations.NewManager(db.Writer, "../database/migrations").Migrate(); err != nil {
+			t.Fatalf("failed to migrate: %v", err)
+		}
+		return NewSQLite(database.Handle(db.Writer))
+	})
+}
+
+func TestMemoryRepositories(t *testing.T) {
+	runConformance(t, func(t *testing.T) *Repositories { return NewMemory() })
+}
+
+// runConformance checks the behaviour every implementation must share; open
+// returns empty repositories
+func runConformance(t *testing.T, open func(t *testing.T) *Repositories) {
+	day := time.Date(2025, 3, 10, 9, 30, 15, 0, time.UTC)
+
+	t.Run("words", func(t *testing.T) {
+		r := open(t)
+		ctx := t.Context()
+
+		hello := models.Word{Chinese: "你好", English: "hello", Parts: json.RawMessage(`{"pinyin":"nǐ hǎo"}`)}
+		if err := r.Words.Create(ctx, &hello); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if hello.ID == 0 || hello.CreatedAt.IsZero() {
+			t.Fatalf("Create() left ID %d, CreatedAt %v", hello.ID, hello.CreatedAt)
+		}
+		bye := models.Word{Chinese: "再见", English: "goodbye", Base: models.Base{CreatedAt: day.Add(400 * time.Millisecond)}}
+		if err := r.Words.Create(ctx, &bye); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if !bye.CreatedAt.Equal(day) {
+			t.Errorf("CreatedAt = %v, want %v truncated to the second", bye.CreatedAt, day)
+		}
+
+		got, err := r.Words.Get(ctx, hello.ID)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got.Chinese != "你好" || got.English != "hello" || string(got.Parts) != `{"pinyin":"nǐ hǎo"}` || !got.CreatedAt.Equal(hello.CreatedAt) {
+			t.Errorf("Get() = %+v, want %+v", got, hello)
+		}
+		if got, _ := r.Words.Get(ctx, bye.ID); got.Parts != nil {
+			t.Errorf("Parts = %s, want nil", got.Parts)
+		}
+		if _, err := r.Words.Get(ctx, 99); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(missing) error = %v, want %v", err, ErrNotFound)
+		}
+
+		var first int64
+		for i := 0; i < 3; i++ {
+			w := models.Word{Chinese: "字", English: "character"}
+			if err := r.Words.Create(ctx, &w); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if i == 0 {
+				first = w.ID
+			}
+		}
+		words, total, err := r.Words.List(ctx, 1, 2)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if total != 5 || len(words) != 2 || words[0].ID != bye.ID {
+			t.Errorf("List(1, 2) = %d words from %v, total %d; want 2 from %d, total 5", len(words), ids(words), total, bye.ID)
+		}
+		if words, _, _ := r.Words.List(ctx, 10, 2); len(words) != 0 {
+			t.Errorf("List past the end = %d words, want 0", len(words))
+		}
+
+		// English matches ignore case; the lowest ID wins, unless a word matches in Chinese
+		if got, err := r.Words.FindByForm(ctx, "Hello", 0); err != nil || got.ID != hello.ID {
+			t.Errorf("FindByForm(Hello) = %+v, %v; want word %d", got, err, hello.ID)
+		}
+		if got, err := r.Words.FindByForm(ctx, "character", first); err != nil || got.ID != first+1 {
+			t.Errorf("FindByForm(character) = %+v, %v; want word %d", got, err, first+1)
+		}
+		upper := models.Word{Chinese: "CHARACTER", English: "letters"}
+		if err := r.Words.Create(ctx, &upper); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if got, err := r.Words.FindByForm(ctx, "CHARACTER", 0); err != nil || got.ID != upper.ID {
+			t.Errorf("FindByForm(CHARACTER) = %+v, %v; want word %d", got, err, upper.ID)
+		}
+		if _, err := r.Words.FindByForm(ctx, "hello", hello.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("FindByForm(excluded) error = %v, want %v", err, ErrNotFound)
+		}
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		r := open(t)
+		ctx := t.Context()
+
+		a, b, c := word(t, r, "一"), word(t, r, "二"), word(t, r, "三")
+		g := models.Group{Name: "Numbers"}
+		if err := r.Groups.Create(ctx, &g); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if err := r.Groups.AddWords(ctx, g.ID, c.ID, a.ID, c.ID); err != nil {
+			t.Fatalf("AddWords() error = %v", err)
+		}
+		if err := r.Groups.AddWords(ctx, g.ID, a.ID); err != nil {
+			t.Fatalf("AddWords() again error = %v", err)
+		}
+		words, err := r.Groups.Words(ctx, g.ID)
+		if err != nil {
+			t.Fatalf("Words() error = %v", err)
+		}
+		if got := ids(words); len(got) != 2 || got[0] != a.ID || got[1] != c.ID {
+			t.Errorf("Words() = %v, want [%d %d]", got, a.ID, c.ID)
+		}
+
+		if err := r.Groups.AddWords(ctx, g.ID, b.ID, 99); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("AddWords(missing word) error = %v, want %v", err, ErrInvalidReference)
+		}
+		if err := r.Groups.AddWords(ctx, 99, a.ID); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("AddWords(missing group) error = %v, want %v", err, ErrInvalidReference)
+		}
+		if _, err := r.Groups.Words(ctx, 99); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Words(missing) error = %v, want %v", err, ErrNotFound)
+		}
+
+		got, err := r.Groups.Get(ctx, g.ID)
+		if err != nil || got.Name != "Numbers" || !got.CreatedAt.Equal(g.CreatedAt) {
+			t.Errorf("Get() = %+v, %v; want %+v", got, err, g)
+		}
+		if _, err := r.Groups.Get(ctx, 99); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(missing) error = %v, want %v", err, ErrNotFound)
+		}
+		other := models.Group{Name: "Colours"}
+		if err := r.Groups.Create(ctx, &other); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		groups, total, err := r.Groups.List(ctx, 0, 10)
+		if err != nil || total != 2 || len(groups) != 2 || groups[1].Name != "Colours" {
+			t.Errorf("List() = %+v, total %d, %v; want Numbers then Colours", groups, total, err)
+		}
+	})
+
+	t.Run("sessions and activities", func(t *testing.T) {
+		r := open(t)
+		ctx := t.Context()
+
+		// Migrations add the Vocabulary Quiz
+		activities, err := r.Activities.List(ctx)
+		if err != nil || len(activities) != 1 || activities[0].ID != 1 || activities[0].Name != "Vocabulary Quiz" {
+			t.Fatalf("List() = %+v, %v; want the Vocabulary Quiz", activities, err)
+		}
+		a := models.StudyActivity{Name: "Flashcards", Description: "Flip through the cards"}
+		if err := r.Activities.Create(ctx, &a); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if err := r.Activities.Create(ctx, &models.StudyActivity{Name: "Flashcards"}); !errors.Is(err, ErrDuplicate) {
+			t.Errorf("Create(same name) error = %v, want %v", err, ErrDuplicate)
+		}
+		if got, err := r.Activities.Get(ctx, a.ID); err != nil || got.Name != "Flashcards" || got.Description != "Flip through the cards" || got.ThumbnailURL != "" {
+			t.Errorf("Get() = %+v, %v; want %+v", got, err, a)
+		}
+		if _, err := r.Activities.Get(ctx, 99); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(missing) error = %v, want %v", err, ErrNotFound)
+		}
+		if list, err := r.Activities.List(ctx); err != nil || len(list) != 2 || list[1].ID != a.ID {
+			t.Errorf("List() = %+v, %v; want the quiz and %d", list, err, a.ID)
+		}
+
+		g := group(t, r, "Greetings")
+		other := group(t, r, "Colours")
+		s1 := models.StudySession{GroupID: g.ID, StudyActivityID: a.ID, Base: models.Base{CreatedAt: day}}
+		s2 := models.StudySession{GroupID: g.ID, StudyActivityID: 1}
+		s3 := models.StudySession{GroupID: other.ID, StudyActivityID: 1}
+		for _, s := range []*models.StudySession{&s1, &s2, &s3} {
+			if err := r.Sessions.Create(ctx, s); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+		if err := r.Sessions.Create(ctx, &models.StudySession{GroupID: 99, StudyActivityID: 1}); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("Create(missing group) error = %v, want %v", err, ErrInvalidReference)
+		}
+		if err := r.Sessions.Create(ctx, &models.StudySession{GroupID: g.ID, StudyActivityID: 99}); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("Create(missing activity) error = %v, want %v", err, ErrInvalidReference)
+		}
+
+		got, err := r.Sessions.Get(ctx, s1.ID)
+		if err != nil || got.GroupID != g.ID || got.StudyActivityID != a.ID || !got.CreatedAt.Equal(day) {
+			t.Errorf("Get() = %+v, %v; want %+v", got, err, s1)
+		}
+		if _, err := r.Sessions.Get(ctx, 99); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(missing) error = %v, want %v", err, ErrNotFound)
+		}
+		sessions, err := r.Sessions.ListByGroup(ctx, g.ID)
+		if err != nil || len(sessions) != 2 || sessions[0].ID != s1.ID || sessions[1].ID != s2.ID {
+			t.Errorf("ListByGroup() = %+v, %v; want sessions %d and %d", sessions, err, s1.ID, s2.ID)
+		}
+		if sessions, _ := r.Sessions.ListByGroup(ctx, 99); sessions == nil || len(sessions) != 0 {
+			t.Errorf("ListByGroup(missing) = %#v, want an empty list", sessions)
+		}
+	})
+
+	t.Run("reviews", func(t *testing.T) {
+		r := open(t)
+		ctx := t.Context()
+
+		w, other := word(t, r, "猫"), word(t, r, "狗")
+		g := group(t, r, "Animals")
+		s := models.StudySession{GroupID: g.ID, StudyActivityID: 1}
+		if err := r.Sessions.Create(ctx, &s); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		ms, grade := 1200, 2
+		// Stored out of order; lists come back oldest first
+		late := models.WordReviewItem{WordID: w.ID, StudySessionID: s.ID, Correct: true, Base: models.Base{CreatedAt: day.Add(48 * time.Hour)}}
+		early := models.WordReviewItem{WordID: w.ID, StudySessionID: s.ID, Answer: "dog", AnswerWordID: &other.ID, ResponseMs: &ms, Grade: &grade, Base: models.Base{CreatedAt: day}}
+		otherReview := models.WordReviewItem{WordID: other.ID, StudySessionID: s.ID, Correct: true, Base: models.Base{CreatedAt: day.Add(time.Hour)}}
+		for _, rv := range []*models.WordReviewItem{&late, &early, &otherReview} {
+			if err := r.Reviews.Create(ctx, rv); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+		ms, grade = 0, 0
+
+		reviews, err := r.Reviews.ListByWord(ctx, w.ID)
+		if err != nil {
+			t.Fatalf("ListByWord() error = %v", err)
+		}
+		if len(reviews) != 2 || reviews[0].ID != early.ID || reviews[1].ID != late.ID {
+			t.Fatalf("ListByWord() = %+v, want reviews %d then %d", reviews, early.ID, late.ID)
+		}
+		got := reviews[0]
+		if got.Correct || got.Answer != "dog" || got.AnswerWordID == nil || *got.AnswerWordID != other.ID ||
+			got.ResponseMs == nil || *got.ResponseMs != 1200 || got.Grade == nil || *got.Grade != 2 || !got.CreatedAt.Equal(day) {
+			t.Errorf("ListByWord()[0] = %+v, want %+v", got, early)
+		}
+		if got := reviews[1]; !got.Correct || got.Answer != "" || got.AnswerWordID != nil || got.ResponseMs != nil || got.Grade != nil {
+			t.Errorf("ListByWord()[1] = %+v, want %+v", got, late)
+		}
+
+		reviews, err = r.Reviews.ListBySession(ctx, s.ID)
+		if err != nil || len(reviews) != 3 || reviews[0].ID != early.ID || reviews[1].ID != otherReview.ID || reviews[2].ID != late.ID {
+			t.Errorf("ListBySession() = %+v, %v; want reviews %d, %d, %d", reviews, err, early.ID, otherReview.ID, late.ID)
+		}
+		if reviews, _ := r.Reviews.ListByWord(ctx, 99); reviews == nil || len(reviews) != 0 {
+			t.Errorf("ListByWord(missing) = %#v, want an empty list", reviews)
+		}
+
+		if err := r.Reviews.Create(ctx, &models.WordReviewItem{WordID: 99, StudySessionID: s.ID}); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("Create(missing word) error = %v, want %v", err, ErrInvalidReference)
+		}
+		if err := r.Reviews.Create(ctx, &models.WordReviewItem{WordID: w.ID, StudySessionID: 99}); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("Create(missing session) error = %v, want %v", err, ErrInvalidReference)
+		}
+		missingWord := int64(99)
+		if err := r.Reviews.Create(ctx, &models.WordReviewItem{WordID: w.ID, StudySessionID: s.ID, AnswerWordID: &missingWord}); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("Create(missing answer word) error = %v, want %v", err, ErrInvalidReference)
+		}
+		for _, bad := range []int{-1, 6} {
+			if err := r.Reviews.Create(ctx, &models.WordReviewItem{WordID: w.ID, StudySessionID: s.ID, Grade: &bad}); !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("Create(grade %d) error = %v, want %v", bad, err, ErrInvalidValue)
+			}
+		}
+		if reviews, _ := r.Reviews.ListByWord(ctx, w.ID); len(reviews) != 2 {
+			t.Errorf("rejected reviews were stored: ListByWord() = %d reviews, want 2", len(reviews))
+		}
+	})
+
+	t.Run("progress", func(t *testing.T) {
+		r := open(t)
+		ctx := t.Context()
+		p := r.Progress
+
+		w := word(t, r, "猫")
+		if _, err := p.Stats(ctx, w.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Stats(unreviewed) error = %v, want %v", err, ErrNotFound)
+		}
+		if _, err := p.Schedule(ctx, w.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Schedule(unreviewed) error = %v, want %v", err, ErrNotFound)
+		}
+		if _, err := p.Mastery(ctx, w.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Mastery(unreviewed) error = %v, want %v", err, ErrNotFound)
+		}
+
+		reviewed := day.Add(300 * time.Millisecond)
+		stats := models.WordStats{CorrectCount: 3, WrongCount: 1, LastReviewedAt: &reviewed, Streak: 2}
+		sched := models.WordSchedule{WordID: w.ID, Repetitions: 2, IntervalDays: 3, Ease: 2.6, DueAt: day.AddDate(0, 0, 3), LastReviewedAt: reviewed}
+		mastery := models.WordMastery{WordID: w.ID, State: "reviewing", Streak: 2, Lapses: 1, CorrectCount: 3, WrongCount: 1}
+		if err := p.SaveStats(ctx, w.ID, stats); err != nil {
+			t.Fatalf("SaveStats() error = %v", err)
+		}
+		if err := p.SaveSchedule(ctx, sched); err != nil {
+			t.Fatalf("SaveSchedule() error = %v", err)
+		}
+		if err := p.SaveMastery(ctx, mastery); err != nil {
+			t.Fatalf("SaveMastery() error = %v", err)
+		}
+		reviewed = time.Time{}
+
+		gotStats, err := p.Stats(ctx, w.ID)
+		if err != nil || gotStats.CorrectCount != 3 || gotStats.WrongCount != 1 || gotStats.Streak != 2 || gotStats.LastReviewedAt == nil || !gotStats.LastReviewedAt.Equal(day) {
+			t.Errorf("Stats() = %+v, %v; want 3/1 streak 2 last reviewed %v", gotStats, err, day)
+		}
+		gotSched, err := p.Schedule(ctx, w.ID)
+		if err != nil || gotSched.Repetitions != 2 || gotSched.IntervalDays != 3 || gotSched.Ease != 2.6 ||
+			!gotSched.DueAt.Equal(day.AddDate(0, 0, 3)) || !gotSched.LastReviewedAt.Equal(day) {
+			t.Errorf("Schedule() = %+v, %v; want %+v", gotSched, err, sched)
+		}
+		if got, err := p.Mastery(ctx, w.ID); err != nil || *got != mastery {
+			t.Errorf("Mastery() = %+v, %v; want %+v", got, err, mastery)
+		}
+
+		if err := p.SaveStats(ctx, 99, stats); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("SaveStats(missing word) error = %v, want %v", err, ErrInvalidReference)
+		}
+		if err := p.SaveSchedule(ctx, models.WordSchedule{WordID: 99}); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("SaveSchedule(missing word) error = %v, want %v", err, ErrInvalidReference)
+		}
+		if err := p.SaveMastery(ctx, models.WordMastery{WordID: 99, State: "learning"}); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("SaveMastery(missing word) error = %v, want %v", err, ErrInvalidReference)
+		}
+
+		if err := p.Clear(ctx, w.ID); err != nil {
+			t.Fatalf("Clear() error = %v", err)
+		}
+		if _, err := p.Stats(ctx, w.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Stats() after Clear error = %v, want %v", err, ErrNotFound)
+		}
+		if _, err := p.Schedule(ctx, w.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Schedule() after Clear error = %v, want %v", err, ErrNotFound)
+		}
+		if _, err := p.Mastery(ctx, w.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Mastery() after Clear error = %v, want %v", err, ErrNotFound)
+		}
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		r := open(t)
+		ctx := t.Context()
+		p := r.Progress
+
+		leech, suspended := word(t, r, "一"), word(t, r, "二")
+		if f, err := p.Flags(ctx, leech.ID); err != nil || f.WordID != leech.ID || f.Leech || f.Suspended || f.LeechAt != nil || f.SuspendedAt != nil {
+			t.Errorf("Flags(unflagged) = %+v, %v; want a zero value", f, err)
+		}
+
+		// A suspension made by the flag goes with it
+		if err := p.FlagLeech(ctx, leech.ID, true); err != nil {
+			t.Fatalf("FlagLeech() error = %v", err)
+		}
+		f, err := p.Flags(ctx, leech.ID)
+		if err != nil || !f.Leech || !f.Suspended || f.LeechAt == nil || f.SuspendedAt == nil || !f.SuspendedAt.Equal(*f.LeechAt) {
+			t.Errorf("Flags() = %+v, %v; want a leech suspended with its flag", f, err)
+		}
+		if err := p.ClearLeech(ctx, leech.ID); err != nil {
+			t.Fatalf("ClearLeech() error = %v", err)
+		}
+		if f, err := p.Flags(ctx, leech.ID); err != nil || f.Leech || f.Suspended || f.LeechAt != nil || f.SuspendedAt != nil {
+			t.Errorf("Flags() after ClearLeech = %+v, %v; want no flags", f, err)
+		}
+
+		// A suspension made first is kept, through the flag and after it
+		if err := p.SetSuspended(ctx, suspended.ID, true); err != nil {
+			t.Fatalf("SetSuspended() error = %v", err)
+		}
+		if err := p.ClearLeech(ctx, suspended.ID); err != nil {
+			t.Fatalf("ClearLeech(not a leech) error = %v", err)
+		}
+		f, err = p.Flags(ctx, suspended.ID)
+		if err != nil || !f.Suspended || f.SuspendedAt == nil || f.Leech {
+			t.Fatalf("Flags() = %+v, %v; want suspended only", f, err)
+		}
+		suspendedAt := *f.SuspendedAt
+		if err := p.FlagLeech(ctx, suspended.ID, true); err != nil {
+			t.Fatalf("FlagLeech() error = %v", err)
+		}
+		if f, _ := p.Flags(ctx, suspended.ID); !f.Leech || !f.Suspended || !f.SuspendedAt.Equal(suspendedAt) {
+			t.Errorf("Flags() = %+v, want a leech still suspended at %v", f, suspendedAt)
+		}
+
+		if err := p.SetSuspended(ctx, suspended.ID, false); err != nil {
+			t.Fatalf("SetSuspended(false) error = %v", err)
+		}
+		if f, _ := p.Flags(ctx, suspended.ID); !f.Leech || f.Suspended || f.SuspendedAt != nil {
+			t.Errorf("Flags() = %+v, want an unsuspended leech", f)
+		}
+		if err := p.FlagLeech(ctx, 99, false); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("FlagLeech(missing word) error = %v, want %v", err, ErrInvalidReference)
+		}
+		if err := p.SetSuspended(ctx, 99, true); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("SetSuspended(missing word) error = %v, want %v", err, ErrInvalidReference)
+		}
+	})
+}
+
+func word(t *testing.T, r *Repositories, chinese string) models.Word {
+	t.Helper()
+	w := models.Word{Chinese: chinese, English: chinese}
+	if err := r.Words.Create(t.Context(), &w); err != nil {
+		t.Fatalf("failed to create word: %v", err)
+	}
+	return w
+}
+
+func group(t *testing.T, r *Repositories, name string) models.Group {
+	t.Helper()
+	g := models.Group{Name: name}
+	if err := r.Groups.Create(t.Context(), &g); err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	return g
+}
+
+func ids(words []models.Word) []int64 {
+	out := make([]int64, len(words))
+	for i, w := range words {
+		out[i] = w.ID
+	}
+	return out
+}
@@ -0,0 +1,486 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+)
+
+// NewSQLite returns repositories that store records in SQLite through q. Pass
+// a database.Handle so calls inside TxRunner.InTx join the transaction.
+func NewSQLite(q database.DBTX) *Repositories {
+	return &Repositories{
+		Words:      sqliteWords{q},
+		Groups:     sqliteGroups{q},
+		Sessions:   sqliteSessions{q},
+		Reviews:    sqliteReviews{q},
+		Activities: sqliteActivities{q},
+		Progress:   sqliteProgress{q},
+	}
+}
+
+// createdAt is the value to store for a record's creation time; nil lets the
+// column default to CURRENT_TIMESTAMP
+func createdAt(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return database.FormatTime(t)
+}
+
+// nullBytes stores empty JSON as NULL
+func nullBytes(b []byte) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	return []byte(b)
+}
+
+// nullString stores an empty string as NULL
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// writeError maps a failed insert that broke a foreign key, unique or check
+// constraint to ErrInvalidReference, ErrDuplicate or ErrInvalidValue
+func writeError(what string, err error) error {
+	var serr *sqlite.Error
+	if errors.As(err, &serr) {
+		switch serr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return fmt.Errorf("failed to create %s: %w", what, ErrInvalidReference)
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+			return fmt.Errorf("failed to create %s: %w", what, ErrDuplicate)
+		case sqlite3.SQLITE_CONSTRAINT_CHECK:
+			return fmt.Errorf("failed to create %s: %w", what, ErrInvalidValue)
+		}
+	}
+	return fmt.Errorf("failed to create %s: %w", what, err)
+}
+
+// readError maps a missing row to ErrNotFound
+func readError(what string, err error) error {
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	return fmt.Errorf("failed to fetch %s: %w", what, err)
+}
+
+// scanner is a *sql.Row or *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// count returns the result of a COUNT(*) query
+func count(ctx context.Context, q database.DBTX, what, query string) (int, error) {
+	var n int
+	if err := q.QueryRowContext(ctx, query).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count %s: %w", what, err)
+	}
+	return n, nil
+}
+
+// list runs a query and scans every row with scan
+func list[T any](ctx context.Context, q database.DBTX, what string, scan func(scanner) (T, error), query string, args ...interface{}) ([]T, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", what, err)
+	}
+	defer rows.Close()
+
+	out := []T{}
+	for rows.Next() {
+		v, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", what, err)
+		}
+		out = append(out, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating %s: %w", what, err)
+	}
+	return out, nil
+}
+
+type sqliteWords struct{ q database.DBTX }
+
+const wordColumns = "id, chinese, english, parts, created_at"
+
+func scanWord(s scanner) (models.Word, error) {
+	var w models.Word
+	var parts []byte
+	err := s.Scan(&w.ID, &w.Chinese, &w.English, &parts, database.ScanTime(&w.CreatedAt))
+	if len(parts) > 0 {
+		w.Parts = parts
+	}
+	return w, err
+}
+
+func (r sqliteWords) Create(ctx context.Context, w *models.Word) error {
+	if err := r.q.QueryRowContext(ctx, `
+		INSERT INTO words (chinese, english, parts, created_at)
+		VALUES (?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP))
+		RETURNING id, created_at
+	`, w.Chinese, w.English, nullBytes(w.Parts), createdAt(w.CreatedAt)).Scan(&w.ID, database.ScanTime(&w.CreatedAt)); err != nil {
+		return writeError("word", err)
+	}
+	return nil
+}
+
+func (r sqliteWords) Get(ctx context.Context, id int64) (*models.Word, error) {
+	w, err := scanWord(r.q.QueryRowContext(ctx, "SELECT "+wordColumns+" FROM words WHERE id = ?", id))
+	if err != nil {
+		return nil, readError("word", err)
+	}
+	return &w, nil
+}
+
+func (r sqliteWords) List(ctx context.Context, offset, limit int) ([]models.Word, int, error) {
+	total, err := count(ctx, r.q, "words", "SELECT COUNT(*) FROM words")
+	if err != nil {
+		return nil, 0, err
+	}
+	words, err := list(ctx, r.q, "words", scanWord, "SELECT "+wordColumns+" FROM words ORDER BY id LIMIT ? OFFSET ?", limit, offset)
+	return words, total, err
+}
+
+func (r sqliteWords) FindByForm(ctx context.Context, form string, excludeID int64) (*models.Word, error) {
+	w, err := scanWord(r.q.QueryRowContext(ctx, `
+		SELECT `+wordColumns+` FROM words
+		WHERE id != ? AND (chinese = ? OR LOWER(english) = LOWER(?))
+		ORDER BY chinese = ? DESC, id ASC
+		LIMIT 1
+	`, excludeID, form, form, form))
+	if err != nil {
+		return nil, readError("word", err)
+	}
+	return &w, nil
+}
+
+type sqliteGroups struct{ q database.DBTX }
+
+func scanGroup(s scanner) (models.Group, error) {
+	var g models.Group
+	err := s.Scan(&g.ID, &g.Name, database.ScanTime(&g.CreatedAt))
+	return g, err
+}
+
+func (r sqliteGroups) Create(ctx context.Context, g *models.Group) error {
+	if err := r.q.QueryRowContext(ctx, `
+		INSERT INTO groups (name, created_at)
+		VALUES (?, COALESCE(?, CURRENT_TIMESTAMP))
+		RETURNING id, created_at
+	`, g.Name, createdAt(g.CreatedAt)).Scan(&g.ID, database.ScanTime(&g.CreatedAt)); err != nil {
+		return writeError("group", err)
+	}
+	return nil
+}
+
+func (r sqliteGroups) Get(ctx context.Context, id int64) (*models.Group, error) {
+	g, err := scanGroup(r.q.QueryRowContext(ctx, "SELECT id, name, created_at FROM groups WHERE id = ?", id))
+	if err != nil {
+		return nil, readError("group", err)
+	}
+	return &g, nil
+}
+
+func (r sqliteGroups) List(ctx context.Context, offset, limit int) ([]models.Group, int, error) {
+	total, err := count(ctx, r.q, "groups", "SELECT COUNT(*) FROM groups")
+	if err != nil {
+		return nil, 0, err
+	}
+	groups, err := list(ctx, r.q, "groups", scanGroup, "SELECT id, name, created_at FROM groups ORDER BY id LIMIT ? OFFSET ?", limit, offset)
+	return groups, total, err
+}
+
+func (r sqliteGroups) AddWords(ctx context.Context, groupID int64, wordIDs ...int64) error {
+	for _, wordID := range wordIDs {
+		if _, err := r.q.ExecContext(ctx, `
+			INSERT INTO words_groups (word_id, group_id)
+			SELECT ?, ?
+			WHERE NOT EXISTS (SELECT 1 FROM words_groups WHERE word_id = ? AND group_id = ?)
+		`, wordID, groupID, wordID, groupID); err != nil {
+			return writeError("group word", err)
+		}
+	}
+	return nil
+}
+
+func (r sqliteGroups) Words(ctx context.Context, groupID int64) ([]models.Word, error) {
+	if _, err := r.Get(ctx, groupID); err != nil {
+		return nil, err
+	}
+	return list(ctx, r.q, "group words", scanWord, `
+		SELECT `+wordColumns+` FROM words
+		WHERE id IN (SELECT word_id FROM words_groups WHERE group_id = ?)
+		ORDER BY id
+	`, groupID)
+}
+
+type sqliteSessions struct{ q database.DBTX }
+
+func scanSession(s scanner) (models.StudySession, error) {
+	var ss models.StudySession
+	err := s.Scan(&ss.ID, &ss.GroupID, &ss.StudyActivityID, database.ScanTime(&ss.CreatedAt))
+	return ss, err
+}
+
+func (r sqliteSessions) Create(ctx context.Context, s *models.StudySession) error {
+	if err := r.q.QueryRowContext(ctx, `
+		INSERT INTO study_sessions (group_id, study_activity_id, created_at)
+		VALUES (?, ?, COALESCE(?, CURRENT_TIMESTAMP))
+		RETURNING id, created_at
+	`, s.GroupID, s.StudyActivityID, createdAt(s.CreatedAt)).Scan(&s.ID, database.ScanTime(&s.CreatedAt)); err != nil {
+		return writeError("study session", err)
+	}
+	return nil
+}
+
+func (r sqliteSessions) Get(ctx context.Context, id int64) (*models.StudySession, error) {
+	s, err := scanSession(r.q.QueryRowContext(ctx, "SELECT id, group_id, study_activity_id, created_at FROM study_sessions WHERE id = ?", id))
+	if err != nil {
+		return nil, readError("study session", err)
+	}
+	return &s, nil
+}
+
+func (r sqliteSessions) ListByGroup(ctx context.Context, groupID int64) ([]models.StudySession, error) {
+	return list(ctx, r.q, "study sessions", scanSession, "SELECT id, group_id, study_activity_id, created_at FROM study_sessions WHERE group_id = ? ORDER BY id", groupID)
+}
+
+type sqliteReviews struct{ q database.DBTX }
+
+const reviewColumns = "id, word_id, study_session_id, correct, answer, answer_word_id, response_ms, grade, created_at"
+
+func scanReview(s scanner) (models.WordReviewItem, error) {
+	var r models.WordReviewItem
+	var answer sql.NullString
+	var answerWordID, responseMs, grade sql.NullInt64
+	err := s.Scan(&r.ID, &r.WordID, &r.StudySessionID, &r.Correct, &answer, &answerWordID, &responseMs, &grade, database.ScanTime(&r.CreatedAt))
+	r.Answer = answer.String
+	if answerWordID.Valid {
+		r.AnswerWordID = &answerWordID.Int64
+	}
+	if responseMs.Valid {
+		ms := int(responseMs.Int64)
+		r.ResponseMs = &ms
+	}
+	if grade.Valid {
+		g := int(grade.Int64)
+		r.Grade = &g
+	}
+	return r, err
+}
+
+func (r sqliteReviews) Create(ctx context.Context, rv *models.WordReviewItem) error {
+	if err := r.q.QueryRowContext(ctx, `
+		INSERT INTO word_review_items (word_id, study_session_id, correct, answer, answer_word_id, response_ms, grade, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP))
+		RETURNING id, created_at
+	`, rv.WordID, rv.StudySessionID, rv.Correct, nullString(rv.Answer), rv.AnswerWordID, rv.ResponseMs, rv.Grade, createdAt(rv.CreatedAt)).Scan(&rv.ID, database.ScanTime(&rv.CreatedAt)); err != nil {
+		return writeError("word review", err)
+	}
+	return nil
+}
+
+func (r sqliteReviews) ListByWord(ctx context.Context, wordID int64) ([]models.WordReviewItem, error) {
+	return list(ctx, r.q, "word reviews", scanReview, "SELECT "+reviewColumns+" FROM word_review_items WHERE word_id = ? ORDER BY created_at, id", wordID)
+}
+
+func (r sqliteReviews) ListBySession(ctx context.Context, sessionID int64) ([]models.WordReviewItem, error) {
+	return list(ctx, r.q, "word reviews", scanReview, "SELECT "+reviewColumns+" FROM word_review_items WHERE study_session_id = ? ORDER BY created_at, id", sessionID)
+}
+
+type sqliteActivities struct{ q database.DBTX }
+
+const activityColumns = "id, name, thumbnail_url, description, created_at"
+
+func scanActivity(s scanner) (models.StudyActivity, error) {
+	var a models.StudyActivity
+	var thumbnail, description sql.NullString
+	err := s.Scan(&a.ID, &a.Name, &thumbnail, &description, database.ScanTime(&a.CreatedAt))
+	a.ThumbnailURL = thumbnail.String
+	a.Description = description.String
+	return a, err
+}
+
+func (r sqliteActivities) Create(ctx context.Context, a *models.StudyActivity) error {
+	if err := r.q.QueryRowContext(ctx, `
+		INSERT INTO study_activities (name, thumbnail_url, description, created_at)
+		VALUES (?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP))
+		RETURNING id, created_at
+	`, a.Name, nullString(a.ThumbnailURL), nullString(a.Description), createdAt(a.CreatedAt)).Scan(&a.ID, database.ScanTime(&a.CreatedAt)); err != nil {
+		return writeError("study activity", err)
+	}
+	return nil
+}
+
+func (r sqliteActivities) Get(ctx context.Context, id int64) (*models.StudyActivity, error) {
+	a, err := scanActivity(r.q.QueryRowContext(ctx, "SELECT "+activityColumns+" FROM study_activities WHERE id = ?", id))
+	if err != nil {
+		return nil, readError("study activity", err)
+	}
+	return &a, nil
+}
+
+func (r sqliteActivities) List(ctx context.Context) ([]models.StudyActivity, error) {
+	return list(ctx, r.q, "study activities", scanActivity, "SELECT "+activityColumns+" FROM study_activities ORDER BY id")
+}
+
+type sqliteProgress struct{ q database.DBTX }
+
+func (r sqliteProgress) Stats(ctx context.Context, wordID int64) (*models.WordStats, error) {
+	var s models.WordStats
+	if err := r.q.QueryRowContext(ctx, `
+		SELECT correct_count, wrong_count, last_reviewed_at, streak FROM word_stats WHERE word_id = ?
+	`, wordID).Scan(&s.CorrectCount, &s.WrongCount, database.ScanNullTime(&s.LastReviewedAt), &s.Streak); err != nil {
+		return nil, readError("word stats", err)
+	}
+	return &s, nil
+}
+
+func (r sqliteProgress) SaveStats(ctx context.Context, wordID int64, s models.WordStats) error {
+	var lastReviewedAt interface{}
+	if s.LastReviewedAt != nil {
+		lastReviewedAt = database.FormatTime(*s.LastReviewedAt)
+	}
+	if _, err := r.q.ExecContext(ctx, `
+		INSERT INTO word_stats (word_id, correct_count, wrong_count, last_reviewed_at, streak, updated_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (word_id) DO UPDATE SET
+			correct_count = excluded.correct_count,
+			wrong_count = excluded.wrong_count,
+			last_reviewed_at = excluded.last_reviewed_at,
+			streak = excluded.streak,
+			updated_at = excluded.updated_at
+	`, wordID, s.CorrectCount, s.WrongCount, lastReviewedAt, s.Streak); err != nil {
+		return writeError("word stats", err)
+	}
+	return nil
+}
+
+func (r sqliteProgress) Schedule(ctx context.Context, wordID int64) (*models.WordSchedule, error) {
+	s := models.WordSchedule{WordID: wordID}
+	if err := r.q.QueryRowContext(ctx, `
+		SELECT repetitions, interval_days, ease, due_at, last_reviewed_at FROM word_schedules WHERE word_id = ?
+	`, wordID).Scan(&s.Repetitions, &s.IntervalDays, &s.Ease, database.ScanTime(&s.DueAt), database.ScanTime(&s.LastReviewedAt)); err != nil {
+		return nil, readError("word schedule", err)
+	}
+	return &s, nil
+}
+
+func (r sqliteProgress) SaveSchedule(ctx context.Context, s models.WordSchedule) error {
+	if _, err := r.q.ExecContext(ctx, `
+		INSERT INTO word_schedules (word_id, repetitions, interval_days, ease, due_at, last_reviewed_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (word_id) DO UPDATE SET
+			repetitions = excluded.repetitions,
+			interval_days = excluded.interval_days,
+			ease = excluded.ease,
+			due_at = excluded.due_at,
+			last_reviewed_at = excluded.last_reviewed_at
+	`, s.WordID, s.Repetitions, s.IntervalDays, s.Ease, database.FormatTime(s.DueAt), database.FormatTime(s.LastReviewedAt)); err != nil {
+		return writeError("word schedule", err)
+	}
+	return nil
+}
+
+func (r sqliteProgress) Mastery(ctx context.Context, wordID int64) (*models.WordMastery, error) {
+	m := models.WordMastery{WordID: wordID}
+	if err := r.q.QueryRowContext(ctx, `
+		SELECT state, streak, lapses, correct_count, wrong_count FROM word_mastery WHERE word_id = ?
+	`, wordID).Scan(&m.State, &m.Streak, &m.Lapses, &m.CorrectCount, &m.WrongCount); err != nil {
+		return nil, readError("word mastery", err)
+	}
+	return &m, nil
+}
+
+func (r sqliteProgress) SaveMastery(ctx context.Context, m models.WordMastery) error {
+	if _, err := r.q.ExecContext(ctx, `
+		INSERT INTO word_mastery (word_id, state, streak, lapses, correct_count, wrong_count, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (word_id) DO UPDATE SET
+			state = excluded.state,
+			streak = excluded.streak,
+			lapses = excluded.lapses,
+			correct_count = excluded.correct_count,
+			wrong_count = excluded.wrong_count,
+			updated_at = excluded.updated_at
+	`, m.WordID, m.State, m.Streak, m.Lapses, m.CorrectCount, m.WrongCount); err != nil {
+		return writeError("word mastery", err)
+	}
+	return nil
+}
+
+func (r sqliteProgress) Clear(ctx context.Context, wordID int64) error {
+	for _, table := range []string{"word_stats", "word_schedules", "word_mastery"} {
+		if _, err := r.q.ExecContext(ctx, "DELETE FROM "+table+" WHERE word_id = ?", wordID); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+func (r sqliteProgress) Flags(ctx context.Context, wordID int64) (*models.WordFlags, error) {
+	f := models.WordFlags{WordID: wordID}
+	err := r.q.QueryRowContext(ctx, `
+		SELECT leech, suspended, leech_at, suspended_at FROM word_flags WHERE word_id = ?
+	`, wordID).Scan(&f.Leech, &f.Suspended, database.ScanNullTime(&f.LeechAt), database.ScanNullTime(&f.SuspendedAt))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch word flags: %w", err)
+	}
+	return &f, nil
+}
+
+func (r sqliteProgress) FlagLeech(ctx context.Context, wordID int64, suspend bool) error {
+	if _, err := r.q.ExecContext(ctx, `
+		INSERT INTO word_flags (word_id, leech, suspended, leech_at, suspended_at)
+		VALUES (?, 1, ?, CURRENT_TIMESTAMP, CASE WHEN ? THEN CURRENT_TIMESTAMP END)
+		ON CONFLICT (word_id) DO UPDATE SET
+			leech = 1,
+			leech_at = excluded.leech_at,
+			suspended_at = CASE WHEN excluded.suspended AND NOT suspended THEN excluded.suspended_at ELSE suspended_at END,
+			suspended = suspended OR excluded.suspended
+	`, wordID, suspend, suspend); err != nil {
+		return writeError("word flags", err)
+	}
+	return nil
+}
+
+func (r sqliteProgress) ClearLeech(ctx context.Context, wordID int64) error {
+	if _, err := r.q.ExecContext(ctx, `
+		UPDATE word_flags SET
+			leech = 0,
+			leech_at = NULL,
+			suspended = CASE WHEN suspended_at = leech_at THEN 0 ELSE suspended END,
+			suspended_at = CASE WHEN suspended_at = leech_at THEN NULL ELSE suspended_at END
+		WHERE word_id = ? AND leech = 1
+	`, wordID); err != nil {
+		return fmt.Errorf("failed to clear leech: %w", err)
+	}
+	return nil
+}
+
+func (r sqliteProgress) SetSuspended(ctx context.Context, wordID int64, suspended bool) error {
+	if _, err := r.q.ExecContext(ctx, `
+		INSERT INTO word_flags (word_id, suspended, suspended_at)
+		VALUES (?, ?, CASE WHEN ? THEN CURRENT_TIMESTAMP END)
+		ON CONFLICT (word_id) DO UPDATE SET
+			suspended = excluded.suspended,
+			suspended_at = excluded.suspended_at
+	`, wordID, suspended, suspended); err != nil {
+		return writeError("word flags", err)
+	}
+	return nil
+}
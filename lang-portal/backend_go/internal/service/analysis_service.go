@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+)
+
+// AnalysisService handles text analysis against the learner's review history
+type AnalysisService struct {
+	db database.DBTX
+}
+
+// NewAnalysisService creates a new AnalysisService
+func NewAnalysisService(db *sql.DB) *AnalysisService {
+	return &AnalysisService{db: database.Handle(db)}
+}
+
+// vocabEntry is a vocabulary word with its mastery state
+type vocabEntry struct {
+	ID      int64
+	Chinese string
+	English string
+	Mastery string
+}
+
+// status maps a word's mastery state to a coverage status
+func (v vocabEntry) status() string {
+	switch v.Mastery {
+	case models.MasteryReviewing, models.MasteryMastered:
+		return models.CoverageKnown
+	case models.MasteryLearning, models.MasteryLapsed:
+		return models.CoverageInProgress
+	default:
+		return models.CoverageUnknown
+	}
+}
+
+// AnalyzeCoverage segments text and reports how much of it the learner knows
+func (s *AnalysisService) AnalyzeCoverage(ctx context.Context, text string) (*models.CoverageReport, error) {
+	vocab, err := s.loadVocabulary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := make(map[string]int64, len(vocab))
+	for id, v := range vocab {
+		dict[v.Chinese] = id
+	}
+
+	report := &models.CoverageReport{
+		Tokens:          []models.CoverageToken{},
+		UnknownWords:    []models.CoverageWord{},
+		UnknownWordIDs:  []int64{},
+		UnmatchedTokens: []string{},
+	}
+
+	unknownIdx := make(map[int64]int)
+	unmatched := make(map[string]bool)
+
+	for _, seg := range newSegmenter(dict).Segment(text) {
+		token := models.CoverageToken{Text: seg.Text, WordID: seg.WordID, Status: models.CoverageUnknown}
+
+		if seg.WordID == 0 {
+			if !unmatched[seg.Text] {
+				unmatched[seg.Text] = true
+				report.UnmatchedTokens = append(report.UnmatchedTokens, seg.Text)
+			}
+		} else {
+			v := vocab[seg.WordID]
+			token.Status = v.status()
+			if token.Status == models.CoverageUnknown {
+				if i, ok := unknownIdx[v.ID]; ok {
+					report.UnknownWords[i].Occurrences++
+				} else {
+					unknownIdx[v.ID] = len(report.UnknownWords)
+					report.UnknownWords = append(report.UnknownWords, models.CoverageWord{
+						ID:          v.ID,
+						Chinese:     v.Chinese,
+						English:     v.English,
+						Occurrences: 1,
+					})
+					report.UnknownWordIDs = append(report.UnknownWordIDs, v.ID)
+				}
+			}
+		}
+
+		switch token.Status {
+		case models.CoverageKnown:
+			report.KnownTokens++
+		case models.CoverageInProgress:
+			report.InProgressTokens++
+		default:
+			report.UnknownTokens++
+		}
+		report.Tokens = append(report.Tokens, token)
+	}
+
+	report.TotalTokens = len(report.Tokens)
+	if report.TotalTokens > 0 {
+		total := float64(report.TotalTokens)
+		report.KnownPercent = float64(report.KnownTokens) / total * 100
+		report.InProgressPercent = float64(report.InProgressTokens) / total * 100
+		report.UnknownPercent = float64(report.UnknownTokens) / total * 100
+	}
+
+	return report, nil
+}
+
+// loadVocabulary returns every word keyed by ID along with its mastery state
+func (s *AnalysisService) loadVocabulary(ctx context.Context) (map[int64]vocabEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT w.id, w.chinese, w.english, COALESCE(wm.state, ?)
+		FROM words w
+		LEFT JOIN word_mastery wm ON wm.word_id = w.id
+	`, models.MasteryNew)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vocabulary: %w", err)
+	}
+	defer rows.Close()
+
+	vocab := make(map[int64]vocabEntry)
+	for rows.Next() {
+		var v vocabEntry
+		if err := rows.Scan(&v.ID, &v.Chinese, &v.English, &v.Mastery); err != nil {
+			return nil, fmt.Errorf("failed to scan vocabulary word: %w", err)
+		}
+		vocab[v.ID] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating vocabulary: %w", err)
+	}
+
+	return vocab, nil
+}
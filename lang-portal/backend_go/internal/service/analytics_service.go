@@ -0,0 +1,519 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+)
+
+const (
+	// DefaultConfusionLimit is the number of pairs returned per group by default
+	DefaultConfusionLimit = 10
+	// MaxConfusionLimit caps the number of pairs returned per group
+	MaxConfusionLimit = 100
+	// MaxSeriesBuckets caps the length of a review time series
+	MaxSeriesBuckets = 400
+	// DefaultForecastDays is the forecast horizon used when none is given
+	DefaultForecastDays = 30
+	// MaxForecastDays caps the forecast horizon
+	MaxForecastDays = 365
+	// MaxForecastNewPerDay caps the what-if number of new words per day
+	MaxForecastNewPerDay = 200
+)
+
+// AnalyticsService reports on the learner's review history
+type AnalyticsService struct {
+	db  database.DBTX
+	now func() time.Time
+}
+
+// NewAnalyticsService creates a new AnalyticsService
+func NewAnalyticsService(db *sql.DB) *AnalyticsService {
+	return &AnalyticsService{db: database.Handle(db), now: time.Now}
+}
+
+// ReviewSeriesOptions selects the period and reviews of a time series.
+// From and To are learner-local dates (YYYY-MM-DD); empty values default to
+// the last 30 days, 12 weeks or 12 months. Zero IDs do not filter.
+type ReviewSeriesOptions struct {
+	Bucket     string
+	From       string
+	To         string
+	GroupID    int64
+	ActivityID int64
+}
+
+// GetReviewSeries returns review counts, accuracy and new words per day, week
+// or month in the learner's timezone. Periods without reviews are included.
+func (s *AnalyticsService) GetReviewSeries(ctx context.Context, opts ReviewSeriesOptions) (*models.ReviewSeries, error) {
+	if opts.Bucket == "" {
+		opts.Bucket = models.BucketDay
+	}
+	if opts.Bucket != models.BucketDay && opts.Bucket != models.BucketWeek && opts.Bucket != models.BucketMonth {
+		return nil, fmt.Errorf("%w: unknown bucket %q", ErrInvalidRange, opts.Bucket)
+	}
+
+	loc, err := s.location(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	to, _ := time.Parse(dateLayout, s.now().In(loc).Format(dateLayout))
+	if opts.To != "" {
+		if to, err = time.Parse(dateLayout, opts.To); err != nil {
+			return nil, fmt.Errorf("%w: to must be YYYY-MM-DD", ErrInvalidRange)
+		}
+	}
+	var from time.Time
+	if opts.From != "" {
+		if from, err = time.Parse(dateLayout, opts.From); err != nil {
+			return nil, fmt.Errorf("%w: from must be YYYY-MM-DD", ErrInvalidRange)
+		}
+	} else {
+		from = bucketStart(to, opts.Bucket)
+		for i := 1; i < defaultSeriesLength(opts.Bucket); i++ {
+			from = bucketStart(from.AddDate(0, 0, -1), opts.Bucket)
+		}
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("%w: from is after to", ErrInvalidRange)
+	}
+
+	var starts []time.Time
+	for b := bucketStart(from, opts.Bucket); !b.After(to); b = nextBucket(b, opts.Bucket) {
+		if len(starts) == MaxSeriesBuckets {
+			return nil, fmt.Errorf("%w: more than %d buckets", ErrInvalidRange, MaxSeriesBuckets)
+		}
+		starts = append(starts, b)
+	}
+
+	days, err := reviewDays(ctx, s.db, loc, from, to, opts.GroupID, opts.ActivityID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int, len(starts))
+	series := &models.ReviewSeries{
+		Bucket:   opts.Bucket,
+		From:     from.Format(dateLayout),
+		To:       to.Format(dateLayout),
+		Timezone: loc.String(),
+		Buckets:  make([]models.ReviewBucket, len(starts)),
+	}
+	for i, b := range starts {
+		key := b.Format(dateLayout)
+		index[key] = i
+		series.Buckets[i].Start = key
+	}
+	for day, counts := range days {
+		d, _ := time.Parse(dateLayout, day)
+		b := &series.Buckets[index[bucketStart(d, opts.Bucket).Format(dateLayout)]]
+		b.Reviews += counts.Reviews
+		b.Correct += counts.Correct
+		b.NewWords += counts.NewWords
+	}
+	for i := range series.Buckets {
+		if b := &series.Buckets[i]; b.Reviews > 0 {
+			b.Accuracy = float64(b.Correct) / float64(b.Reviews)
+		}
+	}
+
+	return series, nil
+}
+
+// GetHeatmap returns the number of reviews on every local day of a year,
+// with an intensity level relative to the busiest day. A zero year means
+// the current one.
+func (s *AnalyticsService) GetHeatmap(ctx context.Context, year int) (*models.Heatmap, error) {
+	loc, err := s.location(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if year == 0 {
+		year = s.now().In(loc).Year()
+	}
+	if year < 1970 || year > 9999 {
+		return nil, fmt.Errorf("%w: year out of range", ErrInvalidRange)
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	days, err := reviewDays(ctx, s.db, loc, from, to, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := &models.Heatmap{Year: year, Timezone: loc.String(), Days: []models.HeatmapDay{}}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		day := models.HeatmapDay{Date: d.Format(dateLayout)}
+		if counts := days[day.Date]; counts != nil {
+			day.Reviews = counts.Reviews
+			heatmap.TotalReviews += counts.Reviews
+			heatmap.ActiveDays++
+			heatmap.MaxReviews = max(heatmap.MaxReviews, counts.Reviews)
+		}
+		heatmap.Days = append(heatmap.Days, day)
+	}
+	for i := range heatmap.Days {
+		if n := heatmap.Days[i].Reviews; n > 0 {
+			// Quartiles of the busiest day, so any activity shows as at least 1
+			heatmap.Days[i].Level = (4*n + heatmap.MaxReviews - 1) / heatmap.MaxReviews
+		}
+	}
+
+	return heatmap, nil
+}
+
+// location returns the learner's configured timezone
+func (s *AnalyticsService) location(ctx context.Context) (*time.Location, error) {
+	cfg, err := loadSettings(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+	return learnerLocation(cfg)
+}
+
+// reviewCounts totals the reviews of one local day
+type reviewCounts struct {
+	Reviews  int
+	Correct  int
+	NewWords int
+}
+
+// reviewDays returns review totals per learner-local day between two local
+// dates, inclusive. A review is a new word when it is the word's first ever.
+func reviewDays(ctx context.Context, q database.DBTX, loc *time.Location, from, to time.Time, groupID, activityID int64) (map[string]*reviewCounts, error) {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, loc)
+
+	query := `
+		SELECT
+			CAST(strftime('%s', wri.created_at) AS INTEGER) / ? as bucket,
+			COUNT(*),
+			COUNT(CASE WHEN wri.correct = 1 THEN 1 END),
+			COUNT(CASE WHEN wri.id = (SELECT MIN(earliest.id) FROM word_review_items earliest WHERE earliest.word_id = wri.word_id) THEN 1 END)
+		FROM word_review_items wri
+		JOIN study_sessions ss ON ss.id = wri.study_session_id
+		WHERE wri.created_at >= ? AND wri.created_at < ?`
+	args := []interface{}{activityBucket, database.FormatTime(start), database.FormatTime(end)}
+	if groupID != 0 {
+		query += " AND ss.group_id = ?"
+		args = append(args, groupID)
+	}
+	if activityID != 0 {
+		query += " AND ss.study_activity_id = ?"
+		args = append(args, activityID)
+	}
+	query += " GROUP BY bucket"
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch review activity: %w", err)
+	}
+	defer rows.Close()
+
+	days := make(map[string]*reviewCounts)
+	for rows.Next() {
+		var bucket int64
+		var c reviewCounts
+		if err := rows.Scan(&bucket, &c.Reviews, &c.Correct, &c.NewWords); err != nil {
+			return nil, fmt.Errorf("failed to scan review activity: %w", err)
+		}
+		day := time.Unix(bucket*activityBucket, 0).In(loc).Format(dateLayout)
+		if days[day] == nil {
+			days[day] = &reviewCounts{}
+		}
+		days[day].Reviews += c.Reviews
+		days[day].Correct += c.Correct
+		days[day].NewWords += c.NewWords
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review activity: %w", err)
+	}
+	return days, nil
+}
+
+// bucketStart returns the first day of the period containing d
+func bucketStart(d time.Time, bucket string) time.Time {
+	switch bucket {
+	case models.BucketWeek:
+		return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+	case models.BucketMonth:
+		return time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return d
+	}
+}
+
+// nextBucket returns the first day of the period after the one starting at b
+func nextBucket(b time.Time, bucket string) time.Time {
+	switch bucket {
+	case models.BucketWeek:
+		return b.AddDate(0, 0, 7)
+	case models.BucketMonth:
+		return b.AddDate(0, 1, 0)
+	default:
+		return b.AddDate(0, 0, 1)
+	}
+}
+
+// defaultSeriesLength is the number of periods shown when no start date is given
+func defaultSeriesLength(bucket string) int {
+	switch bucket {
+	case models.BucketWeek, models.BucketMonth:
+		return 12
+	default:
+		return 30
+	}
+}
+
+// GetConfusions returns the most confused word pairs per group, most frequent
+// first. A pair counts wrong answers in both directions. A groupID of zero
+// reports every group.
+func (s *AnalyticsService) GetConfusions(ctx context.Context, groupID int64, limit int) ([]models.ConfusionGroup, error) {
+	q := `
+		SELECT
+			g.id,
+			g.name,
+			a.id, a.chinese, a.english,
+			b.id, b.chinese, b.english,
+			COUNT(*) as confusions,
+			MAX(wri.created_at) as last_confused_at
+		FROM word_review_items wri
+		JOIN study_sessions ss ON ss.id = wri.study_session_id
+		JOIN groups g ON g.id = ss.group_id
+		JOIN words a ON a.id = MIN(wri.word_id, wri.answer_word_id)
+		JOIN words b ON b.id = MAX(wri.word_id, wri.answer_word_id)
+		WHERE wri.correct = 0 AND wri.answer_word_id IS NOT NULL`
+	var args []interface{}
+	if groupID != 0 {
+		q += " AND g.id = ?"
+		args = append(args, groupID)
+	}
+	q += `
+		GROUP BY g.id, a.id, b.id
+		ORDER BY g.id ASC, confusions DESC, last_confused_at DESC`
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch confusions: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []models.ConfusionGroup{}
+	for rows.Next() {
+		var g models.ConfusionGroup
+		var p models.ConfusionPair
+		if err := rows.Scan(
+			&g.GroupID, &g.GroupName,
+			&p.Words[0].ID, &p.Words[0].Chinese, &p.Words[0].English,
+			&p.Words[1].ID, &p.Words[1].Chinese, &p.Words[1].English,
+			&p.Count, database.ScanTime(&p.LastConfusedAt),
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan confusion: %w", err)
+		}
+
+		if n := len(groups); n == 0 || groups[n-1].GroupID != g.GroupID {
+			groups = append(groups, g)
+		}
+		cur := &groups[len(groups)-1]
+		if len(cur.Pairs) < limit {
+			cur.Pairs = append(cur.Pairs, p)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating confusions: %w", err)
+	}
+
+	return groups, nil
+}
+
+// ConfusableWordIDs returns the distinct words in the most confused pairs,
+// suitable for building a contrast drill group
+func (s *AnalyticsService) ConfusableWordIDs(ctx context.Context, groupID int64, limit int) ([]int64, error) {
+	groups, err := s.GetConfusions(ctx, groupID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, g := range groups {
+		for _, p := range g.Pairs {
+			for _, w := range p.Words {
+				if !seen[w.ID] {
+					seen[w.ID] = true
+					ids = append(ids, w.ID)
+				}
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, ErrNoConfusions
+	}
+	return ids, nil
+}
+
+// GetForecast estimates the reviews due on each of the coming days, per group,
+// by replaying every word's schedule forward as if each review were answered
+// correctly. With newPerDay above zero it also projects the load of adding
+// that many new words every day.
+func (s *AnalyticsService) GetForecast(ctx context.Context, days, newPerDay int) (*models.ReviewForecast, error) {
+	if days < 1 || days > MaxForecastDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidRange, MaxForecastDays)
+	}
+	if newPerDay < 0 || newPerDay > MaxForecastNewPerDay {
+		return nil, fmt.Errorf("%w: new_per_day must be between 0 and %d", ErrInvalidRange, MaxForecastNewPerDay)
+	}
+
+	loc, err := s.location(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	end := today.AddDate(0, 0, days)
+
+	forecast := &models.ReviewForecast{
+		Days:      days,
+		Timezone:  loc.String(),
+		NewPerDay: newPerDay,
+		Groups:    []models.ForecastGroup{},
+		Forecast:  make([]models.ForecastDay, days),
+	}
+	index := make(map[string]int, days)
+	for i := range forecast.Forecast {
+		date := today.AddDate(0, 0, i).Format(dateLayout)
+		forecast.Forecast[i] = models.ForecastDay{Date: date, Groups: []models.ForecastGroup{}}
+		index[date] = i
+	}
+
+	// dayOf returns the forecast day a review falls on, or false past the horizon
+	dayOf := func(at time.Time) (int, bool) {
+		if at.Before(today) {
+			return 0, true
+		}
+		if !at.Before(end) {
+			return 0, false
+		}
+		i, ok := index[at.In(loc).Format(dateLayout)]
+		return i, ok
+	}
+	// replay walks a schedule forward, calling visit with the day of each review
+	replay := func(sched models.WordSchedule, visit func(day int)) {
+		for {
+			day, ok := dayOf(sched.DueAt)
+			if !ok {
+				return
+			}
+			visit(day)
+			sched = nextSchedule(sched, true, nil, latest(sched.DueAt, now))
+		}
+	}
+
+	schedules, err := s.loadForecastSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	perGroup := make(map[int64][]int)
+	groupIdx := make(map[int64]int)
+	for _, fs := range schedules {
+		for _, g := range fs.Groups {
+			if _, ok := groupIdx[g.GroupID]; !ok {
+				groupIdx[g.GroupID] = len(forecast.Groups)
+				forecast.Groups = append(forecast.Groups, models.ForecastGroup{GroupID: g.GroupID, GroupName: g.GroupName})
+				perGroup[g.GroupID] = make([]int, days)
+			}
+		}
+		replay(fs.Schedule, func(day int) {
+			forecast.Forecast[day].Due++
+			for _, g := range fs.Groups {
+				perGroup[g.GroupID][day]++
+				forecast.Groups[groupIdx[g.GroupID]].Due++
+			}
+		})
+	}
+
+	if newPerDay > 0 {
+		for i := range forecast.Forecast {
+			// A new word is first seen at midday and then scheduled like any other
+			introduced := today.AddDate(0, 0, i).Add(12 * time.Hour)
+			first := nextSchedule(models.WordSchedule{Ease: defaultEase}, true, nil, introduced)
+			forecast.Forecast[i].NewWords = newPerDay
+			replay(first, func(day int) {
+				forecast.Forecast[day].WhatIfReviews += newPerDay
+			})
+		}
+	}
+
+	for i := range forecast.Forecast {
+		day := &forecast.Forecast[i]
+		for _, g := range forecast.Groups {
+			if n := perGroup[g.GroupID][i]; n > 0 {
+				day.Groups = append(day.Groups, models.ForecastGroup{GroupID: g.GroupID, GroupName: g.GroupName, Due: n})
+			}
+		}
+		day.Total = day.Due + day.NewWords + day.WhatIfReviews
+	}
+
+	return forecast, nil
+}
+
+// forecastSchedule is a scheduled word and the groups it belongs to
+type forecastSchedule struct {
+	Schedule models.WordSchedule
+	Groups   []models.ForecastGroup
+}
+
+// loadForecastSchedules returns the schedule of every unsuspended word that has one
+func (s *AnalyticsService) loadForecastSchedules(ctx context.Context) ([]*forecastSchedule, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT ws.word_id, ws.repetitions, ws.interval_days, ws.ease, ws.due_at, ws.last_reviewed_at, g.id, g.name
+		FROM word_schedules ws
+		JOIN words w ON w.id = ws.word_id
+		LEFT JOIN words_groups wg ON wg.word_id = w.id
+		LEFT JOIN groups g ON g.id = wg.group_id
+		WHERE `+notSuspended+`
+		ORDER BY ws.word_id ASC, g.id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch word schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []*forecastSchedule
+	for rows.Next() {
+		var sched models.WordSchedule
+		var groupID sql.NullInt64
+		var groupName sql.NullString
+		if err := rows.Scan(
+			&sched.WordID, &sched.Repetitions, &sched.IntervalDays, &sched.Ease,
+			database.ScanTime(&sched.DueAt), database.ScanTime(&sched.LastReviewedAt), &groupID, &groupName,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan word schedule: %w", err)
+		}
+		if n := len(schedules); n == 0 || schedules[n-1].Schedule.WordID != sched.WordID {
+			schedules = append(schedules, &forecastSchedule{Schedule: sched})
+		}
+		if groupID.Valid {
+			cur := schedules[len(schedules)-1]
+			cur.Groups = append(cur.Groups, models.ForecastGroup{GroupID: groupID.Int64, GroupName: groupName.String})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// latest returns the later of two times
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"lang-portal/internal/models"
+)
+
+func TestConfusionPairsAndDrillGroup(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 4, 0)
+	analytics := NewAnalyticsService(db)
+
+	if _, err := analytics.ConfusableWordIDs(ctx, 0, 10); !errors.Is(err, ErrNoConfusions) {
+		t.Errorf("ConfusableWordIDs(no reviews) error = %v, want %v", err, ErrNoConfusions)
+	}
+
+	study := NewStudyService(db)
+	for _, r := range []models.WordReviewItem{
+		{WordID: 1, Answer: "w2"},
+		{WordID: 2, Answer: "Word 1"},
+		{WordID: 1, Answer: "w2"},
+		{WordID: 3, Answer: "w4"},
+		{WordID: 3, Answer: "nothing like it"},
+		{WordID: 4, Correct: true, Answer: "w4"},
+	} {
+		r.StudySessionID = 1
+		if _, err := study.RecordWordReview(ctx, r); err != nil {
+			t.Fatalf("RecordWordReview: %v", err)
+		}
+	}
+
+	groups, err := analytics.GetConfusions(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("GetConfusions: %v", err)
+	}
+	if len(groups) != 1 || groups[0].GroupName != "Bench" || len(groups[0].Pairs) != 2 {
+		t.Fatalf("GetConfusions = %+v, want two pairs in Bench", groups)
+	}
+	// Both directions count towards one pair, most frequent first
+	for i, want := range []struct {
+		a, b  int64
+		count int
+	}{{1, 2, 3}, {3, 4, 1}} {
+		p := groups[0].Pairs[i]
+		if p.Words[0].ID != want.a || p.Words[1].ID != want.b || p.Count != want.count {
+			t.Errorf("pair %d = %d/%d x%d, want %d/%d x%d", i, p.Words[0].ID, p.Words[1].ID, p.Count, want.a, want.b, want.count)
+		}
+	}
+
+	ids, err := analytics.ConfusableWordIDs(ctx, 1, 1)
+	if err != nil || len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Fatalf("ConfusableWordIDs(limit 1) = %v, %v; want [1 2]", ids, err)
+	}
+	groupSvc := NewGroupService(db)
+	drill, err := groupSvc.CreateGroup(ctx, "Contrast", ids)
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	withWords, err := groupSvc.GetGroupByID(ctx, drill.ID)
+	if err != nil || len(withWords.Words) != 2 {
+		t.Errorf("drill group = %+v, %v; want words 1 and 2", withWords, err)
+	}
+}
+
+func TestReviewSeriesAndHeatmapInLearnerTimezone(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 2, 0)
+	if _, err := db.Exec(`
+		INSERT INTO word_review_items (word_id, study_session_id, correct, created_at) VALUES
+			(1, 1, 1, '2025-03-10 23:30:00'),
+			(1, 1, 0, '2025-03-11 01:00:00'),
+			(2, 1, 1, '2025-03-13 12:00:00');
+	`); err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+	analytics := NewAnalyticsService(db)
+
+	render := func(s *models.ReviewSeries) []string {
+		out := make([]string, len(s.Buckets))
+		for i, b := range s.Buckets {
+			out[i] = fmt.Sprintf("%s %d/%d new %d", b.Start, b.Correct, b.Reviews, b.NewWords)
+		}
+		return out
+	}
+	opts := ReviewSeriesOptions{From: "2025-03-09", To: "2025-03-14"}
+	series, err := analytics.GetReviewSeries(ctx, opts)
+	if err != nil {
+		t.Fatalf("GetReviewSeries: %v", err)
+	}
+	// Days without reviews are filled in
+	want := []string{
+		"2025-03-09 0/0 new 0", "2025-03-10 1/1 new 1", "2025-03-11 0/1 new 0",
+		"2025-03-12 0/0 new 0", "2025-03-13 1/1 new 1", "2025-03-14 0/0 new 0",
+	}
+	if got := render(series); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("UTC series = %v, want %v", got, want)
+	}
+
+	// Eight hours ahead, the late review on the 10th moves to the 11th
+	if _, err := NewSettingsService(db).UpdateSettings(ctx, []byte(`{"timezone":"Asia/Shanghai"}`)); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	if series, err = analytics.GetReviewSeries(ctx, opts); err != nil {
+		t.Fatalf("GetReviewSeries: %v", err)
+	}
+	want[1], want[2] = "2025-03-10 0/0 new 0", "2025-03-11 1/2 new 1"
+	if got := render(series); fmt.Sprint(got) != fmt.Sprint(want) || series.Timezone != "Asia/Shanghai" || series.Buckets[2].Accuracy != 0.5 {
+		t.Errorf("Shanghai series = %v in %s, want %v", got, series.Timezone, want)
+	}
+
+	opts.Bucket = models.BucketWeek
+	if series, err = analytics.GetReviewSeries(ctx, opts); err != nil {
+		t.Fatalf("GetReviewSeries(week): %v", err)
+	}
+	if got, want := render(series), []string{"2025-03-03 0/0 new 0", "2025-03-10 2/3 new 2"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("weekly series = %v, want %v", got, want)
+	}
+
+	heatmap, err := analytics.GetHeatmap(ctx, 2025)
+	if err != nil {
+		t.Fatalf("GetHeatmap: %v", err)
+	}
+	if len(heatmap.Days) != 365 || heatmap.TotalReviews != 3 || heatmap.ActiveDays != 2 || heatmap.MaxReviews != 2 {
+		t.Errorf("heatmap = %d days, %d reviews on %d days (max %d); want 365 days, 3 reviews on 2 days (max 2)",
+			len(heatmap.Days), heatmap.TotalReviews, heatmap.ActiveDays, heatmap.MaxReviews)
+	}
+	for date, level := range map[string]int{"2025-03-10": 0, "2025-03-11": 4, "2025-03-13": 2} {
+		d, _ := time.Parse(dateLayout, date)
+		if got := heatmap.Days[d.YearDay()-1]; got.Date != date || got.Level != level {
+			t.Errorf("heatmap day %s = %+v, want level %d", date, got, level)
+		}
+	}
+}
+
+func TestForecastAndWhatIf(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 3, 0)
+	if _, err := db.Exec(`
+		INSERT INTO groups (id, name) VALUES (2, 'Other');
+		INSERT INTO words_groups (word_id, group_id) VALUES (2, 2);
+		INSERT INTO word_schedules (word_id, repetitions, interval_days, ease, due_at, last_reviewed_at) VALUES
+			(1, 3, 30, 2.5, '2025-03-05 09:00:00', '2025-02-03 09:00:00'),
+			(2, 3, 30, 2.5, '2025-03-12 10:00:00', '2025-02-10 10:00:00'),
+			(3, 3, 30, 2.5, '2025-03-10 09:00:00', '2025-02-08 09:00:00');
+	`); err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+	if _, err := NewWordService(db).SuspendWord(ctx, 3); err != nil {
+		t.Fatalf("SuspendWord: %v", err)
+	}
+	analytics := NewAnalyticsService(db)
+	analytics.now = func() time.Time { return time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC) }
+
+	forecast, err := analytics.GetForecast(ctx, 7, 0)
+	if err != nil {
+		t.Fatalf("GetForecast: %v", err)
+	}
+	// The overdue word counts today, its next review is past the horizon, and
+	// the suspended word is left out
+	var due []int
+	for _, d := range forecast.Forecast {
+		due = append(due, d.Due)
+	}
+	if fmt.Sprint(due) != "[1 0 1 0 0 0 0]" || forecast.Forecast[2].Date != "2025-03-12" {
+		t.Errorf("due per day = %v from %s, want [1 0 1 0 0 0 0] from 2025-03-10", due, forecast.Forecast[0].Date)
+	}
+	if len(forecast.Groups) != 2 || forecast.Groups[0].Due != 2 || forecast.Groups[1].Due != 1 {
+		t.Errorf("groups = %+v, want Bench with 2 and Other with 1", forecast.Groups)
+	}
+	if g := forecast.Forecast[2].Groups; len(g) != 2 {
+		t.Errorf("groups due on 2025-03-12 = %+v, want both groups", g)
+	}
+
+	whatIf, err := analytics.GetForecast(ctx, 7, 3)
+	if err != nil {
+		t.Fatalf("GetForecast(what-if): %v", err)
+	}
+	for i, d := range whatIf.Forecast {
+		if d.Due != forecast.Forecast[i].Due || d.NewWords != 3 || d.Total != d.Due+d.NewWords+d.WhatIfReviews {
+			t.Errorf("what-if day %s = %+v, want the same due reviews plus 3 new words", d.Date, d)
+		}
+	}
+	// Words introduced today come back for review from tomorrow
+	if whatIf.Forecast[0].WhatIfReviews != 0 || whatIf.Forecast[1].WhatIfReviews < 3 {
+		t.Errorf("what-if reviews = %d today and %d tomorrow, want 0 and at least 3",
+			whatIf.Forecast[0].WhatIfReviews, whatIf.Forecast[1].WhatIfReviews)
+	}
+
+	if _, err := analytics.GetForecast(ctx, 0, 0); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("GetForecast(0 days) error = %v, want %v", err, ErrInvalidRange)
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import "errors"
+
+var (
+	// ErrSessionNotFound is returned when a study session does not exist
+	ErrSessionNotFound = errors.New("study session not found")
+	// ErrSentenceNotFound is returned when a sentence does not exist
+	ErrSentenceNotFound = errors.New("sentence not found")
+	// ErrWordNotFound is returned when a word does not exist
+	ErrWordNotFound = errors.New("word not found")
+	// ErrExampleNotFound is returned when no matching example sentence exists
+	ErrExampleNotFound = errors.New("example not found")
+	// ErrExampleMissingWord is returned when an example sentence does not contain its word
+	ErrExampleMissingWord = errors.New("example sentence must contain the word")
+	// ErrQuestionNotFound is returned when a quiz question does not exist in the session
+	ErrQuestionNotFound = errors.New("question not found")
+	// ErrQuestionAnswered is returned when a quiz question was already answered
+	ErrQuestionAnswered = errors.New("question already answered")
+	// ErrInvalidChoice is returned when a quiz choice is out of range
+	ErrInvalidChoice = errors.New("choice is out of range")
+	// ErrUnknownStrategy is returned when a queue strategy is not registered
+	ErrUnknownStrategy = errors.New("unknown queue strategy")
+	// ErrQueueEmpty is returned when a session's group has no words to study
+	ErrQueueEmpty = errors.New("no words available for this study session")
+	// ErrInvalidSettings is returned when a settings update is rejected
+	ErrInvalidSettings = errors.New("invalid settings")
+	// ErrNoConfusions is returned when no wrong answers matched another word
+	ErrNoConfusions = errors.New("no confused word pairs recorded")
+	// ErrInvalidGoals is returned when a daily goal is negative
+	ErrInvalidGoals = errors.New("daily goals must not be negative")
+	// ErrInvalidRange is returned when an analytics date range or bucket is unusable
+	ErrInvalidRange = errors.New("invalid date range")
+	// ErrGroupNotFound is returned when a group does not exist
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrActivityNotFound is returned when a study activity does not exist
+	ErrActivityNotFound = errors.New("study activity not found")
+	// ErrInvalidStatsScope is returned for a group stats scope other than group or all
+	ErrInvalidStatsScope = errors.New("scope must be group or all")
+	// ErrReviewNotFound is returned when a word review does not exist
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrNoReviews is returned when undoing in a study session without reviews
+	ErrNoReviews = errors.New("no reviews recorded in this study session")
+	// ErrReviewLocked is returned when a review is older than the edit window
+	ErrReviewLocked = errors.New("review can no longer be changed")
+	// ErrReviewUnchanged is returned when re-grading a review to the grade it has
+	ErrReviewUnchanged = errors.New("review already has that grade")
+	// ErrInvalidGrade is returned when a re-grade's grade is out of range or disagrees with correct
+	ErrInvalidGrade = errors.New("invalid grade")
+	// ErrInvalidReviews is returned when a batch of reviews is rejected
+	ErrInvalidReviews = errors.New("invalid reviews")
+	// ErrUnknownWords is returned when a group is created with words that do not exist
+	ErrUnknownWords = errors.New("unknown word ids")
+)
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+)
+
+// clozeDistractors is the number of wrong options offered with a cloze item
+const clozeDistractors = 3
+
+// ExampleService handles word example sentences and the cloze exercise
+type ExampleService struct {
+	db  database.DBTX
+	tx  *database.TxRunner
+	now func() time.Time
+}
+
+// NewExampleService creates a new ExampleService
+func NewExampleService(db *sql.DB) *ExampleService {
+	return &ExampleService{db: database.Handle(db), tx: database.NewTxRunner(db), now: time.Now}
+}
+
+// GetExamples returns the example sentences for a word
+func (s *ExampleService) GetExamples(ctx context.Context, wordID int64) ([]models.WordExample, error) {
+	if _, err := s.wordChinese(ctx, wordID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, word_id, sentence, translation, source, created_at
+		FROM word_examples
+		WHERE word_id = ?
+		ORDER BY id ASC
+	`, wordID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch word examples: %w", err)
+	}
+	defer rows.Close()
+
+	examples := []models.WordExample{}
+	for rows.Next() {
+		e, err := scanExample(rows)
+		if err != nil {
+			return nil, err
+		}
+		examples = append(examples, *e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word examples: %w", err)
+	}
+	return examples, nil
+}
+
+// CreateExample adds an example sentence to a word
+func (s *ExampleService) CreateExample(ctx context.Context, wordID int64, sentence, translation, source string) (*models.WordExample, error) {
+	chinese, err := s.wordChinese(ctx, wordID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(sentence, chinese) {
+		return nil, ErrExampleMissingWord
+	}
+
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO word_examples (word_id, sentence, translation, source)
+		VALUES (?, ?, ?, ?)
+		RETURNING id, word_id, sentence, translation, source, created_at
+	`, wordID, sentence, translation, nullString(source))
+	return scanExample(row)
+}
+
+// UpdateExample replaces the text of an existing example sentence
+func (s *ExampleService) UpdateExample(ctx context.Context, wordID, exampleID int64, sentence, translation, source string) (*models.WordExample, error) {
+	chinese, err := s.wordChinese(ctx, wordID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(sentence, chinese) {
+		return nil, ErrExampleMissingWord
+	}
+
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE word_examples
+		SET sentence = ?, translation = ?, source = ?
+		WHERE id = ? AND word_id = ?
+		RETURNING id, word_id, sentence, translation, source, created_at
+	`, sentence, translation, nullString(source), exampleID, wordID)
+	e, err := scanExample(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrExampleNotFound
+	}
+	return e, err
+}
+
+// DeleteExample removes an example sentence from a word
+func (s *ExampleService) DeleteExample(ctx context.Context, wordID, exampleID int64) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM word_examples WHERE id = ? AND word_id = ?", exampleID, wordID)
+	if err != nil {
+		return fmt.Errorf("failed to delete word example: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrExampleNotFound
+	}
+	return nil
+}
+
+// GetCloze picks an example sentence for a word in the session's group that
+// is not suspended and blanks out the word, offering distractors from the
+// group first
+func (s *ExampleService) GetCloze(ctx context.Context, sessionID int64) (*models.ClozeItem, error) {
+	groupID, err := sessionGroupID(ctx, s.db, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var item models.ClozeItem
+	var sentence string
+	err = s.db.QueryRowContext(ctx, `
+		SELECT we.id, we.word_id, we.sentence, we.translation, w.chinese
+		FROM word_examples we
+		JOIN words w ON w.id = we.word_id
+		JOIN words_groups wg ON wg.word_id = w.id
+		WHERE wg.group_id = ? AND instr(we.sentence, w.chinese) > 0 AND `+notSuspended+`
+		ORDER BY RANDOM()
+		LIMIT 1
+	`, groupID).Scan(&item.ExampleID, &item.WordID, &sentence, &item.Translation, &item.Answer)
+	if err == sql.ErrNoRows {
+		return nil, ErrExampleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cloze example: %w", err)
+	}
+
+	blank := strings.Repeat("＿", utf8.RuneCountInString(item.Answer))
+	item.Sentence = strings.ReplaceAll(sentence, item.Answer, blank)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT w.chinese
+		FROM words w
+		WHERE w.id != ? AND w.chinese != ?
+		GROUP BY w.chinese
+		ORDER BY MAX(w.id IN (SELECT word_id FROM words_groups WHERE group_id = ?)) DESC, RANDOM()
+		LIMIT ?
+	`, item.WordID, item.Answer, groupID, clozeDistractors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cloze distractors: %w", err)
+	}
+	defer rows.Close()
+
+	item.Options = []string{item.Answer}
+	for rows.Next() {
+		var option string
+		if err := rows.Scan(&option); err != nil {
+			return nil, fmt.Errorf("failed to scan cloze distractor: %w", err)
+		}
+		item.Options = append(item.Options, option)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cloze distractors: %w", err)
+	}
+	rand.Shuffle(len(item.Options), func(i, j int) {
+		item.Options[i], item.Options[j] = item.Options[j], item.Options[i]
+	})
+
+	return &item, nil
+}
+
+// AnswerCloze checks an answer to a cloze item and records it as a review of
+// the word. Only examples GetCloze could have picked for the session count:
+// others are not found.
+func (s *ExampleService) AnswerCloze(ctx context.Context, sessionID, exampleID int64, answer string) (*models.ClozeResult, error) {
+	groupID, err := sessionGroupID(ctx, s.db, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.ClozeResult
+	var wordID int64
+	err = s.db.QueryRowContext(ctx, `
+		SELECT w.id, w.chinese
+		FROM word_examples we
+		JOIN words w ON w.id = we.word_id
+		JOIN words_groups wg ON wg.word_id = w.id
+		WHERE we.id = ? AND wg.group_id = ? AND `+notSuspended+`
+	`, exampleID, groupID).Scan(&wordID, &result.Expected)
+	if err == sql.ErrNoRows {
+		return nil, ErrExampleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cloze example: %w", err)
+	}
+
+	result.Correct = strings.TrimSpace(answer) == result.Expected
+	result.Review = models.WordReviewItem{
+		WordID:         wordID,
+		StudySessionID: sessionID,
+		Correct:        result.Correct,
+		Answer:         answer,
+	}
+
+	if err = s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		if err := settleStreaks(ctx, tx, s.now()); err != nil {
+			return err
+		}
+		return insertReview(ctx, tx, &result.Review)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// wordChinese returns the Chinese form of a word, or ErrWordNotFound
+func (s *ExampleService) wordChinese(ctx context.Context, wordID int64) (string, error) {
+	var chinese string
+	err := s.db.QueryRowContext(ctx, "SELECT chinese FROM words WHERE id = ?", wordID).Scan(&chinese)
+	if err == sql.ErrNoRows {
+		return "", ErrWordNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch word: %w", err)
+	}
+	return chinese, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanExample scans a word_examples row
+func scanExample(row rowScanner) (*models.WordExample, error) {
+	var e models.WordExample
+	var source sql.NullString
+	if err := row.Scan(&e.ID, &e.WordID, &e.Sentence, &e.Translation, &source, database.ScanTime(&e.CreatedAt)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan word example: %w", err)
+	}
+	e.Source = source.String
+	return &e, nil
+}
+
+// nullString maps an empty string to NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+)
+
+// MaxStreakFreezes caps how many unspent streak freezes a learner can hold
+const MaxStreakFreezes = 2
+
+// GoalService handles daily goals and streak freezes
+type GoalService struct {
+	db  database.DBTX
+	tx  *database.TxRunner
+	now func() time.Time
+}
+
+// NewGoalService creates a new GoalService
+func NewGoalService(db *sql.DB) *GoalService {
+	return &GoalService{db: database.Handle(db), tx: database.NewTxRunner(db), now: time.Now}
+}
+
+// GetToday returns today's progress towards the daily goals
+func (s *GoalService) GetToday(ctx context.Context) (*models.GoalProgress, error) {
+	return todayProgress(ctx, s.db, s.now())
+}
+
+// UpdateGoals replaces the daily goals and returns today's progress against
+// them. Finished days are settled first, so past weeks keep the goals they
+// were studied under.
+func (s *GoalService) UpdateGoals(ctx context.Context, goals models.DailyGoals) (*models.GoalProgress, error) {
+	if goals.Reviews < 0 || goals.NewWords < 0 || goals.Minutes < 0 {
+		return nil, ErrInvalidGoals
+	}
+
+	err := s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		if err := settleStreaks(ctx, tx, s.now()); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO goals (id, reviews, new_words, minutes, updated_at)
+			VALUES (1, ?, ?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (id) DO UPDATE SET
+				reviews = excluded.reviews,
+				new_words = excluded.new_words,
+				minutes = excluded.minutes,
+				updated_at = excluded.updated_at
+		`, goals.Reviews, goals.NewWords, goals.Minutes); err != nil {
+			return fmt.Errorf("failed to save goals: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetToday(ctx)
+}
+
+// dayActivity is what the learner did on one local day
+type dayActivity struct {
+	Sessions int
+	Reviews  int
+	NewWords int
+	Seconds  float64
+}
+
+// active reports whether the learner studied at all that day
+func (a *dayActivity) active() bool {
+	return a != nil && (a.Sessions > 0 || a.Reviews > 0)
+}
+
+// meets reports whether the day's activity reaches every daily goal
+func (a *dayActivity) meets(g models.DailyGoals) bool {
+	return a.active() &&
+		a.Reviews >= g.Reviews &&
+		a.NewWords >= g.NewWords &&
+		int(a.Seconds/60) >= g.Minutes
+}
+
+// dailyActivity returns activity per learner-local day since the given time.
+// A word counts as new on the day of its first ever review, and a session's
+// minutes (start to last review) count on the day it started.
+func dailyActivity(ctx context.Context, q database.DBTX, loc *time.Location, since time.Time) (map[string]*dayActivity, error) {
+	days := make(map[string]*dayActivity)
+	day := func(t time.Time) *dayActivity {
+		key := t.In(loc).Format(dateLayout)
+		if days[key] == nil {
+			days[key] = &dayActivity{}
+		}
+		return days[key]
+	}
+	from := database.FormatTime(since)
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT ss.created_at, MAX(wri.created_at)
+		FROM study_sessions ss
+		LEFT JOIN word_review_items wri ON wri.study_session_id = ss.id
+		WHERE ss.created_at >= ?
+		GROUP BY ss.id
+	`, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch daily sessions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var start time.Time
+		var end *time.Time
+		if err := rows.Scan(database.ScanTime(&start), database.ScanNullTime(&end)); err != nil {
+			return nil, fmt.Errorf("failed to scan daily session: %w", err)
+		}
+		d := day(start)
+		d.Sessions++
+		if end != nil && end.After(start) {
+			d.Seconds += end.Sub(start).Seconds()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating daily sessions: %w", err)
+	}
+
+	reviews, err := q.QueryContext(ctx, `
+		SELECT
+			wri.created_at,
+			wri.id = (SELECT MIN(earliest.id) FROM word_review_items earliest WHERE earliest.word_id = wri.word_id)
+		FROM word_review_items wri
+		WHERE wri.created_at >= ?
+	`, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch daily reviews: %w", err)
+	}
+	defer reviews.Close()
+	for reviews.Next() {
+		var at time.Time
+		var first bool
+		if err := reviews.Scan(database.ScanTime(&at), &first); err != nil {
+			return nil, fmt.Errorf("failed to scan daily review: %w", err)
+		}
+		d := day(at)
+		d.Reviews++
+		if first {
+			d.NewWords++
+		}
+	}
+	if err := reviews.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating daily reviews: %w", err)
+	}
+
+	return days, nil
+}
+
+// loadGoals returns the daily goals; no row means no targets
+func loadGoals(ctx context.Context, q database.DBTX) (models.DailyGoals, error) {
+	var g models.DailyGoals
+	err := q.QueryRowContext(ctx, "SELECT reviews, new_words, minutes FROM goals WHERE id = 1").Scan(&g.Reviews, &g.NewWords, &g.Minutes)
+	if err != nil && err != sql.ErrNoRows {
+		return g, fmt.Errorf("failed to fetch goals: %w", err)
+	}
+	return g, nil
+}
+
+// loadStreakState returns the unspent freezes and the last settled day
+func loadStreakState(ctx context.Context, q database.DBTX) (int, string, error) {
+	var freezes int
+	var settled sql.NullString
+	err := q.QueryRowContext(ctx, "SELECT freezes_available, settled_through FROM streak_state WHERE id = 1").Scan(&freezes, &settled)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, "", fmt.Errorf("failed to fetch streak state: %w", err)
+	}
+	return freezes, settled.String, nil
+}
+
+// streakSettlement is how settling the finished days changes the streak state
+type streakSettlement struct {
+	Freezes int      // unspent freezes after settling
+	Spent   []string // days a freeze is spent on
+	Through string   // last settled day; empty when nothing was settled yet
+	Pending bool     // whether any day is left to settle
+}
+
+// pendingSettlement walks every finished day not settled yet without storing
+// anything. A missed day right after an active or frozen day spends a freeze
+// if one is available, and every Monday-to-Sunday week with all daily goals
+// met earns one.
+func pendingSettlement(ctx context.Context, q database.DBTX, now time.Time) (streakSettlement, error) {
+	var plan streakSettlement
+	cfg, err := loadSettings(ctx, q)
+	if err != nil {
+		return plan, err
+	}
+	loc, err := learnerLocation(cfg)
+	if err != nil {
+		return plan, err
+	}
+	today, _ := time.Parse(dateLayout, now.In(loc).Format(dateLayout))
+	yesterday := today.AddDate(0, 0, -1)
+
+	if plan.Freezes, plan.Through, err = loadStreakState(ctx, q); err != nil {
+		return plan, err
+	}
+
+	var start time.Time
+	if plan.Through != "" {
+		if start, err = time.Parse(dateLayout, plan.Through); err != nil {
+			return plan, fmt.Errorf("failed to parse settled day: %w", err)
+		}
+		start = start.AddDate(0, 0, 1)
+	} else {
+		var days []string
+		if days, err = activityDays(ctx, q, loc); err != nil {
+			return plan, err
+		}
+		for _, d := range days {
+			if first, _ := time.Parse(dateLayout, d); start.IsZero() || first.Before(start) {
+				start = first
+			}
+		}
+	}
+	if start.IsZero() || start.After(yesterday) {
+		return plan, nil
+	}
+
+	// Load from the Monday of the first week so perfect weeks can be judged,
+	// plus one day so a missed first day can see the day before it
+	weekStart := start.AddDate(0, 0, -((int(start.Weekday())+6)%7)-1)
+	since := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, loc)
+	acts, err := dailyActivity(ctx, q, loc, since)
+	if err != nil {
+		return plan, err
+	}
+	goals, err := loadGoals(ctx, q)
+	if err != nil {
+		return plan, err
+	}
+	frozenList, err := frozenDays(ctx, q)
+	if err != nil {
+		return plan, err
+	}
+	frozen := make(map[string]bool, len(frozenList))
+	for _, d := range frozenList {
+		frozen[d] = true
+	}
+
+	for d := start; !d.After(yesterday); d = d.AddDate(0, 0, 1) {
+		key := d.Format(dateLayout)
+		prev := d.AddDate(0, 0, -1).Format(dateLayout)
+		if !acts[key].active() && !frozen[key] && plan.Freezes > 0 && (acts[prev].active() || frozen[prev]) {
+			plan.Spent = append(plan.Spent, key)
+			frozen[key] = true
+			plan.Freezes--
+		}
+
+		if d.Weekday() == time.Sunday {
+			perfect := true
+			for i := 0; i < 7 && perfect; i++ {
+				perfect = acts[d.AddDate(0, 0, -i).Format(dateLayout)].meets(goals)
+			}
+			if perfect {
+				plan.Freezes = min(plan.Freezes+1, MaxStreakFreezes)
+			}
+		}
+	}
+	plan.Through = yesterday.Format(dateLayout)
+	plan.Pending = true
+	return plan, nil
+}
+
+// settleStreaks stores the pending settlement. Everything that writes
+// reviews, sessions, goals or settings calls it in its transaction first, so
+// reads only ever preview what is left to settle.
+func settleStreaks(ctx context.Context, tx database.DBTX, now time.Time) error {
+	plan, err := pendingSettlement(ctx, tx, now)
+	if err != nil || !plan.Pending {
+		return err
+	}
+
+	for _, day := range plan.Spent {
+		if _, err = tx.ExecContext(ctx, "INSERT INTO streak_freezes (day) VALUES (?)", day); err != nil {
+			return fmt.Errorf("failed to spend streak freeze: %w", err)
+		}
+	}
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO streak_state (id, freezes_available, settled_through, updated_at)
+		VALUES (1, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (id) DO UPDATE SET
+			freezes_available = excluded.freezes_available,
+			settled_through = excluded.settled_through,
+			updated_at = excluded.updated_at
+	`, plan.Freezes, plan.Through); err != nil {
+		return fmt.Errorf("failed to save streak state: %w", err)
+	}
+	return nil
+}
+
+// todayProgress reports the learner's activity today against the daily goals
+func todayProgress(ctx context.Context, q database.DBTX, now time.Time) (*models.GoalProgress, error) {
+	cfg, err := loadSettings(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := learnerLocation(cfg)
+	if err != nil {
+		return nil, err
+	}
+	local := now.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	acts, err := dailyActivity(ctx, q, loc, midnight)
+	if err != nil {
+		return nil, err
+	}
+	goals, err := loadGoals(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := pendingSettlement(ctx, q, now)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &models.GoalProgress{
+		Date:          local.Format(dateLayout),
+		Timezone:      loc.String(),
+		Goals:         goals,
+		StreakFreezes: plan.Freezes,
+	}
+	if a := acts[progress.Date]; a != nil {
+		progress.Reviews = a.Reviews
+		progress.NewWords = a.NewWords
+		progress.Minutes = int(a.Seconds / 60)
+		progress.Completed = a.meets(goals)
+	}
+	return progress, nil
+}
@@ -1,38 +1,52 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"lang-portal/internal/database"
 	"lang-portal/internal/database/query"
 	"lang-portal/internal/models"
 )
 
 // GroupService handles group-related business logic
 type GroupService struct {
-	db *sql.DB
+	db database.DBTX
+	tx *database.TxRunner
+	// read serves read-only queries; NewServices points it at the reader pool
+	read database.DBTX
+	now  func() time.Time
 }
 
 // NewGroupService creates a new GroupService
 func NewGroupService(db *sql.DB) *GroupService {
-	return &GroupService{db: db}
+	return &GroupService{db: database.Handle(db), tx: database.NewTxRunner(db), read: database.Handle(db), now: time.Now}
 }
 
-// GetGroups returns a paginated list of groups
-func (s *GroupService) GetGroups(page, perPage int) (*models.PaginatedResponse[models.Group], error) {
+// GetGroups returns a paginated list of groups with their stats in the given scope
+func (s *GroupService) GetGroups(ctx context.Context, page, perPage int, scope string) (*models.PaginatedResponse[models.GroupWithStats], error) {
+	scope, err := statsScope(scope)
+	if err != nil {
+		return nil, err
+	}
+
 	q := query.New("SELECT * FROM groups")
 	q.OrderBy("name ASC").Paginate(page, perPage)
 
-	rows, err := q.Execute(s.db)
+	rows, err := q.Execute(ctx, s.read)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch groups: %w", err)
 	}
 	defer rows.Close()
 
-	var groups []models.Group
+	var groups []models.GroupWithStats
 	for rows.Next() {
-		var g models.Group
-		if err := rows.Scan(&g.ID, &g.Name, &g.CreatedAt); err != nil {
+		var g models.GroupWithStats
+		if err := rows.Scan(&g.ID, &g.Name, database.ScanTime(&g.CreatedAt)); err != nil {
 			return nil, fmt.Errorf("failed to scan group: %w", err)
 		}
 		groups = append(groups, g)
@@ -41,13 +55,26 @@ func (s *GroupService) GetGroups(page, perPage int) (*models.PaginatedResponse[m
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating groups: %w", err)
 	}
+	rows.Close()
 
-	total, err := q.ExecuteCount(s.db)
+	ids := make([]int64, len(groups))
+	for i := range groups {
+		ids[i] = groups[i].ID
+	}
+	stats, err := s.groupStats(ctx, ids, scope)
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		groups[i].Stats = *stats[groups[i].ID]
+	}
+
+	total, err := q.ExecuteCount(ctx, s.read)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count groups: %w", err)
 	}
 
-	return &models.PaginatedResponse[models.Group]{
+	return &models.PaginatedResponse[models.GroupWithStats]{
 		Items: groups,
 		Pagination: models.Pagination{
 			CurrentPage:  page,
@@ -58,18 +85,63 @@ func (s *GroupService) GetGroups(page, perPage int) (*models.PaginatedResponse[m
 	}, nil
 }
 
+// CreateGroup creates a group and attaches the given words to it. If any of
+// the words does not exist nothing is created and ErrUnknownWords names them.
+func (s *GroupService) CreateGroup(ctx context.Context, name string, wordIDs []int64) (*models.Group, error) {
+	var group models.Group
+
+	err := s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO groups (name)
+			VALUES (?)
+			RETURNING id, name, created_at
+		`, name).Scan(&group.ID, &group.Name, database.ScanTime(&group.CreatedAt)); err != nil {
+			return fmt.Errorf("failed to create group: %w", err)
+		}
+
+		seen := make(map[int64]bool, len(wordIDs))
+		var unknown []string
+		for _, wordID := range wordIDs {
+			if seen[wordID] {
+				continue
+			}
+			seen[wordID] = true
+
+			res, err := tx.ExecContext(ctx, `
+				INSERT INTO words_groups (word_id, group_id)
+				SELECT id, ? FROM words WHERE id = ?
+			`, group.ID, wordID)
+			if err != nil {
+				return fmt.Errorf("failed to add word %d to group: %w", wordID, err)
+			}
+			if n, err := res.RowsAffected(); err == nil && n == 0 {
+				unknown = append(unknown, strconv.FormatInt(wordID, 10))
+			}
+		}
+		if len(unknown) > 0 {
+			return fmt.Errorf("%w: %s", ErrUnknownWords, strings.Join(unknown, ", "))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
 // GetGroupByID returns a single group with its words
-func (s *GroupService) GetGroupByID(id int64) (*models.GroupWithWords, error) {
+func (s *GroupService) GetGroupByID(ctx context.Context, id int64) (*models.GroupWithWords, error) {
 	// First get the group
 	var group models.Group
-	err := s.db.QueryRow("SELECT * FROM groups WHERE id = ?", id).
-		Scan(&group.ID, &group.Name, &group.CreatedAt)
+	err := s.read.QueryRowContext(ctx, "SELECT * FROM groups WHERE id = ?", id).
+		Scan(&group.ID, &group.Name, database.ScanTime(&group.CreatedAt))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch group: %w", err)
 	}
 
 	// Then get its words
-	rows, err := s.db.Query(`
+	rows, err := s.read.QueryContext(ctx, `
 		SELECT w.* FROM words w
 		JOIN words_groups wg ON w.id = wg.word_id
 		WHERE wg.group_id = ?
@@ -84,9 +156,12 @@ func (s *GroupService) GetGroupByID(id int64) (*models.GroupWithWords, error) {
 	for rows.Next() {
 		var w models.Word
 		var parts []byte
-		if err := rows.Scan(&w.ID, &w.Chinese, &w.English, &parts, &w.CreatedAt); err != nil {
+		if err := rows.Scan(&w.ID, &w.Chinese, &w.English, &parts, database.ScanTime(&w.CreatedAt)); err != nil {
 			return nil, fmt.Errorf("failed to scan word: %w", err)
 		}
+		if len(parts) > 0 {
+			w.Parts = parts
+		}
 		words = append(words, w)
 	}
 
@@ -101,7 +176,7 @@ func (s *GroupService) GetGroupByID(id int64) (*models.GroupWithWords, error) {
 }
 
 // GetGroupWordsPaginated returns paginated words for a group
-func (s *GroupService) GetGroupWordsPaginated(groupID int64, page, perPage int) (*models.PaginatedResponse[models.Word], error) {
+func (s *GroupService) GetGroupWordsPaginated(ctx context.Context, groupID int64, page, perPage int) (*models.PaginatedResponse[models.Word], error) {
 	if page < 1 {
 		page = 1
 	}
@@ -109,7 +184,7 @@ func (s *GroupService) GetGroupWordsPaginated(groupID int64, page, perPage int)
 		perPage = 100
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.read.QueryContext(ctx, `
         SELECT w.id, w.chinese, w.english, w.parts, w.created_at
         FROM words w
         JOIN words_groups wg ON w.id = wg.word_id
@@ -125,9 +200,13 @@ func (s *GroupService) GetGroupWordsPaginated(groupID int64, page, perPage int)
 	var items []models.Word
 	for rows.Next() {
 		var w models.Word
-		if err := rows.Scan(&w.ID, &w.Chinese, &w.English, &w.Parts, &w.CreatedAt); err != nil {
+		var parts []byte
+		if err := rows.Scan(&w.ID, &w.Chinese, &w.English, &parts, database.ScanTime(&w.CreatedAt)); err != nil {
 			return nil, fmt.Errorf("failed to scan word: %w", err)
 		}
+		if len(parts) > 0 {
+			w.Parts = parts
+		}
 		items = append(items, w)
 	}
 	if err := rows.Err(); err != nil {
@@ -135,7 +214,7 @@ func (s *GroupService) GetGroupWordsPaginated(groupID int64, page, perPage int)
 	}
 
 	var total int
-	if err := s.db.QueryRow(`
+	if err := s.read.QueryRowContext(ctx, `
         SELECT COUNT(*)
         FROM words_groups
         WHERE group_id = ?
@@ -155,7 +234,7 @@ func (s *GroupService) GetGroupWordsPaginated(groupID int64, page, perPage int)
 }
 
 // GetGroupStudySessions returns paginated study sessions for a group
-func (s *GroupService) GetGroupStudySessions(groupID int64, page, perPage int) (*models.PaginatedResponse[models.StudySessionSummary], error) {
+func (s *GroupService) GetGroupStudySessions(ctx context.Context, groupID int64, page, perPage int) (*models.PaginatedResponse[models.StudySessionSummary], error) {
 	if page < 1 {
 		page = 1
 	}
@@ -163,7 +242,7 @@ func (s *GroupService) GetGroupStudySessions(groupID int64, page, perPage int) (
 		perPage = 100
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.read.QueryContext(ctx, `
         SELECT ss.id, ss.group_id, ss.study_activity_id, ss.created_at, g.name as group_name
         FROM study_sessions ss
         JOIN groups g ON g.id = ss.group_id
@@ -179,7 +258,7 @@ func (s *GroupService) GetGroupStudySessions(groupID int64, page, perPage int) (
 	var items []models.StudySessionSummary
 	for rows.Next() {
 		var item models.StudySessionSummary
-		if err := rows.Scan(&item.ID, &item.GroupID, &item.StudyActivityID, &item.CreatedAt, &item.GroupName); err != nil {
+		if err := rows.Scan(&item.ID, &item.GroupID, &item.StudyActivityID, database.ScanTime(&item.CreatedAt), &item.GroupName); err != nil {
 			return nil, fmt.Errorf("failed to scan group session: %w", err)
 		}
 		items = append(items, item)
@@ -189,7 +268,7 @@ func (s *GroupService) GetGroupStudySessions(groupID int64, page, perPage int) (
 	}
 
 	var total int
-	if err := s.db.QueryRow("SELECT COUNT(*) FROM study_sessions WHERE group_id = ?", groupID).Scan(&total); err != nil {
+	if err := s.read.QueryRowContext(ctx, "SELECT COUNT(*) FROM study_sessions WHERE group_id = ?", groupID).Scan(&total); err != nil {
 		return nil, fmt.Errorf("failed to count group sessions: %w", err)
 	}
 
@@ -204,33 +283,174 @@ func (s *GroupService) GetGroupStudySessions(groupID int64, page, perPage int) (
 	}, nil
 }
 
-// GetGroupStats returns statistics for a group
-func (s *GroupService) GetGroupStats(id int64) (*models.GroupStats, error) {
-	var stats models.GroupStats
-
-	err := s.db.QueryRow(`
-		WITH group_stats AS (
-			SELECT 
-				COUNT(DISTINCT w.id) as total_words,
-				COUNT(DISTINCT CASE WHEN wri.id IS NOT NULL THEN w.id END) as studied_words,
-				COALESCE(AVG(CASE WHEN wri.correct THEN 1.0 ELSE 0.0 END), 0) as success_rate,
-				MAX(wri.created_at) as last_studied_at
-			FROM words w
-			JOIN words_groups wg ON w.id = wg.word_id
-			LEFT JOIN word_review_items wri ON w.id = wri.word_id
-			WHERE wg.group_id = ?
-		)
-		SELECT 
-			total_words,
-			studied_words,
-			success_rate * 100,
-			last_studied_at
-		FROM group_stats
-	`, id).Scan(&stats.TotalWords, &stats.StudiedWords, &stats.SuccessRate, &stats.LastStudiedAt)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch group stats: %w", err)
-	}
-
-	return &stats, nil
+// GetGroupStats returns statistics for a group. With the group scope only
+// reviews from the group's own study sessions count; with the all scope every
+// review of the group's words does. Mastery and due dates are per word and
+// do not depend on the scope.
+func (s *GroupService) GetGroupStats(ctx context.Context, id int64, scope string) (*models.GroupStats, error) {
+	scope, err := statsScope(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	if err := s.read.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM groups WHERE id = ?)", id).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check group: %w", err)
+	}
+	if !exists {
+		return nil, ErrGroupNotFound
+	}
+
+	stats, err := s.groupStats(ctx, []int64{id}, scope)
+	if err != nil {
+		return nil, err
+	}
+	return stats[id], nil
+}
+
+// groupStats computes the statistics of several groups (see GetGroupStats)
+// with one query per figure, however many groups there are
+func (s *GroupService) groupStats(ctx context.Context, ids []int64, scope string) (map[int64]*models.GroupStats, error) {
+	stats := make(map[int64]*models.GroupStats, len(ids))
+	if len(ids) == 0 {
+		return stats, nil
+	}
+	groupIDs := make([]interface{}, len(ids))
+	for i, id := range ids {
+		stats[id] = &models.GroupStats{Scope: scope, Activities: []models.ActivityAccuracy{}}
+		groupIDs[i] = id
+	}
+	in := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ") + ")"
+	// Restricts reviews to the group's sessions unless every review counts
+	scoped := "(? = 'all' OR wri.study_session_id IN (SELECT id FROM study_sessions WHERE group_id = wg.group_id))"
+
+	err := s.eachGroupRow(ctx, "group stats", `
+		SELECT
+			wg.group_id,
+			COUNT(DISTINCT w.id) as total_words,
+			COUNT(DISTINCT wri.word_id) as studied_words,
+			COUNT(wri.id) as total_reviews,
+			COALESCE(AVG(CASE WHEN wri.id IS NULL THEN NULL WHEN wri.correct THEN 1.0 ELSE 0.0 END), 0) * 100 as success_rate,
+			MAX(wri.created_at) as last_studied_at
+		FROM words_groups wg
+		JOIN words w ON w.id = wg.word_id
+		LEFT JOIN word_review_items wri ON wri.word_id = w.id AND `+scoped+`
+		WHERE wg.group_id IN `+in+`
+		GROUP BY wg.group_id
+	`, append([]interface{}{scope}, groupIDs...), func(rows *sql.Rows) error {
+		var id int64
+		var g models.GroupStats
+		if err := rows.Scan(&id, &g.TotalWords, &g.StudiedWords, &g.TotalReviews, &g.SuccessRate, database.ScanNullTime(&g.LastStudiedAt)); err != nil {
+			return err
+		}
+		g.Scope, g.Activities = scope, stats[id].Activities
+		*stats[id] = g
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.eachGroupRow(ctx, "mastery breakdown", `
+		SELECT wg.group_id, `+masteryCounts+`
+		FROM words_groups wg
+		JOIN words w ON w.id = wg.word_id
+		LEFT JOIN word_mastery wm ON wm.word_id = w.id
+		WHERE wg.group_id IN `+in+`
+		GROUP BY wg.group_id
+	`, append(masteryStates(), groupIDs...), func(rows *sql.Rows) error {
+		var id int64
+		var b models.MasteryBreakdown
+		if err := rows.Scan(&id, &b.New, &b.Learning, &b.Reviewing, &b.Mastered, &b.Lapsed, &b.Suspended); err != nil {
+			return err
+		}
+		stats[id].Mastery = b
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.eachGroupRow(ctx, "due words", `
+		SELECT wg.group_id, COUNT(*)
+		FROM word_schedules ws
+		JOIN words w ON w.id = ws.word_id
+		JOIN words_groups wg ON wg.word_id = w.id
+		WHERE wg.group_id IN `+in+` AND `+notSuspended+` AND ws.due_at <= ?
+		GROUP BY wg.group_id
+	`, append(groupIDs, database.FormatTime(s.now())), func(rows *sql.Rows) error {
+		var id int64
+		var due int
+		if err := rows.Scan(&id, &due); err != nil {
+			return err
+		}
+		stats[id].DueCount = due
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.eachGroupRow(ctx, "activity accuracy", `
+		SELECT
+			wg.group_id,
+			ss.study_activity_id,
+			sa.name,
+			COUNT(*),
+			COUNT(CASE WHEN wri.correct = 1 THEN 1 END)
+		FROM words_groups wg
+		JOIN word_review_items wri ON wri.word_id = wg.word_id
+		JOIN study_sessions ss ON ss.id = wri.study_session_id
+		JOIN study_activities sa ON sa.id = ss.study_activity_id
+		WHERE wg.group_id IN `+in+` AND `+scoped+`
+		GROUP BY wg.group_id, ss.study_activity_id
+		ORDER BY wg.group_id, ss.study_activity_id ASC
+	`, append(groupIDs, scope), func(rows *sql.Rows) error {
+		var id int64
+		var a models.ActivityAccuracy
+		if err := rows.Scan(&id, &a.ActivityID, &a.ActivityName, &a.Reviews, &a.Correct); err != nil {
+			return err
+		}
+		if a.Reviews > 0 {
+			a.SuccessRate = float64(a.Correct) / float64(a.Reviews) * 100
+		}
+		stats[id].Activities = append(stats[id].Activities, a)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// eachGroupRow runs a query of group statistics and scans every row with scan
+func (s *GroupService) eachGroupRow(ctx context.Context, what, query string, args []interface{}, scan func(*sql.Rows) error) error {
+	rows, err := s.read.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", what, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return fmt.Errorf("failed to scan %s: %w", what, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating %s: %w", what, err)
+	}
+	return nil
+}
+
+// statsScope validates a group stats scope, defaulting to the group scope
+func statsScope(scope string) (string, error) {
+	switch scope {
+	case "":
+		return models.StatsScopeGroup, nil
+	case models.StatsScopeGroup, models.StatsScopeAll:
+		return scope, nil
+	default:
+		return "", ErrInvalidStatsScope
+	}
 }
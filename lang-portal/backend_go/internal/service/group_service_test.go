@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"lang-portal/internal/models"
+)
+
+func TestGroupStatsPerPage(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 3, 0)
+	if _, err := db.Exec(`
+		INSERT INTO groups (id, name) VALUES (2, 'Other');
+		INSERT INTO words_groups (word_id, group_id) VALUES (2, 2), (3, 2);
+		INSERT INTO study_sessions (id, group_id, study_activity_id) VALUES (2, 2, 1);
+	`); err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+	study := NewStudyService(db)
+	for _, r := range []models.WordReviewItem{
+		{WordID: 1, StudySessionID: 1, Correct: false},
+		{WordID: 2, StudySessionID: 1, Correct: true},
+		{WordID: 2, StudySessionID: 2, Correct: true},
+		{WordID: 3, StudySessionID: 2, Correct: true},
+	} {
+		if _, err := study.RecordWordReview(ctx, r); err != nil {
+			t.Fatalf("RecordWordReview: %v", err)
+		}
+	}
+	if _, err := NewWordService(db).SuspendWord(ctx, 3); err != nil {
+		t.Fatalf("SuspendWord: %v", err)
+	}
+
+	// render leaves out the last study time, which is when the test ran
+	render := func(s models.GroupStats) string {
+		out := fmt.Sprintf("%d words, %d studied, %d reviews, %.0f%%, %d due, mastery %+v, activities",
+			s.TotalWords, s.StudiedWords, s.TotalReviews, s.SuccessRate, s.DueCount, s.Mastery)
+		for _, a := range s.Activities {
+			out += fmt.Sprintf(" %d:%d/%d", a.ActivityID, a.Correct, a.Reviews)
+		}
+		return out
+	}
+	groups := NewGroupService(db)
+	for _, tt := range []struct {
+		scope string
+		want  []string
+	}{
+		{"group", []string{
+			"3 words, 2 studied, 2 reviews, 50%, 1 due, mastery {New:0 Learning:1 Reviewing:1 Mastered:0 Lapsed:0 Suspended:1}, activities 1:1/2",
+			"2 words, 2 studied, 2 reviews, 100%, 0 due, mastery {New:0 Learning:0 Reviewing:1 Mastered:0 Lapsed:0 Suspended:1}, activities 1:2/2",
+		}},
+		{"all", []string{
+			"3 words, 3 studied, 4 reviews, 75%, 1 due, mastery {New:0 Learning:1 Reviewing:1 Mastered:0 Lapsed:0 Suspended:1}, activities 1:3/4",
+			"2 words, 2 studied, 3 reviews, 100%, 0 due, mastery {New:0 Learning:0 Reviewing:1 Mastered:0 Lapsed:0 Suspended:1}, activities 1:3/3",
+		}},
+	} {
+		page, err := groups.GetGroups(ctx, 1, 10, tt.scope)
+		if err != nil {
+			t.Fatalf("GetGroups(%s): %v", tt.scope, err)
+		}
+		if len(page.Items) != 2 {
+			t.Fatalf("GetGroups(%s) = %d groups, want 2", tt.scope, len(page.Items))
+		}
+		for i, g := range page.Items {
+			if got := render(g.Stats); got != tt.want[i] || g.Stats.Scope != tt.scope || g.Stats.LastStudiedAt == nil {
+				t.Errorf("%s scope, group %d stats = %q, want %q", tt.scope, g.ID, got, tt.want[i])
+			}
+			single, err := groups.GetGroupStats(ctx, g.ID, tt.scope)
+			if err != nil {
+				t.Fatalf("GetGroupStats(%d): %v", g.ID, err)
+			}
+			if got := render(*single); got != tt.want[i] {
+				t.Errorf("%s scope, GetGroupStats(%d) = %q, want %q", tt.scope, g.ID, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestCreateGroupRejectsUnknownWords(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 2, 0)
+	groups := NewGroupService(db)
+
+	_, err := groups.CreateGroup(ctx, "Mixed", []int64{1, 7, 2, 9, 7})
+	if !errors.Is(err, ErrUnknownWords) || !strings.HasSuffix(err.Error(), ": 7, 9") {
+		t.Errorf("CreateGroup(unknown words) error = %v, want %v naming 7, 9", err, ErrUnknownWords)
+	}
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM groups WHERE name = 'Mixed'").Scan(&n); err != nil || n != 0 {
+		t.Errorf("rejected group stored %d times, %v", n, err)
+	}
+
+	group, err := groups.CreateGroup(ctx, "Known", []int64{2, 1, 2})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	withWords, err := groups.GetGroupByID(ctx, group.ID)
+	if err != nil || len(withWords.Words) != 2 {
+		t.Errorf("GetGroupByID = %+v, %v; want 2 words", withWords, err)
+	}
+}
+
+func TestGroupWordsKeepParts(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 1, 0)
+	if _, err := db.Exec(`
+		UPDATE words SET parts = '{"pinyin":"māo"}';
+		INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES (1, 1, 1);
+	`); err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	page, err := NewGroupService(db).GetGroupWordsPaginated(ctx, 1, 1, 10)
+	if err != nil || len(page.Items) != 1 || string(page.Items[0].Parts) != `{"pinyin":"māo"}` {
+		t.Errorf("GetGroupWordsPaginated = %+v, %v; want one word with its parts", page, err)
+	}
+	words, err := NewStudyService(db).GetStudySessionWords(ctx, 1, 1, 10)
+	if err != nil || len(words.Items) != 1 || string(words.Items[0].Parts) != `{"pinyin":"māo"}` {
+		t.Errorf("GetStudySessionWords = %+v, %v; want one word with its parts", words, err)
+	}
+}
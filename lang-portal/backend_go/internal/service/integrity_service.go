@@ -0,0 +1,475 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+)
+
+// maxRepairPasses bounds how often Repair re-checks: quarantining a row
+// orphans the rows that belong to it, which the next pass picks up
+const maxRepairPasses = 5
+
+// orphanRule is a reference the app relies on, checked explicitly because
+// databases from before migration 014 don't declare it as a foreign key
+type orphanRule struct {
+	table, column, parent string
+}
+
+var orphanRules = []orphanRule{
+	{"words_groups", "word_id", "words"},
+	{"words_groups", "group_id", "groups"},
+	{"study_sessions", "group_id", "groups"},
+	{"word_review_items", "study_session_id", "study_sessions"},
+	{"word_review_items", "word_id", "words"},
+}
+
+// IntegrityService checks the database for broken or orphaned rows and repairs them
+type IntegrityService struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewIntegrityService creates a new IntegrityService
+func NewIntegrityService(db *sql.DB) *IntegrityService {
+	return &IntegrityService{db: db, now: time.Now}
+}
+
+// Check runs SQLite's integrity and foreign key checks and the app's own
+// rules and reports what breaks them. It works on databases that are not
+// fully migrated, so it can find the rows that block a migration.
+func (s *IntegrityService) Check(ctx context.Context) (*models.IntegrityReport, error) {
+	issues, err := checkIntegrity(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+	return s.report(issues, nil), nil
+}
+
+// Repair fixes what Check reports: bad rows are moved to
+// integrity_quarantine and drifted word stats are rebuilt. Issues it cannot
+// fix, such as a corrupt file, are left in the report.
+func (s *IntegrityService) Repair(ctx context.Context) (*models.IntegrityReport, error) {
+	// Foreign keys are off while repairing so quarantining a row doesn't
+	// cascade-delete the rows that belong to it; they get quarantined too.
+	// PRAGMA foreign_keys only applies to its own connection and outside a transaction.
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	var fk int
+	if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&fk); err != nil {
+		return nil, fmt.Errorf("failed to read foreign_keys: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return nil, fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), fmt.Sprintf("PRAGMA foreign_keys = %d", fk))
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	repaired, issues, err := repairIntegrity(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit repairs: %w", err)
+	}
+	return s.report(issues, repaired), nil
+}
+
+func (s *IntegrityService) report(issues, repaired []models.IntegrityIssue) *models.IntegrityReport {
+	if issues == nil {
+		issues = []models.IntegrityIssue{}
+	}
+	return &models.IntegrityReport{
+		CheckedAt: s.now().UTC(),
+		OK:        len(issues) == 0,
+		Issues:    issues,
+		Repaired:  repaired,
+	}
+}
+
+// repairIntegrity repairs issues until a check finds none it can fix and
+// returns the repaired issues and those left
+func repairIntegrity(ctx context.Context, q database.DBTX) ([]models.IntegrityIssue, []models.IntegrityIssue, error) {
+	if _, err := q.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS integrity_quarantine (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			table_name TEXT NOT NULL,
+			row_id INTEGER NOT NULL,
+			data JSON NOT NULL,
+			reason TEXT NOT NULL,
+			quarantined_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		return nil, nil, fmt.Errorf("failed to create quarantine table: %w", err)
+	}
+
+	var repaired []models.IntegrityIssue
+	for pass := 0; ; pass++ {
+		issues, err := checkIntegrity(ctx, q)
+		if err != nil {
+			return nil, nil, err
+		}
+		var fixable []models.IntegrityIssue
+		for _, issue := range issues {
+			if issue.Repair != models.RepairNone {
+				fixable = append(fixable, issue)
+			}
+		}
+		if len(fixable) == 0 || pass == maxRepairPasses {
+			return repaired, issues, nil
+		}
+
+		rebuild := false
+		for _, issue := range fixable {
+			switch issue.Repair {
+			case models.RepairQuarantine:
+				if err := quarantineRow(ctx, q, issue); err != nil {
+					return nil, nil, err
+				}
+			case models.RepairRebuild:
+				rebuild = true
+			}
+			repaired = append(repaired, issue)
+		}
+		if rebuild {
+			if err := rebuildWordStats(ctx, q); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+}
+
+// quarantineRow copies a row, as JSON, to integrity_quarantine and deletes it
+func quarantineRow(ctx context.Context, q database.DBTX, issue models.IntegrityIssue) error {
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %q WHERE rowid = ?", issue.Table), issue.RowID)
+	if err != nil {
+		return fmt.Errorf("failed to read %s row %d: %w", issue.Table, issue.RowID, err)
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return fmt.Errorf("failed to read %s columns: %w", issue.Table, err)
+	}
+	data := make(map[string]interface{}, len(columns))
+	if rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan %s row %d: %w", issue.Table, issue.RowID, err)
+		}
+		for i, col := range columns {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			data[col] = values[i]
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error reading %s row %d: %w", issue.Table, issue.RowID, err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s row %d: %w", issue.Table, issue.RowID, err)
+	}
+	if _, err := q.ExecContext(ctx, `
+		INSERT INTO integrity_quarantine (table_name, row_id, data, reason) VALUES (?, ?, ?, ?)
+	`, issue.Table, issue.RowID, string(body), issue.Problem); err != nil {
+		return fmt.Errorf("failed to quarantine %s row %d: %w", issue.Table, issue.RowID, err)
+	}
+	if _, err := q.ExecContext(ctx, fmt.Sprintf("DELETE FROM %q WHERE rowid = ?", issue.Table), issue.RowID); err != nil {
+		return fmt.Errorf("failed to delete %s row %d: %w", issue.Table, issue.RowID, err)
+	}
+	return nil
+}
+
+// checkIntegrity runs every check and returns the issues found
+func checkIntegrity(ctx context.Context, q database.DBTX) ([]models.IntegrityIssue, error) {
+	issues, err := sqliteIntegrity(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	// A corrupt file makes the other checks meaningless
+	if len(issues) > 0 {
+		return issues, nil
+	}
+
+	tables, err := tableNames(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	add := func(found []models.IntegrityIssue) {
+		for _, issue := range found {
+			key := fmt.Sprintf("%s/%d", issue.Table, issue.RowID)
+			if !seen[key] {
+				seen[key] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	for _, rule := range orphanRules {
+		if !tables[rule.table] || !tables[rule.parent] {
+			continue
+		}
+		found, err := orphans(ctx, q, rule)
+		if err != nil {
+			return nil, err
+		}
+		add(found)
+	}
+
+	found, err := foreignKeyIssues(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	add(found)
+
+	if tables["words_groups"] {
+		found, err := duplicateMemberships(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		add(found)
+	}
+
+	if tables["word_stats"] && tables["word_review_items"] {
+		// Drift is only measured once the reviews themselves are sound
+		found, err := statsDrift(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		add(found)
+	}
+	return issues, nil
+}
+
+// sqliteIntegrity runs PRAGMA integrity_check, which reports corrupt pages
+// and indexes that don't match their tables
+func sqliteIntegrity(ctx context.Context, q database.DBTX) ([]models.IntegrityIssue, error) {
+	rows, err := q.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run integrity check: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []models.IntegrityIssue
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			return nil, fmt.Errorf("failed to scan integrity check: %w", err)
+		}
+		if msg == "ok" {
+			continue
+		}
+		issues = append(issues, models.IntegrityIssue{
+			Rule:    "integrity_check",
+			Problem: msg,
+			Repair:  models.RepairNone,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating integrity check: %w", err)
+	}
+	return issues, nil
+}
+
+// tableNames returns the set of tables in the database
+func tableNames(ctx context.Context, q database.DBTX) (map[string]bool, error) {
+	rows, err := q.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'table'")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tables: %w", err)
+	}
+	return tables, nil
+}
+
+// orphans finds rows whose rule column points at a missing parent row
+func orphans(ctx context.Context, q database.DBTX, rule orphanRule) ([]models.IntegrityIssue, error) {
+	rows, err := q.QueryContext(ctx, fmt.Sprintf(`
+		SELECT c.rowid, c.%[2]s
+		FROM %[1]s c
+		WHERE NOT EXISTS (SELECT 1 FROM %[3]s p WHERE p.id = c.%[2]s)
+		ORDER BY c.rowid
+	`, rule.table, rule.column, rule.parent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orphaned %s rows: %w", rule.table, err)
+	}
+	defer rows.Close()
+
+	var issues []models.IntegrityIssue
+	for rows.Next() {
+		var id int64
+		var ref sql.NullInt64
+		if err := rows.Scan(&id, &ref); err != nil {
+			return nil, fmt.Errorf("failed to scan orphaned %s row: %w", rule.table, err)
+		}
+		issues = append(issues, models.IntegrityIssue{
+			Rule:    "orphan",
+			Table:   rule.table,
+			RowID:   id,
+			Problem: fmt.Sprintf("%s %d refers to a missing %s row", rule.column, ref.Int64, rule.parent),
+			Repair:  models.RepairQuarantine,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating orphaned %s rows: %w", rule.table, err)
+	}
+	return issues, nil
+}
+
+// foreignKeyIssues runs PRAGMA foreign_key_check, which covers every
+// declared reference whether or not foreign keys are being enforced
+func foreignKeyIssues(ctx context.Context, q database.DBTX) ([]models.IntegrityIssue, error) {
+	rows, err := q.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []models.IntegrityIssue
+	for rows.Next() {
+		var table, parent string
+		var rowID sql.NullInt64
+		var fkID int
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return nil, fmt.Errorf("failed to scan foreign key violation: %w", err)
+		}
+		issues = append(issues, models.IntegrityIssue{
+			Rule:    "foreign_key",
+			Table:   table,
+			RowID:   rowID.Int64,
+			Problem: fmt.Sprintf("refers to a missing %s row", parent),
+			Repair:  models.RepairQuarantine,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating foreign key violations: %w", err)
+	}
+	return issues, nil
+}
+
+// duplicateMemberships finds words listed in a group more than once; the
+// first membership is kept
+func duplicateMemberships(ctx context.Context, q database.DBTX) ([]models.IntegrityIssue, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, word_id, group_id
+		FROM words_groups
+		WHERE id NOT IN (SELECT MIN(id) FROM words_groups GROUP BY word_id, group_id)
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []models.IntegrityIssue
+	for rows.Next() {
+		var id, wordID, groupID int64
+		if err := rows.Scan(&id, &wordID, &groupID); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate membership: %w", err)
+		}
+		issues = append(issues, models.IntegrityIssue{
+			Rule:    "duplicate_membership",
+			Table:   "words_groups",
+			RowID:   id,
+			Problem: fmt.Sprintf("word %d is already in group %d", wordID, groupID),
+			Repair:  models.RepairQuarantine,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating duplicate memberships: %w", err)
+	}
+	return issues, nil
+}
+
+// statsDrift finds word_stats totals that no longer match the reviews they
+// are kept from
+func statsDrift(ctx context.Context, q database.DBTX) ([]models.IntegrityIssue, error) {
+	rows, err := q.QueryContext(ctx, `
+		WITH last_wrong AS (
+			SELECT word_id, MAX(id) AS id FROM word_review_items WHERE correct = 0 GROUP BY word_id
+		),
+		expected AS (
+			SELECT
+				wri.word_id,
+				COUNT(CASE WHEN wri.correct = 1 THEN 1 END) AS correct_count,
+				COUNT(CASE WHEN wri.correct = 0 THEN 1 END) AS wrong_count,
+				COUNT(CASE WHEN wri.correct = 1 AND wri.id > COALESCE(lw.id, 0) THEN 1 END) AS streak
+			FROM word_review_items wri
+			LEFT JOIN last_wrong lw ON lw.word_id = wri.word_id
+			GROUP BY wri.word_id
+		)
+		SELECT e.word_id, COALESCE(ws.correct_count, 0), COALESCE(ws.wrong_count, 0), COALESCE(ws.streak, 0),
+			e.correct_count, e.wrong_count, e.streak
+		FROM expected e
+		LEFT JOIN word_stats ws ON ws.word_id = e.word_id
+		WHERE ws.word_id IS NULL OR ws.correct_count != e.correct_count
+			OR ws.wrong_count != e.wrong_count OR ws.streak != e.streak
+		UNION ALL
+		SELECT ws.word_id, ws.correct_count, ws.wrong_count, ws.streak, 0, 0, 0
+		FROM word_stats ws
+		WHERE (ws.correct_count > 0 OR ws.wrong_count > 0)
+			AND NOT EXISTS (SELECT 1 FROM word_review_items wri WHERE wri.word_id = ws.word_id)
+		ORDER BY 1
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare word stats: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []models.IntegrityIssue
+	for rows.Next() {
+		var wordID int64
+		var got, want [3]int
+		if err := rows.Scan(&wordID, &got[0], &got[1], &got[2], &want[0], &want[1], &want[2]); err != nil {
+			return nil, fmt.Errorf("failed to scan word stats: %w", err)
+		}
+		issues = append(issues, models.IntegrityIssue{
+			Rule:    "stats_drift",
+			Table:   "word_stats",
+			RowID:   wordID,
+			Problem: fmt.Sprintf("has %d correct, %d wrong, streak %d; reviews give %d, %d, %d", got[0], got[1], got[2], want[0], want[1], want[2]),
+			Repair:  models.RepairRebuild,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word stats: %w", err)
+	}
+	return issues, nil
+}
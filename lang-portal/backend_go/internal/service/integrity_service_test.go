@@ -0,0 +1,137 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"lang-portal/internal/models"
+)
+
+func TestIntegrityCheckAndRepair(t *testing.T) {
+	// newTestDB doesn't enforce foreign keys, so the bad rows go in as they
+	// did with the old seeding code
+	db := newTestDB(t)
+	for _, q := range []string{
+		"INSERT INTO words (id, chinese, english) VALUES (1, '一', 'one'), (2, '二', 'two')",
+		"INSERT INTO groups (id, name) VALUES (1, 'Numbers')",
+		"INSERT INTO words_groups (id, word_id, group_id) VALUES (1, 1, 1), (2, 2, 1), (3, 99, 1)",
+		"INSERT INTO study_sessions (id, group_id, study_activity_id) VALUES (1, 1, 1), (2, 77, 1)",
+		"INSERT INTO word_review_items (id, word_id, study_session_id, correct) VALUES (1, 1, 1, 1), (2, 2, 2, 1), (3, 1, 55, 0)",
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	s := NewIntegrityService(db)
+	ctx := t.Context()
+
+	report, err := s.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	want := []models.IntegrityIssue{
+		{Rule: "orphan", Table: "words_groups", RowID: 3, Problem: "word_id 99 refers to a missing words row", Repair: models.RepairQuarantine},
+		{Rule: "orphan", Table: "study_sessions", RowID: 2, Problem: "group_id 77 refers to a missing groups row", Repair: models.RepairQuarantine},
+		{Rule: "orphan", Table: "word_review_items", RowID: 3, Problem: "study_session_id 55 refers to a missing study_sessions row", Repair: models.RepairQuarantine},
+		{Rule: "stats_drift", Table: "word_stats", RowID: 1, Problem: "has 0 correct, 0 wrong, streak 0; reviews give 1, 1, 0", Repair: models.RepairRebuild},
+		{Rule: "stats_drift", Table: "word_stats", RowID: 2, Problem: "has 0 correct, 0 wrong, streak 0; reviews give 1, 0, 1", Repair: models.RepairRebuild},
+	}
+	if report.OK || len(report.Issues) != len(want) {
+		t.Fatalf("Check() = %+v, want %d issues", report.Issues, len(want))
+	}
+	for i, issue := range report.Issues {
+		if issue != want[i] {
+			t.Errorf("issue %d = %+v, want %+v", i, issue, want[i])
+		}
+	}
+
+	report, err = s.Repair(ctx)
+	if err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+	if !report.OK || len(report.Issues) != 0 {
+		t.Errorf("Repair() left %+v", report.Issues)
+	}
+	// Session 2's review is orphaned once the session is quarantined and
+	// is picked up by the next pass
+	var quarantined []string
+	rows, err := db.Query("SELECT table_name || ' ' || row_id FROM integrity_quarantine ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var q string
+		if err := rows.Scan(&q); err != nil {
+			t.Fatal(err)
+		}
+		quarantined = append(quarantined, q)
+	}
+	wantQuarantined := []string{"words_groups 3", "study_sessions 2", "word_review_items 3", "word_review_items 2"}
+	if len(quarantined) != len(wantQuarantined) {
+		t.Fatalf("quarantined %v, want %v", quarantined, wantQuarantined)
+	}
+	for i := range quarantined {
+		if quarantined[i] != wantQuarantined[i] {
+			t.Errorf("quarantined %v, want %v", quarantined, wantQuarantined)
+			break
+		}
+	}
+
+	var data string
+	if err := db.QueryRow("SELECT data FROM integrity_quarantine WHERE table_name = 'study_sessions'").Scan(&data); err != nil {
+		t.Fatal(err)
+	}
+	if want := `"group_id":77`; !strings.Contains(data, want) {
+		t.Errorf("quarantined session = %s, want it to hold %s", data, want)
+	}
+
+	var correct, wrong int
+	if err := db.QueryRow("SELECT correct_count, wrong_count FROM word_stats WHERE word_id = 1").Scan(&correct, &wrong); err != nil || correct != 1 || wrong != 0 {
+		t.Errorf("word 1 stats = %d/%d (%v), want 1/0", correct, wrong, err)
+	}
+	var stale int
+	if err := db.QueryRow("SELECT COUNT(*) FROM word_stats WHERE word_id = 2").Scan(&stale); err != nil || stale != 0 {
+		t.Errorf("word 2 has %d stats rows (%v), want 0", stale, err)
+	}
+}
+
+func TestIntegrityDuplicateMemberships(t *testing.T) {
+	db := newTestDB(t)
+	// words_groups as it was before migration 014 made memberships unique
+	for _, q := range []string{
+		"DROP TABLE words_groups",
+		`CREATE TABLE words_groups (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			word_id INTEGER NOT NULL,
+			group_id INTEGER NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		"INSERT INTO words (id, chinese, english) VALUES (1, '一', 'one')",
+		"INSERT INTO groups (id, name) VALUES (1, 'Numbers')",
+		"INSERT INTO words_groups (id, word_id, group_id) VALUES (1, 1, 1), (2, 1, 1)",
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	s := NewIntegrityService(db)
+
+	report, err := s.Check(t.Context())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	want := models.IntegrityIssue{Rule: "duplicate_membership", Table: "words_groups", RowID: 2, Problem: "word 1 is already in group 1", Repair: models.RepairQuarantine}
+	if len(report.Issues) != 1 || report.Issues[0] != want {
+		t.Fatalf("Check() = %+v, want [%+v]", report.Issues, want)
+	}
+
+	report, err = s.Repair(t.Context())
+	if err != nil || !report.OK || len(report.Repaired) != 1 {
+		t.Fatalf("Repair() = %+v, %v; want the duplicate repaired", report, err)
+	}
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM words_groups").Scan(&n); err != nil || n != 1 {
+		t.Errorf("words_groups rows = %d (%v), want 1", n, err)
+	}
+}
@@ -0,0 +1,16 @@
+package service
+
+// notSuspended is a SQL condition that holds for words (aliased w) that are
+// not suspended. Suspended words are left out of queues, quizzes and totals.
+const notSuspended = "NOT EXISTS (SELECT 1 FROM word_flags wf WHERE wf.word_id = w.id AND wf.suspended = 1)"
+
+// isLeech reports whether a word with the given lapse count should be flagged.
+// A word is flagged when it reaches the threshold and again every half
+// threshold after that, so an unsuspended leech that keeps failing returns.
+func isLeech(lapses, threshold int) bool {
+	if threshold <= 0 || lapses < threshold {
+		return false
+	}
+	step := max(threshold/2, 1)
+	return (lapses-threshold)%step == 0
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// nextMastery applies one review to a word's mastery state.
+//
+// A word starts as new and becomes learning on its first review. A run of
+// correct answers moves it to reviewing and then mastered (if its overall
+// success rate is high enough). A wrong answer while reviewing or mastered
+// lapses the word, which stays lapsed until it earns a reviewing streak again.
+//
+// Every wrong answer after the first review counts as a lapse, whatever the
+// state, so a word that never sticks is still caught as a leech.
+func nextMastery(prev models.WordMastery, correct bool, cfg models.Settings) models.WordMastery {
+	next := prev
+	if next.State == "" {
+		next.State = models.MasteryNew
+	}
+
+	if !correct {
+		if next.State != models.MasteryNew {
+			next.Lapses++
+		}
+		next.WrongCount++
+		next.Streak = 0
+		switch prev.State {
+		case models.MasteryReviewing, models.MasteryMastered:
+			next.State = models.MasteryLapsed
+		case models.MasteryLapsed:
+			// Still relearning
+		default:
+			next.State = models.MasteryLearning
+		}
+		return next
+	}
+
+	next.CorrectCount++
+	next.Streak++
+	accuracy := float64(next.CorrectCount) / float64(next.CorrectCount+next.WrongCount)
+
+	switch {
+	case next.Streak >= cfg.MasteryMasteredStreak && accuracy >= cfg.MasteryMasteredAccuracy:
+		next.State = models.MasteryMastered
+	case next.Streak >= cfg.MasteryReviewingStreak:
+		next.State = models.MasteryReviewing
+	case prev.State == models.MasteryLapsed:
+		next.State = models.MasteryLapsed
+	default:
+		next.State = models.MasteryLearning
+	}
+	return next
+}
+
+// loadMastery returns the stored mastery of a word, or a new-word state
+func loadMastery(ctx context.Context, p repository.ProgressRepository, wordID int64) (models.WordMastery, error) {
+	m, err := p.Mastery(ctx, wordID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.WordMastery{WordID: wordID, State: models.MasteryNew}, nil
+	}
+	if err != nil {
+		return models.WordMastery{WordID: wordID}, err
+	}
+	return *m, nil
+}
+
+// rebuildMastery recomputes every word's mastery by replaying its review history
+func rebuildMastery(ctx context.Context, q database.DBTX, cfg models.Settings) error {
+	rows, err := q.QueryContext(ctx, "SELECT word_id, correct FROM word_review_items ORDER BY id ASC")
+	if err != nil {
+		return fmt.Errorf("failed to fetch review history: %w", err)
+	}
+
+	states := make(map[int64]models.WordMastery)
+	var order []int64
+	for rows.Next() {
+		var wordID int64
+		var correct bool
+		if err := rows.Scan(&wordID, &correct); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan review: %w", err)
+		}
+		prev, ok := states[wordID]
+		if !ok {
+			prev = models.WordMastery{WordID: wordID}
+			order = append(order, wordID)
+		}
+		states[wordID] = nextMastery(prev, correct, cfg)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating review history: %w", err)
+	}
+
+	if _, err := q.ExecContext(ctx, "DELETE FROM word_mastery"); err != nil {
+		return fmt.Errorf("failed to clear word mastery: %w", err)
+	}
+	progress := repository.NewSQLite(q).Progress
+	for _, wordID := range order {
+		if err := progress.SaveMastery(ctx, states[wordID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// masteryCounts are the columns counting words (aliased w, with their mastery
+// aliased wm) in each state, in the order of MasteryBreakdown's fields. Words
+// without reviews are new and suspended words are only counted as suspended.
+// Bind masteryStates to them.
+const masteryCounts = `
+	COUNT(CASE WHEN ` + notSuspended + ` AND wm.word_id IS NULL THEN 1 END),
+	COUNT(CASE WHEN ` + notSuspended + ` AND wm.state = ? THEN 1 END),
+	COUNT(CASE WHEN ` + notSuspended + ` AND wm.state = ? THEN 1 END),
+	COUNT(CASE WHEN ` + notSuspended + ` AND wm.state = ? THEN 1 END),
+	COUNT(CASE WHEN ` + notSuspended + ` AND wm.state = ? THEN 1 END),
+	COUNT(CASE WHEN NOT ` + notSuspended + ` THEN 1 END)`
+
+// masteryStates are the arguments of masteryCounts
+func masteryStates() []interface{} {
+	return []interface{}{models.MasteryLearning, models.MasteryReviewing, models.MasteryMastered, models.MasteryLapsed}
+}
+
+// masteryBreakdown counts words per mastery state, optionally only those in a
+// group (zero counts every word)
+func masteryBreakdown(ctx context.Context, q database.DBTX, groupID int64) (models.MasteryBreakdown, error) {
+	var b models.MasteryBreakdown
+	err := q.QueryRowContext(ctx, `
+		SELECT `+masteryCounts+`
+		FROM words w
+		LEFT JOIN word_mastery wm ON wm.word_id = w.id
+		WHERE ? = 0 OR w.id IN (SELECT word_id FROM words_groups WHERE group_id = ?)
+	`, append(masteryStates(), groupID, groupID)...).Scan(
+		&b.New, &b.Learning, &b.Reviewing, &b.Mastered, &b.Lapsed, &b.Suspended,
+	)
+	if err != nil {
+		return b, fmt.Errorf("failed to fetch mastery breakdown: %w", err)
+	}
+	return b, nil
+}
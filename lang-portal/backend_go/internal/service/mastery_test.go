@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+
+	"lang-portal/internal/models"
+)
+
+func TestNextMasteryTransitions(t *testing.T) {
+	cfg := models.DefaultSettings()
+	cfg.MasteryReviewingStreak = 2
+	cfg.MasteryMasteredStreak = 4
+	cfg.MasteryMasteredAccuracy = 0.8
+
+	tests := []struct {
+		name    string
+		answers []bool
+		want    []string
+	}{
+		{
+			name:    "streak to mastered, lapse and relearn",
+			answers: []bool{true, true, true, true, false, true, true},
+			want: []string{
+				models.MasteryLearning, models.MasteryReviewing, models.MasteryReviewing, models.MasteryMastered,
+				models.MasteryLapsed, models.MasteryLapsed, models.MasteryReviewing,
+			},
+		},
+		{
+			name:    "streak without the accuracy stays reviewing",
+			answers: []bool{false, false, true, true, true, true},
+			want: []string{
+				models.MasteryLearning, models.MasteryLearning, models.MasteryLearning,
+				models.MasteryReviewing, models.MasteryReviewing, models.MasteryReviewing,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := models.WordMastery{WordID: 1}
+			for i, correct := range tt.answers {
+				m = nextMastery(m, correct, cfg)
+				if m.State != tt.want[i] {
+					t.Fatalf("after answer %d (%v) state = %s, want %s", i+1, correct, m.State, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestThresholdChangeRebuildsMastery(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 3, 0)
+	study := NewStudyService(db)
+	for _, r := range []models.WordReviewItem{
+		{WordID: 1, StudySessionID: 1, Correct: true},
+		{WordID: 1, StudySessionID: 1, Correct: true},
+		{WordID: 1, StudySessionID: 1, Correct: true},
+		{WordID: 2, StudySessionID: 1, Correct: false},
+	} {
+		if _, err := study.RecordWordReview(ctx, r); err != nil {
+			t.Fatalf("RecordWordReview: %v", err)
+		}
+	}
+
+	breakdown := func() models.MasteryBreakdown {
+		t.Helper()
+		b, err := masteryBreakdown(ctx, db, 0)
+		if err != nil {
+			t.Fatalf("masteryBreakdown: %v", err)
+		}
+		return b
+	}
+	if got, want := breakdown(), (models.MasteryBreakdown{New: 1, Learning: 1, Reviewing: 1}); got != want {
+		t.Errorf("breakdown = %+v, want %+v", got, want)
+	}
+
+	// Three correct answers in a row now master the word
+	if _, err := NewSettingsService(db).UpdateSettings(ctx, []byte(`{"mastery_mastered_streak":3}`)); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	if got, want := breakdown(), (models.MasteryBreakdown{New: 1, Learning: 1, Mastered: 1}); got != want {
+		t.Errorf("breakdown after the threshold change = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+)
+
+// DefaultQueueSettings are used for a session until the client overrides them
+var DefaultQueueSettings = models.QueueSettings{
+	Strategy: models.QueueReasonWeakest,
+	NewRatio: 0.2,
+	NoRepeat: 3,
+}
+
+// QueueOptions overrides the stored queue settings of a session; nil fields are left unchanged
+type QueueOptions struct {
+	Strategy string
+	NewRatio *float64
+	NoRepeat *int
+}
+
+// QueueService picks which word a study session should present next
+type QueueService struct {
+	db         database.DBTX
+	tx         *database.TxRunner
+	strategies map[string]QueueStrategy
+	now        func() time.Time
+}
+
+// NewQueueService creates a new QueueService with the built-in strategies
+func NewQueueService(db *sql.DB) *QueueService {
+	s := &QueueService{
+		db:         database.Handle(db),
+		tx:         database.NewTxRunner(db),
+		strategies: make(map[string]QueueStrategy),
+		now:        time.Now,
+	}
+	s.RegisterStrategy(weakestFirst{})
+	s.RegisterStrategy(dueFirst{})
+	return s
+}
+
+// RegisterStrategy makes a strategy available by name
+func (s *QueueService) RegisterStrategy(strategy QueueStrategy) {
+	s.strategies[strategy.Name()] = strategy
+}
+
+// queueState is the persisted queue of a session
+type queueState struct {
+	Settings            models.QueueSettings
+	CurrentWordID       sql.NullInt64
+	CurrentReason       sql.NullString
+	ServedAfterReviewID int64
+}
+
+// Next returns the word the session should study next. A word that was served
+// but not yet reviewed is returned again, so the queue survives a page reload.
+func (s *QueueService) Next(ctx context.Context, sessionID int64, opts QueueOptions) (*models.QueueItem, error) {
+	var item *models.QueueItem
+	err := s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		groupID, err := sessionGroupID(ctx, tx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		state, err := s.loadState(ctx, tx, sessionID)
+		if err != nil {
+			return err
+		}
+		if opts.Strategy != "" {
+			state.Settings.Strategy = opts.Strategy
+		}
+		if opts.NewRatio != nil {
+			state.Settings.NewRatio = *opts.NewRatio
+		}
+		if opts.NoRepeat != nil {
+			state.Settings.NoRepeat = *opts.NoRepeat
+		}
+		strategy, ok := s.strategies[state.Settings.Strategy]
+		if !ok {
+			return ErrUnknownStrategy
+		}
+
+		item = &models.QueueItem{Settings: state.Settings}
+
+		var served int
+		if err = tx.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM session_queue_items WHERE study_session_id = ?", sessionID,
+		).Scan(&served); err != nil {
+			return fmt.Errorf("failed to count served words: %w", err)
+		}
+
+		pending := false
+		if state.CurrentWordID.Valid {
+			var reviewed bool
+			if err = tx.QueryRowContext(ctx, `
+				SELECT EXISTS (
+					SELECT 1 FROM word_review_items
+					WHERE study_session_id = ? AND word_id = ? AND id > ?
+				)
+			`, sessionID, state.CurrentWordID.Int64, state.ServedAfterReviewID).Scan(&reviewed); err != nil {
+				return fmt.Errorf("failed to check current word: %w", err)
+			}
+			pending = !reviewed
+		}
+
+		if pending {
+			item.Resumed = true
+			item.Reason = state.CurrentReason.String
+			item.Position = served
+			item.Word.ID = state.CurrentWordID.Int64
+		} else {
+			var wordID int64
+			var reason string
+			wordID, reason, err = s.pick(ctx, tx, sessionID, groupID, served, state.Settings, strategy)
+			if err != nil {
+				return err
+			}
+
+			if _, err = tx.ExecContext(ctx, `
+				INSERT INTO session_queue_items (study_session_id, word_id, reason)
+				VALUES (?, ?, ?)
+			`, sessionID, wordID, reason); err != nil {
+				return fmt.Errorf("failed to record served word: %w", err)
+			}
+
+			state.CurrentWordID = sql.NullInt64{Int64: wordID, Valid: true}
+			state.CurrentReason = sql.NullString{String: reason, Valid: true}
+			if err = tx.QueryRowContext(ctx,
+				"SELECT COALESCE(MAX(id), 0) FROM word_review_items WHERE study_session_id = ?", sessionID,
+			).Scan(&state.ServedAfterReviewID); err != nil {
+				return fmt.Errorf("failed to fetch last review: %w", err)
+			}
+
+			item.Reason = reason
+			item.Position = served + 1
+			item.Word.ID = wordID
+		}
+
+		if _, err = tx.ExecContext(ctx, `
+			INSERT INTO session_queues (
+				study_session_id, strategy, new_ratio, no_repeat,
+				current_word_id, current_reason, served_after_review_id, updated_at
+			)
+			VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (study_session_id) DO UPDATE SET
+				strategy = excluded.strategy,
+				new_ratio = excluded.new_ratio,
+				no_repeat = excluded.no_repeat,
+				current_word_id = excluded.current_word_id,
+				current_reason = excluded.current_reason,
+				served_after_review_id = excluded.served_after_review_id,
+				updated_at = excluded.updated_at
+		`, sessionID, state.Settings.Strategy, state.Settings.NewRatio, state.Settings.NoRepeat,
+			state.CurrentWordID, state.CurrentReason, state.ServedAfterReviewID); err != nil {
+			return fmt.Errorf("failed to save session queue: %w", err)
+		}
+
+		// parts is stored as text, which the driver won't scan into json.RawMessage
+		var parts []byte
+		if err = tx.QueryRowContext(ctx, `
+			SELECT id, chinese, english, parts, created_at FROM words WHERE id = ?
+		`, item.Word.ID).Scan(&item.Word.ID, &item.Word.Chinese, &item.Word.English, &parts, database.ScanTime(&item.Word.CreatedAt)); err != nil {
+			return fmt.Errorf("failed to fetch word: %w", err)
+		}
+		if len(parts) > 0 {
+			item.Word.Parts = parts
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// loadState returns the stored queue of a session, or the defaults
+func (s *QueueService) loadState(ctx context.Context, q database.DBTX, sessionID int64) (*queueState, error) {
+	state := &queueState{Settings: DefaultQueueSettings}
+	err := q.QueryRowContext(ctx, `
+		SELECT strategy, new_ratio, no_repeat, current_word_id, current_reason, served_after_review_id
+		FROM session_queues
+		WHERE study_session_id = ?
+	`, sessionID).Scan(
+		&state.Settings.Strategy,
+		&state.Settings.NewRatio,
+		&state.Settings.NoRepeat,
+		&state.CurrentWordID,
+		&state.CurrentReason,
+		&state.ServedAfterReviewID,
+	)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch session queue: %w", err)
+	}
+	return state, nil
+}
+
+// pick chooses the next word from the group, mixing new words in at the configured
+// ratio and skipping anything served within the last NoRepeat items
+func (s *QueueService) pick(ctx context.Context, q database.DBTX, sessionID, groupID int64, served int, settings models.QueueSettings, strategy QueueStrategy) (int64, string, error) {
+	candidates, err := s.loadCandidates(ctx, q, groupID)
+	if err != nil {
+		return 0, "", err
+	}
+	if len(candidates) == 0 {
+		return 0, "", ErrQueueEmpty
+	}
+
+	recent, err := s.recentWords(ctx, q, sessionID, settings.NoRepeat)
+	if err != nil {
+		return 0, "", err
+	}
+	candidates = withoutRecent(candidates, recent)
+
+	var newServed int
+	if err := q.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM session_queue_items
+		WHERE study_session_id = ? AND reason = ?
+	`, sessionID, models.QueueReasonNew).Scan(&newServed); err != nil {
+		return 0, "", fmt.Errorf("failed to count new words served: %w", err)
+	}
+
+	var fresh, review []QueueCandidate
+	for _, c := range candidates {
+		if c.IsNew() {
+			fresh = append(fresh, c)
+		} else {
+			review = append(review, c)
+		}
+	}
+
+	wantNew := float64(newServed) < settings.NewRatio*float64(served+1)
+	if len(fresh) > 0 && (wantNew || len(review) == 0) {
+		// New words are introduced in the order they were added
+		return fresh[0].WordID, models.QueueReasonNew, nil
+	}
+
+	strategy.Rank(review, s.now().UTC())
+	return review[0].WordID, strategy.Name(), nil
+}
+
+// loadCandidates returns every unsuspended word in the group with its review totals and due date
+func (s *QueueService) loadCandidates(ctx context.Context, q database.DBTX, groupID int64) ([]QueueCandidate, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			w.id,
+			COALESCE(wst.correct_count, 0),
+			COALESCE(wst.wrong_count, 0),
+			ws.due_at
+		FROM words w
+		JOIN words_groups wg ON wg.word_id = w.id
+		LEFT JOIN word_stats wst ON wst.word_id = w.id
+		LEFT JOIN word_schedules ws ON ws.word_id = w.id
+		WHERE wg.group_id = ? AND `+notSuspended+`
+		GROUP BY w.id
+		ORDER BY w.id ASC
+	`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch queue candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []QueueCandidate
+	for rows.Next() {
+		var c QueueCandidate
+		if err := rows.Scan(&c.WordID, &c.CorrectCount, &c.WrongCount, database.ScanNullTime(&c.DueAt)); err != nil {
+			return nil, fmt.Errorf("failed to scan queue candidate: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating queue candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// recentWords returns the IDs of the last n words served in the session, newest first
+func (s *QueueService) recentWords(ctx context.Context, q database.DBTX, sessionID int64, n int) ([]int64, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	rows, err := q.QueryContext(ctx, `
+		SELECT word_id FROM session_queue_items
+		WHERE study_session_id = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`, sessionID, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recent words: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan recent word: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// withoutRecent drops recently served words, relaxing the window from the oldest
+// end when the group is too small to honour it
+func withoutRecent(candidates []QueueCandidate, recent []int64) []QueueCandidate {
+	for n := len(recent); n >= 0; n-- {
+		skip := make(map[int64]bool, n)
+		for _, id := range recent[:n] {
+			skip[id] = true
+		}
+		var out []QueueCandidate
+		for _, c := range candidates {
+			if !skip[c.WordID] {
+				out = append(out, c)
+			}
+		}
+		if len(out) > 0 {
+			return out
+		}
+	}
+	return candidates
+}
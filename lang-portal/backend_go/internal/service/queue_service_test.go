@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"lang-portal/internal/models"
+)
+
+func TestQueueStrategiesRank(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	candidates := []QueueCandidate{
+		{WordID: 1, CorrectCount: 3, WrongCount: 1, DueAt: at(-time.Hour)},
+		{WordID: 2, CorrectCount: 1, WrongCount: 1, DueAt: at(time.Hour)},
+		{WordID: 3, CorrectCount: 2, WrongCount: 2, DueAt: nil},
+		{WordID: 4, CorrectCount: 4, WrongCount: 0, DueAt: at(-2 * time.Hour)},
+	}
+	ids := func(cs []QueueCandidate) []int64 {
+		out := make([]int64, len(cs))
+		for i, c := range cs {
+			out[i] = c.WordID
+		}
+		return out
+	}
+
+	for _, tt := range []struct {
+		strategy QueueStrategy
+		want     []int64
+	}{
+		// Lowest success rate first; ties go to the word missed more often
+		{weakestFirst{}, []int64{3, 2, 1, 4}},
+		// Overdue words by due date, then the rest weakest first
+		{dueFirst{}, []int64{4, 1, 3, 2}},
+	} {
+		got := append([]QueueCandidate(nil), candidates...)
+		tt.strategy.Rank(got, now)
+		if g := ids(got); len(g) != len(tt.want) || g[0] != tt.want[0] || g[1] != tt.want[1] || g[2] != tt.want[2] || g[3] != tt.want[3] {
+			t.Errorf("%s ranked %v, want %v", tt.strategy.Name(), g, tt.want)
+		}
+	}
+}
+
+func TestQueueResumesAndSkipsRecentWords(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 4, 0)
+	study := NewStudyService(db)
+	review := func(wordID int64, correct bool) {
+		t.Helper()
+		if _, err := study.RecordWordReview(ctx, models.WordReviewItem{WordID: wordID, StudySessionID: 1, Correct: correct}); err != nil {
+			t.Fatalf("RecordWordReview(%d) error = %v", wordID, err)
+		}
+	}
+	// Word 1 is the weakest; the rest were all answered correctly once
+	review(1, false)
+	review(2, true)
+	review(3, true)
+	review(4, true)
+
+	next := func(s *QueueService, opts QueueOptions) *models.QueueItem {
+		t.Helper()
+		item, err := s.Next(ctx, 1, opts)
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		return item
+	}
+
+	noNew, window := 0.0, 2
+	item := next(NewQueueService(db), QueueOptions{NewRatio: &noNew, NoRepeat: &window})
+	if item.Word.ID != 1 || item.Reason != models.QueueReasonWeakest || item.Resumed {
+		t.Fatalf("first item = word %d (%s, resumed %v), want word 1 (weakest)", item.Word.ID, item.Reason, item.Resumed)
+	}
+	// Not reviewed yet, so a reload serves it again
+	if again := next(NewQueueService(db), QueueOptions{}); again.Word.ID != 1 || !again.Resumed {
+		t.Errorf("reloaded item = word %d (resumed %v), want word 1 resumed", again.Word.ID, again.Resumed)
+	}
+
+	// The settings are stored with the session, so a fresh service keeps the
+	// two-word window: word 1 waits until two other words were served
+	var served []int64
+	for _, correct := range []bool{true, true, true} {
+		review(item.Word.ID, correct)
+		item = next(NewQueueService(db), QueueOptions{})
+		served = append(served, item.Word.ID)
+	}
+	if served[0] != 2 || served[1] != 3 || served[2] != 1 {
+		t.Errorf("served %v after word 1, want [2 3 1]", served)
+	}
+	if item.Settings.NoRepeat != 2 || item.Settings.NewRatio != 0 {
+		t.Errorf("settings = %+v, want the stored no-repeat 2 and new ratio 0", item.Settings)
+	}
+
+	if _, err := NewQueueService(db).Next(ctx, 1, QueueOptions{Strategy: "random"}); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("Next(unknown strategy) error = %v, want %v", err, ErrUnknownStrategy)
+	}
+}
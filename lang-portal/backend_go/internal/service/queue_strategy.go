@@ -0,0 +1,75 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"lang-portal/internal/models"
+)
+
+// QueueCandidate is a previously studied word that could be served next in a session
+type QueueCandidate struct {
+	WordID       int64
+	CorrectCount int
+	WrongCount   int
+	DueAt        *time.Time // nil when the word has no schedule yet
+}
+
+// IsNew reports whether the word has never been reviewed
+func (c QueueCandidate) IsNew() bool {
+	return c.CorrectCount+c.WrongCount == 0
+}
+
+// SuccessRate returns the share of correct reviews, or 0 for a new word
+func (c QueueCandidate) SuccessRate() float64 {
+	total := c.CorrectCount + c.WrongCount
+	if total == 0 {
+		return 0
+	}
+	return float64(c.CorrectCount) / float64(total)
+}
+
+// QueueStrategy orders review candidates so the first one is studied next.
+// New words are mixed in by the queue itself and never reach a strategy.
+type QueueStrategy interface {
+	// Name identifies the strategy in requests and is reported as the pick reason
+	Name() string
+	// Rank sorts candidates in place, best first
+	Rank(candidates []QueueCandidate, now time.Time)
+}
+
+// weakestFirst serves the word with the lowest success rate first
+type weakestFirst struct{}
+
+func (weakestFirst) Name() string { return models.QueueReasonWeakest }
+
+func (weakestFirst) Rank(candidates []QueueCandidate, now time.Time) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.SuccessRate() != b.SuccessRate() {
+			return a.SuccessRate() < b.SuccessRate()
+		}
+		return a.WrongCount > b.WrongCount
+	})
+}
+
+// dueFirst serves the most overdue word first, falling back to the weakest
+type dueFirst struct{}
+
+func (dueFirst) Name() string { return models.QueueReasonDue }
+
+func (dueFirst) Rank(candidates []QueueCandidate, now time.Time) {
+	weakestFirst{}.Rank(candidates, now)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		aDue := a.DueAt != nil && !a.DueAt.After(now)
+		bDue := b.DueAt != nil && !b.DueAt.After(now)
+		if aDue != bDue {
+			return aDue
+		}
+		if aDue && bDue {
+			return a.DueAt.Before(*b.DueAt)
+		}
+		return false
+	})
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+)
+
+const (
+	// quizDistractors is the number of wrong options per question
+	quizDistractors = 3
+	// DefaultQuizCount is the number of questions generated when none is requested
+	DefaultQuizCount = 10
+	// MaxQuizCount caps the number of questions generated in one call
+	MaxQuizCount = 50
+)
+
+// QuizService handles multiple-choice questions for study sessions
+type QuizService struct {
+	db  database.DBTX
+	tx  *database.TxRunner
+	now func() time.Time
+}
+
+// NewQuizService creates a new QuizService
+func NewQuizService(db *sql.DB) *QuizService {
+	return &QuizService{db: database.Handle(db), tx: database.NewTxRunner(db), now: time.Now}
+}
+
+// quizWord is a word loaded for question generation
+type quizWord struct {
+	ID      int64
+	Chinese string
+	English string
+	InGroup bool
+	Active  bool // false when the word is suspended
+	Traits  wordTraits
+}
+
+// side returns the text of the word shown as an option for the given direction
+func (w quizWord) side(direction string, prompt bool) string {
+	chineseFirst := direction == models.DirectionChineseToEnglish
+	if chineseFirst == prompt {
+		return w.Chinese
+	}
+	return w.English
+}
+
+// GenerateQuestions builds multiple-choice questions from the session's group.
+// Distractors are the most similar words in the group, topped up from other groups.
+func (s *QuizService) GenerateQuestions(ctx context.Context, sessionID int64, count int, direction string) ([]models.QuizQuestion, error) {
+	groupID, err := sessionGroupID(ctx, s.db, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	words, err := s.loadWords(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []quizWord
+	for _, w := range words {
+		if w.InGroup && w.Active {
+			targets = append(targets, w)
+		}
+	}
+	rand.Shuffle(len(targets), func(i, j int) { targets[i], targets[j] = targets[j], targets[i] })
+	if len(targets) > count {
+		targets = targets[:count]
+	}
+
+	var questions []models.QuizQuestion
+	err = s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		questions = []models.QuizQuestion{}
+		for _, target := range targets {
+			answer := target.side(direction, false)
+			options := append(pickDistractors(target, words, direction), answer)
+			rand.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+
+			correctIndex := 0
+			for i, o := range options {
+				if o == answer {
+					correctIndex = i
+				}
+			}
+
+			optionsJSON, _ := json.Marshal(options)
+			q := models.QuizQuestion{
+				StudySessionID: sessionID,
+				WordID:         target.ID,
+				Direction:      direction,
+				Prompt:         target.side(direction, true),
+				Options:        options,
+			}
+			if err := tx.QueryRowContext(ctx, `
+				INSERT INTO quiz_questions (study_session_id, word_id, direction, prompt, options, correct_index)
+				VALUES (?, ?, ?, ?, ?, ?)
+				RETURNING id
+			`, sessionID, target.ID, direction, q.Prompt, string(optionsJSON), correctIndex).Scan(&q.ID); err != nil {
+				return fmt.Errorf("failed to create quiz question: %w", err)
+			}
+			questions = append(questions, q)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// AnswerQuestion checks a choice against the stored answer and records a
+// review. A question is answered once: of concurrent answers only the first
+// records a review, the rest get ErrQuestionAnswered.
+func (s *QuizService) AnswerQuestion(ctx context.Context, sessionID, questionID int64, choice int) (*models.QuizAnswerResult, error) {
+	result := models.QuizAnswerResult{QuestionID: questionID, Choice: choice}
+
+	err := s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		var wordID int64
+		var optionsJSON string
+		var chosen sql.NullInt64
+		err := tx.QueryRowContext(ctx, `
+			SELECT word_id, options, correct_index, chosen_index
+			FROM quiz_questions
+			WHERE id = ? AND study_session_id = ?
+		`, questionID, sessionID).Scan(&wordID, &optionsJSON, &result.CorrectIndex, &chosen)
+		if err == sql.ErrNoRows {
+			return ErrQuestionNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch quiz question: %w", err)
+		}
+		if chosen.Valid {
+			return ErrQuestionAnswered
+		}
+
+		var options []string
+		if err := json.Unmarshal([]byte(optionsJSON), &options); err != nil {
+			return fmt.Errorf("failed to decode quiz options: %w", err)
+		}
+		if choice < 0 || choice >= len(options) {
+			return ErrInvalidChoice
+		}
+		result.Correct = choice == result.CorrectIndex
+
+		res, err := tx.ExecContext(ctx, `
+			UPDATE quiz_questions
+			SET chosen_index = ?, answered_at = CURRENT_TIMESTAMP
+			WHERE id = ? AND study_session_id = ? AND chosen_index IS NULL
+		`, choice, questionID, sessionID)
+		if err != nil {
+			return fmt.Errorf("failed to save quiz answer: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to save quiz answer: %w", err)
+		}
+		if n == 0 {
+			return ErrQuestionAnswered
+		}
+
+		result.Review = models.WordReviewItem{
+			WordID:         wordID,
+			StudySessionID: sessionID,
+			Correct:        result.Correct,
+			Answer:         options[choice],
+		}
+		if err := settleStreaks(ctx, tx, s.now()); err != nil {
+			return err
+		}
+		return insertReview(ctx, tx, &result.Review)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// loadWords returns every word, flagging those in the given group and those not suspended
+func (s *QuizService) loadWords(ctx context.Context, groupID int64) ([]quizWord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			w.id,
+			w.chinese,
+			w.english,
+			w.parts,
+			EXISTS (SELECT 1 FROM words_groups wg WHERE wg.word_id = w.id AND wg.group_id = ?) as in_group,
+			`+notSuspended+` as active
+		FROM words w
+	`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch quiz words: %w", err)
+	}
+	defer rows.Close()
+
+	var words []quizWord
+	for rows.Next() {
+		var w quizWord
+		var parts []byte
+		if err := rows.Scan(&w.ID, &w.Chinese, &w.English, &parts, &w.InGroup, &w.Active); err != nil {
+			return nil, fmt.Errorf("failed to scan quiz word: %w", err)
+		}
+		w.Traits = newWordTraits(w.Chinese, parts)
+		words = append(words, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating quiz words: %w", err)
+	}
+	return words, nil
+}
+
+// pickDistractors chooses the most similar words to target, preferring its own group
+func pickDistractors(target quizWord, words []quizWord, direction string) []string {
+	type scored struct {
+		text    string
+		inGroup bool
+		score   int
+	}
+
+	answer := target.side(direction, false)
+	var candidates []scored
+	for _, w := range words {
+		if w.ID == target.ID || w.side(direction, false) == answer {
+			continue
+		}
+		candidates = append(candidates, scored{
+			text:    w.side(direction, false),
+			inGroup: w.InGroup,
+			score:   similarity(target.Traits, w.Traits),
+		})
+	}
+
+	// Shuffle first so equally similar words are picked at random
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].inGroup != candidates[j].inGroup {
+			return candidates[i].inGroup
+		}
+		return candidates[i].score > candidates[j].score
+	})
+
+	seen := make(map[string]bool)
+	var out []string
+	for _, c := range candidates {
+		if len(out) == quizDistractors {
+			break
+		}
+		if seen[c.text] {
+			continue
+		}
+		seen[c.text] = true
+		out = append(out, c.text)
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"lang-portal/internal/models"
+)
+
+func TestAnswerQuestionOnce(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 4, 0)
+	svc := NewQuizService(db)
+
+	questions, err := svc.GenerateQuestions(ctx, 1, 1, models.DirectionChineseToEnglish)
+	if err != nil || len(questions) != 1 {
+		t.Fatalf("GenerateQuestions() = %v, %v; want one question", questions, err)
+	}
+
+	const answers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, answers)
+	for range answers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.AnswerQuestion(ctx, 1, questions[0].ID, 0)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var ok int
+	for err := range errs {
+		switch {
+		case err == nil:
+			ok++
+		case !errors.Is(err, ErrQuestionAnswered):
+			t.Errorf("AnswerQuestion() error = %v, want nil or ErrQuestionAnswered", err)
+		}
+	}
+	var reviews int
+	if err := db.QueryRow("SELECT COUNT(*) FROM word_review_items").Scan(&reviews); err != nil {
+		t.Fatal(err)
+	}
+	if ok != 1 || reviews != 1 {
+		t.Errorf("%d answers accepted and %d reviews recorded, want 1 each", ok, reviews)
+	}
+}
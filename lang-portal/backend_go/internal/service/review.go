@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// insertReview stores a word review and advances its word's progress through
+// the SQLite repositories (see recordReview). Callers should pass a transaction.
+func insertReview(ctx context.Context, q database.DBTX, review *models.WordReviewItem) error {
+	cfg, err := loadSettings(ctx, q)
+	if err != nil {
+		return err
+	}
+	_, err = recordReview(ctx, repository.NewSQLite(q), *cfg, review)
+	return err
+}
+
+// recordReview stores a word review, fills in its ID and timestamp (unless
+// it is already set) and advances the word's stats, schedule and mastery,
+// flagging it as a leech when the review pushes its lapses over the
+// threshold. It returns the word's new mastery. A wrong answer is matched
+// against the vocabulary so confusions can be reported.
+func recordReview(ctx context.Context, repos *repository.Repositories, cfg models.Settings, review *models.WordReviewItem) (models.WordMastery, error) {
+	review.Answer = strings.TrimSpace(review.Answer)
+	if !review.Correct && review.Answer != "" && review.AnswerWordID == nil {
+		id, err := matchAnswer(ctx, repos.Words, review.Answer, review.WordID)
+		if err != nil {
+			return models.WordMastery{}, err
+		}
+		review.AnswerWordID = id
+	}
+	if err := repos.Reviews.Create(ctx, review); err != nil {
+		return models.WordMastery{}, err
+	}
+
+	p := repos.Progress
+	stats, err := loadStats(ctx, p, review.WordID)
+	if err != nil {
+		return models.WordMastery{}, err
+	}
+	if err := p.SaveStats(ctx, review.WordID, countReview(stats, *review)); err != nil {
+		return models.WordMastery{}, err
+	}
+	sched, err := loadSchedule(ctx, p, review.WordID)
+	if err != nil {
+		return models.WordMastery{}, err
+	}
+	if err := p.SaveSchedule(ctx, nextSchedule(sched, review.Correct, review.Grade, review.CreatedAt)); err != nil {
+		return models.WordMastery{}, err
+	}
+	prev, err := loadMastery(ctx, p, review.WordID)
+	if err != nil {
+		return prev, err
+	}
+	next := nextMastery(prev, review.Correct, cfg)
+	if err := p.SaveMastery(ctx, next); err != nil {
+		return next, err
+	}
+	if next.Lapses > prev.Lapses && isLeech(next.Lapses, cfg.LeechThreshold) {
+		if err := p.FlagLeech(ctx, review.WordID, cfg.LeechAutoSuspend); err != nil {
+			return next, err
+		}
+	}
+	return next, nil
+}
+
+// getReview returns a stored word review
+func getReview(ctx context.Context, q database.DBTX, id int64) (models.WordReviewItem, error) {
+	var r models.WordReviewItem
+	err := q.QueryRowContext(ctx, `
+		SELECT id, word_id, study_session_id, correct, COALESCE(answer, ''), answer_word_id, response_ms, grade, created_at
+		FROM word_review_items
+		WHERE id = ?
+	`, id).Scan(&r.ID, &r.WordID, &r.StudySessionID, &r.Correct, &r.Answer, &r.AnswerWordID, &r.ResponseMs, &r.Grade, database.ScanTime(&r.CreatedAt))
+	if err == sql.ErrNoRows {
+		return r, ErrReviewNotFound
+	}
+	if err != nil {
+		return r, fmt.Errorf("failed to fetch word review: %w", err)
+	}
+	return r, nil
+}
+
+// auditReview records a change to a review; after is nil when it was undone
+func auditReview(ctx context.Context, q database.DBTX, action string, before models.WordReviewItem, after *models.WordReviewItem) (models.ReviewAudit, error) {
+	entry := models.ReviewAudit{
+		ReviewID:       before.ID,
+		StudySessionID: before.StudySessionID,
+		WordID:         before.WordID,
+		Action:         action,
+		Before:         before,
+		After:          after,
+	}
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return entry, fmt.Errorf("failed to encode review: %w", err)
+	}
+	var afterJSON sql.NullString
+	if after != nil {
+		data, err := json.Marshal(after)
+		if err != nil {
+			return entry, fmt.Errorf("failed to encode review: %w", err)
+		}
+		afterJSON = sql.NullString{String: string(data), Valid: true}
+	}
+
+	if err := q.QueryRowContext(ctx, `
+		INSERT INTO review_audit (review_id, study_session_id, word_id, action, before, after)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id, created_at
+	`, entry.ReviewID, entry.StudySessionID, entry.WordID, action, string(beforeJSON), afterJSON).Scan(&entry.ID, database.ScanTime(&entry.CreatedAt)); err != nil {
+		return entry, fmt.Errorf("failed to record review change: %w", err)
+	}
+	return entry, nil
+}
+
+// recomputeWord replays a word's remaining reviews into its stats, schedule
+// and mastery after one was undone or re-graded. The leech flag follows the
+// lapses: it is raised as a new review would raise it, and cleared when the
+// word falls back below the threshold, along with a suspension made by the
+// flag itself.
+func recomputeWord(ctx context.Context, repos *repository.Repositories, cfg models.Settings, wordID int64) (models.ReviewChange, error) {
+	var change models.ReviewChange
+	reviews, err := repos.Reviews.ListByWord(ctx, wordID)
+	if err != nil {
+		return change, err
+	}
+	p := repos.Progress
+	prev, err := loadMastery(ctx, p, wordID)
+	if err != nil {
+		return change, err
+	}
+	stats, sched := replayReviews(wordID, reviews)
+	mastery := models.WordMastery{WordID: wordID, State: models.MasteryNew}
+	for _, r := range reviews {
+		mastery = nextMastery(mastery, r.Correct, cfg)
+	}
+
+	if len(reviews) == 0 {
+		if err := p.Clear(ctx, wordID); err != nil {
+			return change, err
+		}
+	} else {
+		if err := p.SaveStats(ctx, wordID, stats); err != nil {
+			return change, err
+		}
+		if err := p.SaveSchedule(ctx, sched); err != nil {
+			return change, err
+		}
+		if err := p.SaveMastery(ctx, mastery); err != nil {
+			return change, err
+		}
+		change.Schedule = &sched
+	}
+
+	switch {
+	case mastery.Lapses > prev.Lapses && isLeech(mastery.Lapses, cfg.LeechThreshold):
+		err = p.FlagLeech(ctx, wordID, cfg.LeechAutoSuspend)
+	case mastery.Lapses < prev.Lapses && mastery.Lapses < cfg.LeechThreshold:
+		err = p.ClearLeech(ctx, wordID)
+	}
+	if err != nil {
+		return change, err
+	}
+
+	flags, err := p.Flags(ctx, wordID)
+	if err != nil {
+		return change, err
+	}
+	stats.Mastery = mastery.State
+	stats.Leech = flags.Leech
+	stats.Suspended = flags.Suspended
+	change.Stats = stats
+	change.Mastery = mastery
+	return change, nil
+}
+
+// matchAnswer returns the word, other than wordID, whose Chinese or English
+// form equals the answer, or nil if none does
+func matchAnswer(ctx context.Context, words repository.WordRepository, answer string, wordID int64) (*int64, error) {
+	w, err := words.FindByForm(ctx, answer, wordID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to match answer: %w", err)
+	}
+	return &w.ID, nil
+}
+
+// sessionGroupID returns the group a study session belongs to
+func sessionGroupID(ctx context.Context, q database.DBTX, sessionID int64) (int64, error) {
+	var groupID int64
+	err := q.QueryRowContext(ctx, "SELECT group_id FROM study_sessions WHERE id = ?", sessionID).Scan(&groupID)
+	if err == sql.ErrNoRows {
+		return 0, ErrSessionNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch study session: %w", err)
+	}
+	return groupID, nil
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// wordState renders what is derived from a word's reviews, leaving out
+// timestamps, so two histories recorded moments apart compare equal
+func wordState(t *testing.T, db *sql.DB, wordID int64) string {
+	t.Helper()
+	var stats, sched, mastery, flags string
+	if err := db.QueryRow(`
+		SELECT
+			COALESCE((SELECT correct_count || '/' || wrong_count || ' streak ' || streak FROM word_stats WHERE word_id = ?1), 'no stats'),
+			COALESCE((SELECT repetitions || ' reps ' || interval_days || 'd ease ' || ease FROM word_schedules WHERE word_id = ?1), 'no schedule'),
+			COALESCE((SELECT state || ' lapses ' || lapses FROM word_mastery WHERE word_id = ?1), 'no mastery'),
+			COALESCE((SELECT 'leech ' || leech || ' suspended ' || suspended FROM word_flags WHERE word_id = ?1), 'no flags')
+	`, wordID).Scan(&stats, &sched, &mastery, &flags); err != nil {
+		t.Fatalf("failed to read word %d state: %v", wordID, err)
+	}
+	return fmt.Sprintf("%s; %s; %s; %s", stats, sched, mastery, flags)
+}
+
+// recordAnswers records reviews of a word in session 1
+func recordAnswers(t *testing.T, svc *StudyService, wordID int64, answers ...bool) []*models.WordReviewItem {
+	t.Helper()
+	var reviews []*models.WordReviewItem
+	for _, correct := range answers {
+		r, err := svc.RecordWordReview(t.Context(), models.WordReviewItem{WordID: wordID, StudySessionID: 1, Correct: correct})
+		if err != nil {
+			t.Fatalf("RecordWordReview: %v", err)
+		}
+		reviews = append(reviews, r)
+	}
+	return reviews
+}
+
+func TestUndoAndRegradeMatchRecordedHistory(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 3, 0)
+	svc := NewStudyService(db)
+
+	// Word 1 gets a misclick that is undone, word 2 one that is re-graded;
+	// word 3 is recorded as the learner meant to answer
+	recordAnswers(t, svc, 1, true, true, false)
+	change, err := svc.UndoLastReview(ctx, 1)
+	if err != nil {
+		t.Fatalf("UndoLastReview: %v", err)
+	}
+	if change.Audit.Action != models.ReviewUndo || change.Audit.After != nil || change.Audit.Before.Correct {
+		t.Errorf("undo audit = %+v, want the wrong review with no after", change.Audit)
+	}
+	if change.Stats.CorrectCount != 2 || change.Stats.WrongCount != 0 || change.Mastery.State != models.MasteryReviewing {
+		t.Errorf("undo left stats %+v, mastery %+v", change.Stats, change.Mastery)
+	}
+	recordAnswers(t, svc, 3, true, true)
+	if got, want := wordState(t, db, 1), wordState(t, db, 3); got != want {
+		t.Errorf("after undo word 1 = %q, want %q", got, want)
+	}
+
+	misclick := recordAnswers(t, svc, 2, true, false, true)[1]
+	if _, err := svc.UpdateReview(ctx, misclick.ID, nil, nil); err != nil {
+		t.Fatalf("UpdateReview: %v", err)
+	}
+	recordAnswers(t, svc, 3, true)
+	if got, want := wordState(t, db, 2), wordState(t, db, 3); got != want {
+		t.Errorf("after re-grade word 2 = %q, want %q", got, want)
+	}
+	correct := true
+	if _, err := svc.UpdateReview(ctx, misclick.ID, &correct, nil); !errors.Is(err, ErrReviewUnchanged) {
+		t.Errorf("re-grading to the same grade error = %v, want ErrReviewUnchanged", err)
+	}
+
+	audit, err := svc.GetReviewAudit(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetReviewAudit: %v", err)
+	}
+	if len(audit) != 2 || audit[0].Action != models.ReviewRegrade || audit[0].ReviewID != misclick.ID ||
+		audit[0].Before.Correct || audit[0].After == nil || !audit[0].After.Correct {
+		t.Errorf("audit = %+v, want the re-grade of review %d then the undo", audit, misclick.ID)
+	}
+
+	// Undoing every review leaves no derived state behind
+	for {
+		if _, err := svc.UndoLastReview(ctx, 1); err != nil {
+			if !errors.Is(err, ErrNoReviews) {
+				t.Fatalf("UndoLastReview: %v", err)
+			}
+			break
+		}
+	}
+	if got := wordState(t, db, 2); got != "no stats; no schedule; no mastery; no flags" {
+		t.Errorf("word 2 with no reviews = %q", got)
+	}
+}
+
+func TestReviewEditWindow(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 1, 0)
+	svc := NewStudyService(db)
+	review := recordAnswers(t, svc, 1, false)[0]
+
+	svc.now = func() time.Time { return time.Now().Add(11 * time.Minute) }
+	if _, err := svc.UndoLastReview(ctx, 1); !errors.Is(err, ErrReviewLocked) {
+		t.Errorf("undo after the window error = %v, want ErrReviewLocked", err)
+	}
+	if _, err := NewSettingsService(db).UpdateSettings(ctx, []byte(`{"review_edit_window_minutes": 15}`)); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	if _, err := svc.UpdateReview(ctx, review.ID, nil, nil); err != nil {
+		t.Errorf("re-grade in a wider window: %v", err)
+	}
+
+	svc.now = time.Now
+	if _, err := NewSettingsService(db).UpdateSettings(ctx, []byte(`{"review_edit_window_minutes": 0}`)); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	if _, err := svc.UpdateReview(ctx, review.ID, nil, nil); !errors.Is(err, ErrReviewLocked) {
+		t.Errorf("re-grade with changes disabled error = %v, want ErrReviewLocked", err)
+	}
+	if _, err := svc.UpdateReview(ctx, review.ID+1, nil, nil); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("re-grading a missing review error = %v, want ErrReviewNotFound", err)
+	}
+}
+
+func TestUndoClearsLeechFromMisclick(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 1, 0)
+	if _, err := NewSettingsService(db).UpdateSettings(ctx, []byte(`{"leech_threshold": 1}`)); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	svc := NewStudyService(db)
+
+	reviews := recordAnswers(t, svc, 1, true, true, false)
+	if got := wordState(t, db, 1); got != "2/1 streak 0; 0 reps 0.0d ease 2.5; lapsed lapses 1; leech 1 suspended 1" {
+		t.Fatalf("after the lapse word 1 = %q", got)
+	}
+
+	change, err := svc.UpdateReview(ctx, reviews[2].ID, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateReview: %v", err)
+	}
+	if change.Stats.Leech || change.Stats.Suspended {
+		t.Errorf("re-graded word is still leech %v, suspended %v", change.Stats.Leech, change.Stats.Suspended)
+	}
+
+	// Flipping it back makes the word a leech again
+	if _, err := svc.UpdateReview(ctx, reviews[2].ID, nil, nil); err != nil {
+		t.Fatalf("UpdateReview: %v", err)
+	}
+	flags, err := repository.NewSQLite(db).Progress.Flags(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !flags.Leech || !flags.Suspended {
+		t.Errorf("flags = %+v, want a suspended leech", flags)
+	}
+}
+
+func TestLeechWhileLearning(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 1, 0)
+	if _, err := NewSettingsService(db).UpdateSettings(ctx, []byte(`{"leech_threshold": 2}`)); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	svc := NewStudyService(db)
+
+	// A word that never reaches reviewing still lapses on every failure after the first
+	recordAnswers(t, svc, 1, false, false)
+	if got := wordState(t, db, 1); got != "0/2 streak 0; 0 reps 0.0d ease 2.1; learning lapses 1; no flags" {
+		t.Errorf("after two failures word 1 = %q", got)
+	}
+	recordAnswers(t, svc, 1, false)
+	if got := wordState(t, db, 1); got != "0/3 streak 0; 0 reps 0.0d ease 1.9; learning lapses 2; leech 1 suspended 1" {
+		t.Errorf("after three failures word 1 = %q", got)
+	}
+}
+
+func TestRecordReviewsBatch(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 3, 0)
+	if _, err := db.Exec("INSERT INTO words (id, chinese, english) VALUES (4, '外', 'outside')"); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewStudyService(db)
+	yes, no := true, false
+	pass, fail, tooHigh := 4, 1, 7
+	var started time.Time
+	if err := db.QueryRow("UPDATE study_sessions SET created_at = datetime('now', '-1 hour') WHERE id = 1 RETURNING created_at").Scan(&started); err != nil {
+		t.Fatal(err)
+	}
+	at := func(seconds int) *time.Time {
+		ts := started.Add(time.Duration(seconds) * time.Second)
+		return &ts
+	}
+
+	invalid := []models.ReviewSubmission{
+		{WordID: 1, Correct: &yes},
+		{WordID: 4, Correct: &yes},
+		{WordID: 2},
+		{WordID: 2, Grade: &tooHigh},
+		{WordID: 2, Correct: &yes, Grade: &fail},
+		{WordID: 3, Correct: &no, AnsweredAt: at(7200)},
+	}
+	_, err := svc.RecordReviews(ctx, 1, invalid)
+	if !errors.Is(err, ErrInvalidReviews) {
+		t.Fatalf("RecordReviews(invalid) error = %v, want ErrInvalidReviews", err)
+	}
+	for _, item := range []string{"item 1:", "item 2:", "item 3:", "item 4:", "item 5:"} {
+		if !strings.Contains(err.Error(), item) {
+			t.Errorf("error %q does not mention %s", err, item)
+		}
+	}
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM word_review_items").Scan(&count); err != nil || count != 0 {
+		t.Errorf("a rejected batch recorded %d reviews (%v), want 0", count, err)
+	}
+	if _, err := svc.RecordReviews(ctx, 99, invalid[:1]); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("RecordReviews(missing session) error = %v, want ErrSessionNotFound", err)
+	}
+
+	// Submitted out of order; word 1 is recorded as wrong, then right, then right
+	result, err := svc.RecordReviews(ctx, 1, []models.ReviewSubmission{
+		{WordID: 1, Grade: &pass, AnsweredAt: at(2)},
+		{WordID: 1, Correct: &no, Grade: &fail, AnsweredAt: at(0)},
+		{WordID: 2, Correct: &yes},
+		{WordID: 1, Correct: &yes, AnsweredAt: at(3)},
+	})
+	if err != nil {
+		t.Fatalf("RecordReviews: %v", err)
+	}
+	if len(result.Items) != 4 {
+		t.Fatalf("RecordReviews returned %d items, want 4", len(result.Items))
+	}
+	for i, item := range result.Items {
+		if item.Index != i || item.Review.ID == 0 {
+			t.Errorf("item %d = %+v, want it recorded in place", i, item)
+		}
+	}
+	first, second := result.Items[1].Review, result.Items[0].Review
+	if first.ID > second.ID || first.Correct || !first.CreatedAt.Equal(*at(0)) || first.Grade == nil || *first.Grade != fail {
+		t.Errorf("earliest review = %+v, want it recorded first as wrong with grade %d", first, fail)
+	}
+	if !second.Correct || result.Items[3].Mastery != models.MasteryReviewing {
+		t.Errorf("grade %d recorded correct %v; last mastery %q, want reviewing", pass, second.Correct, result.Items[3].Mastery)
+	}
+	s := result.Session
+	if s.ID != 1 || s.ReviewItemsCount != 4 || s.CorrectCount != 3 || s.WrongCount != 1 || s.WordsReviewed != 2 || s.EndTime == nil {
+		t.Errorf("session summary = %+v, want 4 reviews of 2 words, 3 correct", s)
+	}
+
+	// Recording the same answers one by one gives word 3 the same state, and
+	// the grades set the ease: 2.5 - 0.54 for a 1, unchanged for a 4, + 0.1 ungraded
+	for _, sub := range []models.ReviewSubmission{
+		{WordID: 3, Correct: &no, Grade: &fail},
+		{WordID: 3, Grade: &pass},
+		{WordID: 3, Correct: &yes},
+	} {
+		if _, err := svc.RecordReviews(ctx, 1, []models.ReviewSubmission{sub}); err != nil {
+			t.Fatalf("RecordReviews: %v", err)
+		}
+	}
+	want := "2/1 streak 2; 2 reps 3.0d ease 2.06; reviewing lapses 0; no flags"
+	if got := wordState(t, db, 1); got != want {
+		t.Errorf("after the batch word 1 = %q, want %q", got, want)
+	}
+	if got := wordState(t, db, 3); got != want {
+		t.Errorf("one by one word 3 = %q, want %q", got, want)
+	}
+
+	// A review older than the word's last one would move its schedule backwards
+	if _, err := svc.RecordReviews(ctx, 1, []models.ReviewSubmission{{WordID: 1, Correct: &yes, AnsweredAt: at(1)}}); !errors.Is(err, ErrInvalidReviews) ||
+		!strings.Contains(err.Error(), "last review") {
+		t.Errorf("RecordReviews(before the last review) error = %v, want ErrInvalidReviews", err)
+	}
+}
+
+func TestRegradeWithGrade(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 1, 0)
+	svc := NewStudyService(db)
+	review := recordAnswers(t, svc, 1, true)[0]
+	yes := true
+	one, four, seven := 1, 4, 7
+
+	change, err := svc.UpdateReview(ctx, review.ID, nil, &one)
+	if err != nil {
+		t.Fatalf("UpdateReview(grade 1): %v", err)
+	}
+	if after := change.Audit.After; after == nil || after.Correct || after.Grade == nil || *after.Grade != 1 {
+		t.Errorf("grade 1 gave %+v, want a wrong review graded 1", after)
+	}
+	if got := wordState(t, db, 1); got != "0/1 streak 0; 0 reps 0.0d ease 1.96; learning lapses 0; no flags" {
+		t.Errorf("after grade 1 word 1 = %q", got)
+	}
+
+	for _, tc := range []struct {
+		correct *bool
+		grade   *int
+		want    error
+	}{
+		{nil, &seven, ErrInvalidGrade},
+		{&yes, &one, ErrInvalidGrade},
+		{nil, &one, ErrReviewUnchanged},
+	} {
+		if _, err := svc.UpdateReview(ctx, review.ID, tc.correct, tc.grade); !errors.Is(err, tc.want) {
+			t.Errorf("UpdateReview(%v, %v) error = %v, want %v", tc.correct, tc.grade, err, tc.want)
+		}
+	}
+
+	// Moving within the passing grades keeps correct; flipping drops a grade that no longer agrees
+	if _, err := svc.UpdateReview(ctx, review.ID, &yes, &four); err != nil {
+		t.Fatalf("UpdateReview(correct, grade 4): %v", err)
+	}
+	change, err = svc.UpdateReview(ctx, review.ID, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateReview(flip): %v", err)
+	}
+	if after := change.Audit.After; after.Correct || after.Grade != nil {
+		t.Errorf("flipping a grade 4 review gave %+v, want wrong and ungraded", after)
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+const (
+	defaultEase = 2.5
+	minEase     = 1.3
+	maxEase     = 3.0
+
+	// targetRetention is the chance of recall the scheduler aims for when a word falls due
+	targetRetention = 0.9
+	// minStabilityDays keeps the forgetting curve of a just-failed word from collapsing
+	minStabilityDays = 1.0
+	// retentionPoints is the number of samples in a retention curve
+	retentionPoints = 30
+)
+
+// nextSchedule computes a word's schedule after a review, SM-2 style.
+// A correct answer grows the interval, a wrong one resets it so the word is due again now.
+// The ease follows the review's grade when the activity gave one.
+func nextSchedule(prev models.WordSchedule, correct bool, grade *int, reviewedAt time.Time) models.WordSchedule {
+	next := prev
+	if next.Ease == 0 {
+		next.Ease = defaultEase
+	}
+
+	if correct {
+		next.Repetitions++
+		switch next.Repetitions {
+		case 1:
+			next.IntervalDays = 1
+		case 2:
+			next.IntervalDays = 3
+		default:
+			next.IntervalDays = math.Round(prev.IntervalDays*next.Ease*10) / 10
+		}
+	} else {
+		next.Repetitions = 0
+		next.IntervalDays = 0
+	}
+	next.Ease = math.Min(maxEase, math.Max(minEase, roundEase(next.Ease+easeChange(correct, grade))))
+
+	next.LastReviewedAt = reviewedAt
+	next.DueAt = reviewedAt.Add(time.Duration(next.IntervalDays * float64(24*time.Hour)))
+	return next
+}
+
+// easeChange is how far a review moves the ease factor. A grade uses SM-2's
+// formula; an ungraded correct answer counts as a perfect 5 and an ungraded
+// wrong one lowers the ease by 0.2.
+func easeChange(correct bool, grade *int) float64 {
+	if grade == nil {
+		if correct {
+			return 0.1
+		}
+		return -0.2
+	}
+	miss := float64(5 - *grade)
+	return 0.1 - miss*(0.08+miss*0.02)
+}
+
+// roundEase keeps ease factors at two decimals
+func roundEase(ease float64) float64 {
+	return math.Round(ease*100) / 100
+}
+
+// retention predicts the chance of recalling a word some days after a review
+// that scheduled it intervalDays ahead. Recall decays exponentially and is
+// expected to reach targetRetention when the word falls due.
+func retention(elapsedDays, intervalDays float64) float64 {
+	stability := math.Max(intervalDays, minStabilityDays)
+	return math.Pow(targetRetention, math.Max(elapsedDays, 0)/stability)
+}
+
+// retentionCurve samples the forgetting curve after a review until well past the due date
+func retentionCurve(intervalDays float64) []models.RetentionPoint {
+	horizon := math.Max(2*math.Max(intervalDays, minStabilityDays), 7)
+	step := horizon / float64(retentionPoints-1)
+
+	curve := make([]models.RetentionPoint, 0, retentionPoints)
+	for i := 0; i < retentionPoints; i++ {
+		day := math.Round(float64(i)*step*100) / 100
+		curve = append(curve, models.RetentionPoint{Day: day, Retention: retention(day, intervalDays)})
+	}
+	return curve
+}
+
+// loadSchedule returns the stored schedule of a word, or a fresh one if it was never reviewed
+func loadSchedule(ctx context.Context, p repository.ProgressRepository, wordID int64) (models.WordSchedule, error) {
+	sched, err := p.Schedule(ctx, wordID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.WordSchedule{WordID: wordID, Ease: defaultEase}, nil
+	}
+	if err != nil {
+		return models.WordSchedule{}, err
+	}
+	return *sched, nil
+}
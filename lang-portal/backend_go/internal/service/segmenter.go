@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"lang-portal/internal/database"
+)
+
+// segment is a single token produced by the segmenter
+type segment struct {
+	Text   string
+	WordID int64 // zero when the token is not in the vocabulary
+}
+
+// segmenter splits Chinese text into tokens using forward maximum matching
+// against the vocabulary. Han characters that match no word become
+// single-character tokens; everything else (spaces, punctuation, latin) is skipped.
+type segmenter struct {
+	dict   map[string]int64
+	maxLen int
+}
+
+// newSegmenter creates a segmenter from a map of word form to word ID
+func newSegmenter(dict map[string]int64) *segmenter {
+	maxLen := 1
+	for form := range dict {
+		if n := utf8.RuneCountInString(form); n > maxLen {
+			maxLen = n
+		}
+	}
+	return &segmenter{dict: dict, maxLen: maxLen}
+}
+
+// loadSegmenter builds a segmenter from every word in the vocabulary
+func loadSegmenter(ctx context.Context, db database.DBTX) (*segmenter, error) {
+	rows, err := db.QueryContext(ctx, "SELECT id, chinese FROM words")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vocabulary: %w", err)
+	}
+	defer rows.Close()
+
+	dict := make(map[string]int64)
+	for rows.Next() {
+		var id int64
+		var chinese string
+		if err := rows.Scan(&id, &chinese); err != nil {
+			return nil, fmt.Errorf("failed to scan vocabulary word: %w", err)
+		}
+		dict[chinese] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating vocabulary: %w", err)
+	}
+
+	return newSegmenter(dict), nil
+}
+
+// Segment returns the tokens found in text, in order
+func (s *segmenter) Segment(text string) []segment {
+	runes := []rune(text)
+	var out []segment
+
+	for i := 0; i < len(runes); {
+		if !unicode.Is(unicode.Han, runes[i]) {
+			i++
+			continue
+		}
+
+		// Find the end of the current run of Han characters
+		end := i
+		for end < len(runes) && unicode.Is(unicode.Han, runes[end]) {
+			end++
+		}
+
+		// Longest dictionary match first, falling back to one character
+		matched := false
+		for n := min(s.maxLen, end-i); n > 0; n-- {
+			form := string(runes[i : i+n])
+			if id, ok := s.dict[form]; ok {
+				out = append(out, segment{Text: form, WordID: id})
+				i += n
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			out = append(out, segment{Text: string(runes[i])})
+			i++
+		}
+	}
+
+	return out
+}
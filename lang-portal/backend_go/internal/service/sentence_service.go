@@ -0,0 +1,492 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+)
+
+// hintDistractors is the number of extra group words mixed into hint candidates
+const hintDistractors = 3
+
+// SentenceService handles the sentence translation exercise
+type SentenceService struct {
+	db  database.DBTX
+	tx  *database.TxRunner
+	now func() time.Time
+}
+
+// NewSentenceService creates a new SentenceService
+func NewSentenceService(db *sql.DB) *SentenceService {
+	return &SentenceService{db: database.Handle(db), tx: database.NewTxRunner(db), now: time.Now}
+}
+
+// CreateSentence stores a reference sentence and links the vocabulary words it uses.
+// Roles optionally name the slot of each linked word in order, e.g. "subject" or "verb".
+func (s *SentenceService) CreateSentence(ctx context.Context, groupID int64, chinese, english string, roles []string) (*models.Sentence, error) {
+	seg, err := loadSegmenter(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	sentence := models.Sentence{GroupID: groupID, Chinese: chinese, English: english}
+
+	err = s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO sentences (group_id, chinese, english)
+			VALUES (?, ?, ?)
+			RETURNING id, created_at
+		`, groupID, chinese, english).Scan(&sentence.ID, database.ScanTime(&sentence.CreatedAt)); err != nil {
+			return fmt.Errorf("failed to create sentence: %w", err)
+		}
+
+		// Position is the order of the word among the sentence's vocabulary words
+		pos := 0
+		for _, token := range seg.Segment(chinese) {
+			if token.WordID == 0 {
+				continue
+			}
+			var role sql.NullString
+			if pos < len(roles) && roles[pos] != "" {
+				role = sql.NullString{String: roles[pos], Valid: true}
+			}
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO sentence_words (sentence_id, word_id, position, role)
+				VALUES (?, ?, ?, ?)
+			`, sentence.ID, token.WordID, pos, role); err != nil {
+				return fmt.Errorf("failed to link sentence word: %w", err)
+			}
+			pos++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sentence, nil
+}
+
+// GetSentence returns a single sentence including its answer
+func (s *SentenceService) GetSentence(ctx context.Context, id int64) (*models.Sentence, error) {
+	var sentence models.Sentence
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, group_id, chinese, english, created_at
+		FROM sentences
+		WHERE id = ?
+	`, id).Scan(&sentence.ID, &sentence.GroupID, &sentence.Chinese, &sentence.English, database.ScanTime(&sentence.CreatedAt))
+	if err == sql.ErrNoRows {
+		return nil, ErrSentenceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sentence: %w", err)
+	}
+	return &sentence, nil
+}
+
+// GetRandomSentence picks a sentence from a group and returns it without the answer
+func (s *SentenceService) GetRandomSentence(ctx context.Context, groupID int64) (*models.SentencePrompt, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id FROM sentences
+		WHERE group_id = ?
+		ORDER BY RANDOM()
+		LIMIT 1
+	`, groupID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, ErrSentenceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sentence: %w", err)
+	}
+
+	sentence, err := s.GetSentence(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	seg, err := loadSegmenter(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SentencePrompt{
+		ID:         sentence.ID,
+		GroupID:    sentence.GroupID,
+		English:    sentence.English,
+		TokenCount: len(seg.Segment(sentence.Chinese)),
+	}, nil
+}
+
+// GradeSentence aligns an answer with the reference sentence token by token and
+// records a review in the study session for every vocabulary word the sentence
+// uses. The sentence must belong to the session's group.
+func (s *SentenceService) GradeSentence(ctx context.Context, sentenceID, sessionID int64, answer string) (*models.SentenceGrade, error) {
+	sentence, err := s.sessionSentence(ctx, sentenceID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	seg, err := loadSegmenter(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+	ref := seg.Segment(sentence.Chinese)
+	ans := seg.Segment(answer)
+
+	grade := &models.SentenceGrade{
+		SentenceID: sentence.ID,
+		Answer:     answer,
+		Reference:  sentence.Chinese,
+		Tokens:     []models.TokenFeedback{},
+		Reviews:    []models.WordReviewItem{},
+	}
+
+	// Track whether every occurrence of a reference token was answered correctly,
+	// and the first vocabulary word written in its place
+	tokenOK := make(map[string]bool)
+	tokenAnswer := make(map[string]segment)
+	correct, extra := 0, 0
+	for _, op := range alignTokens(segmentTexts(ref), segmentTexts(ans)) {
+		var fb models.TokenFeedback
+		switch {
+		case op.Ref >= 0 && op.Ans >= 0:
+			fb = models.TokenFeedback{Expected: ref[op.Ref].Text, Actual: ans[op.Ans].Text, WordID: ref[op.Ref].WordID}
+			if ref[op.Ref].Text == ans[op.Ans].Text {
+				fb.Status = models.TokenCorrect
+				correct++
+			} else {
+				fb.Status = models.TokenWrong
+				if _, ok := tokenAnswer[fb.Expected]; !ok && ans[op.Ans].WordID != 0 {
+					tokenAnswer[fb.Expected] = ans[op.Ans]
+				}
+			}
+		case op.Ref >= 0:
+			fb = models.TokenFeedback{Expected: ref[op.Ref].Text, WordID: ref[op.Ref].WordID, Status: models.TokenMissing}
+		default:
+			fb = models.TokenFeedback{Actual: ans[op.Ans].Text, WordID: ans[op.Ans].WordID, Status: models.TokenExtra}
+			extra++
+		}
+		if fb.Expected != "" {
+			ok, seen := tokenOK[fb.Expected]
+			tokenOK[fb.Expected] = (ok || !seen) && fb.Status == models.TokenCorrect
+		}
+		grade.Tokens = append(grade.Tokens, fb)
+	}
+
+	if total := len(ref) + extra; total > 0 {
+		grade.Score = float64(correct) / float64(total)
+	}
+	grade.Correct = correct == len(ref) && extra == 0
+
+	if err = s.db.QueryRowContext(ctx, `
+		SELECT COALESCE(MAX(level), 0)
+		FROM sentence_hints
+		WHERE sentence_id = ? AND study_session_id = ?
+	`, sentence.ID, sessionID).Scan(&grade.HintLevel); err != nil {
+		return nil, fmt.Errorf("failed to fetch hint level: %w", err)
+	}
+
+	words, err := s.getSentenceWords(ctx, sentence.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		if err := settleStreaks(ctx, tx, s.now()); err != nil {
+			return err
+		}
+		grade.Reviews = grade.Reviews[:0]
+		for _, w := range words {
+			ok, seen := tokenOK[w.Chinese]
+			if !seen {
+				// The vocabulary changed since the sentence was linked
+				ok = strings.Contains(answer, w.Chinese)
+			}
+
+			review := models.WordReviewItem{WordID: w.ID, StudySessionID: sessionID, Correct: ok}
+			if given, found := tokenAnswer[w.Chinese]; found && !ok {
+				review.Answer = given.Text
+				review.AnswerWordID = &given.WordID
+			}
+			if err := insertReview(ctx, tx, &review); err != nil {
+				return err
+			}
+			grade.Reviews = append(grade.Reviews, review)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return grade, nil
+}
+
+// GetHint returns help for a sentence at the given level and logs it against the study session.
+// Level 1 shows the slot pattern, level 2 adds candidate words with distractors from the
+// sentence's group, and level 3 adds a partial answer. The sentence must
+// belong to the session's group.
+func (s *SentenceService) GetHint(ctx context.Context, sentenceID, sessionID int64, level int) (*models.SentenceHint, error) {
+	sentence, err := s.sessionSentence(ctx, sentenceID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	level = max(models.HintLevelPattern, min(level, models.HintLevelPartial))
+	hint := &models.SentenceHint{SentenceID: sentence.ID, Level: level}
+
+	slots, err := s.getSentenceSlots(ctx, sentence.ID)
+	if err != nil {
+		return nil, err
+	}
+	hint.Pattern = strings.Join(slots, " + ")
+
+	if level >= models.HintLevelCandidates {
+		if hint.Candidates, err = s.getHintCandidates(ctx, sentence); err != nil {
+			return nil, err
+		}
+	}
+
+	if level >= models.HintLevelPartial {
+		seg, err := loadSegmenter(ctx, s.db)
+		if err != nil {
+			return nil, err
+		}
+		hint.PartialAnswer = partialAnswer(seg.Segment(sentence.Chinese))
+	}
+
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO sentence_hints (sentence_id, study_session_id, level)
+		VALUES (?, ?, ?)
+	`, sentence.ID, sessionID, level); err != nil {
+		return nil, fmt.Errorf("failed to log hint: %w", err)
+	}
+
+	return hint, nil
+}
+
+// sessionSentence returns a sentence practised in a study session. A sentence
+// from another group than the session's is not found.
+func (s *SentenceService) sessionSentence(ctx context.Context, sentenceID, sessionID int64) (*models.Sentence, error) {
+	sentence, err := s.GetSentence(ctx, sentenceID)
+	if err != nil {
+		return nil, err
+	}
+	groupID, err := sessionGroupID(ctx, s.db, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if sentence.GroupID != groupID {
+		return nil, ErrSentenceNotFound
+	}
+	return sentence, nil
+}
+
+// getSentenceSlots returns the slot name of each linked word in sentence order.
+// The stored role wins, then the part of speech from the word's parts, then "Word".
+func (s *SentenceService) getSentenceSlots(ctx context.Context, sentenceID int64) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT sw.role, w.parts
+		FROM sentence_words sw
+		JOIN words w ON w.id = sw.word_id
+		WHERE sw.sentence_id = ?
+		ORDER BY sw.position
+	`, sentenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sentence slots: %w", err)
+	}
+	defer rows.Close()
+
+	var slots []string
+	for rows.Next() {
+		var role sql.NullString
+		var parts []byte
+		if err := rows.Scan(&role, &parts); err != nil {
+			return nil, fmt.Errorf("failed to scan sentence slot: %w", err)
+		}
+
+		slot := role.String
+		if slot == "" {
+			var p struct {
+				POS string `json:"pos"`
+			}
+			if json.Unmarshal(parts, &p) == nil {
+				slot = p.POS
+			}
+		}
+		if slot == "" {
+			slot = "word"
+		}
+		slots = append(slots, capitalize(slot))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sentence slots: %w", err)
+	}
+	return slots, nil
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// getHintCandidates returns the sentence's words mixed with distractors from its group
+func (s *SentenceService) getHintCandidates(ctx context.Context, sentence *models.Sentence) ([]models.Word, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, chinese, english, parts, created_at FROM (
+			SELECT w.*
+			FROM words w
+			JOIN sentence_words sw ON sw.word_id = w.id
+			WHERE sw.sentence_id = ?
+			GROUP BY w.id
+			UNION ALL
+			SELECT * FROM (
+				SELECT w.*
+				FROM words w
+				JOIN words_groups wg ON wg.word_id = w.id
+				WHERE wg.group_id = ?
+				  AND w.id NOT IN (SELECT word_id FROM sentence_words WHERE sentence_id = ?)
+				GROUP BY w.id
+				ORDER BY RANDOM()
+				LIMIT ?
+			)
+		)
+		ORDER BY RANDOM()
+	`, sentence.ID, sentence.GroupID, sentence.ID, hintDistractors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch hint candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var words []models.Word
+	for rows.Next() {
+		var w models.Word
+		var parts []byte
+		if err := rows.Scan(&w.ID, &w.Chinese, &w.English, &parts, database.ScanTime(&w.CreatedAt)); err != nil {
+			return nil, fmt.Errorf("failed to scan hint candidate: %w", err)
+		}
+		if len(parts) > 0 {
+			w.Parts = parts
+		}
+		words = append(words, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating hint candidates: %w", err)
+	}
+	return words, nil
+}
+
+// partialAnswer reveals the first half of the tokens and masks the rest
+func partialAnswer(tokens []segment) string {
+	var b strings.Builder
+	reveal := (len(tokens) + 1) / 2
+	for i, t := range tokens {
+		if i < reveal {
+			b.WriteString(t.Text)
+		} else {
+			b.WriteString(strings.Repeat("＿", utf8.RuneCountInString(t.Text)))
+		}
+	}
+	return b.String()
+}
+
+// getSentenceWords returns the distinct vocabulary words linked to a sentence
+func (s *SentenceService) getSentenceWords(ctx context.Context, sentenceID int64) ([]models.Word, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT w.id, w.chinese, w.english
+		FROM sentence_words sw
+		JOIN words w ON w.id = sw.word_id
+		WHERE sw.sentence_id = ?
+		GROUP BY w.id
+		ORDER BY MIN(sw.position)
+	`, sentenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sentence words: %w", err)
+	}
+	defer rows.Close()
+
+	var words []models.Word
+	for rows.Next() {
+		var w models.Word
+		if err := rows.Scan(&w.ID, &w.Chinese, &w.English); err != nil {
+			return nil, fmt.Errorf("failed to scan sentence word: %w", err)
+		}
+		words = append(words, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sentence words: %w", err)
+	}
+	return words, nil
+}
+
+// alignOp pairs a reference token with an answer token; -1 marks a gap
+type alignOp struct {
+	Ref int
+	Ans int
+}
+
+// alignTokens aligns two token sequences with minimum edit distance
+func alignTokens(ref, ans []string) []alignOp {
+	n, m := len(ref), len(ans)
+	d := make([][]int, n+1)
+	for i := range d {
+		d[i] = make([]int, m+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= m; j++ {
+		d[0][j] = j
+	}
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			cost := 1
+			if ref[i-1] == ans[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j-1]+cost, d[i-1][j]+1, d[i][j-1]+1)
+		}
+	}
+
+	var ops []alignOp
+	for i, j := n, m; i > 0 || j > 0; {
+		switch {
+		case i > 0 && j > 0 && ref[i-1] == ans[j-1] && d[i][j] == d[i-1][j-1]:
+			ops = append(ops, alignOp{Ref: i - 1, Ans: j - 1})
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && d[i][j] == d[i-1][j-1]+1:
+			ops = append(ops, alignOp{Ref: i - 1, Ans: j - 1})
+			i, j = i-1, j-1
+		case i > 0 && d[i][j] == d[i-1][j]+1:
+			ops = append(ops, alignOp{Ref: i - 1, Ans: -1})
+			i--
+		default:
+			ops = append(ops, alignOp{Ref: -1, Ans: j - 1})
+			j--
+		}
+	}
+
+	for l, r := 0, len(ops)-1; l < r; l, r = l+1, r-1 {
+		ops[l], ops[r] = ops[r], ops[l]
+	}
+	return ops
+}
+
+// segmentTexts returns the text of each segment
+func segmentTexts(segs []segment) []string {
+	out := make([]string, len(segs))
+	for i, s := range segs {
+		out[i] = s.Text
+	}
+	return out
+}
@@ -1,21 +1,49 @@
 package service
 
 import (
-	"database/sql"
+	"lang-portal/internal/database"
 )
 
 // Services holds all service instances
 type Services struct {
-	Word  *WordService
-	Group *GroupService
-	Study *StudyService
+	Word      *WordService
+	Group     *GroupService
+	Study     *StudyService
+	Analysis  *AnalysisService
+	Sentence  *SentenceService
+	Example   *ExampleService
+	Quiz      *QuizService
+	Queue     *QueueService
+	Settings  *SettingsService
+	Analytics *AnalyticsService
+	Goal      *GoalService
+	Integrity *IntegrityService
+	// Tx runs work spanning several services in one transaction; services
+	// called inside it join the transaction instead of opening their own
+	Tx *database.TxRunner
 }
 
-// NewServices creates all services
-func NewServices(db *sql.DB) *Services {
-	return &Services{
-		Word:  NewWordService(db),
-		Group: NewGroupService(db),
-		Study: NewStudyService(db),
+// NewServices creates all services. Writes go through the single writer
+// connection; read-only queries use the reader pool so they don't queue behind writes.
+func NewServices(db *database.DB) *Services {
+	s := &Services{
+		Word:      NewWordService(db.Writer),
+		Group:     NewGroupService(db.Writer),
+		Study:     NewStudyService(db.Writer),
+		Analysis:  NewAnalysisService(db.Reader),
+		Sentence:  NewSentenceService(db.Writer),
+		Example:   NewExampleService(db.Writer),
+		Quiz:      NewQuizService(db.Writer),
+		Queue:     NewQueueService(db.Writer),
+		Settings:  NewSettingsService(db.Writer),
+		Analytics: NewAnalyticsService(db.Reader),
+		Goal:      NewGoalService(db.Writer),
+		Integrity: NewIntegrityService(db.Writer),
+		Tx:        database.NewTxRunner(db.Writer),
 	}
+	// Reads inside a transaction still go to the writer so they see its changes
+	s.Word.read = database.Handle(db.Reader)
+	s.Group.read = database.Handle(db.Reader)
+	s.Study.read = database.Handle(db.Reader)
+	return s
 }
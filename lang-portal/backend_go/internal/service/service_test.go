@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/database/migrations"
+)
+
+// importGroup adds words, a group holding them and an example per word in
+// one transaction spanning the group and example services
+func importGroup(ctx context.Context, s *Services, name string, words [][2]string) error {
+	return s.Tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		var ids []int64
+		for _, w := range words {
+			var id int64
+			if err := tx.QueryRowContext(ctx, "INSERT INTO words (chinese, english, parts) VALUES (?, ?, '{}') RETURNING id", w[0], w[1]).Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		if _, err := s.Group.CreateGroup(ctx, name, ids); err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if _, err := s.Example.CreateExample(ctx, id, "我说"+words[i][0], "I say "+words[i][1], ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func TestServicesShareTransaction(t *testing.T) {
+	db, err := database.Open(database.Config{DBPath: ":memory:"})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := migrations.NewManager(db.Writer, "../database/migrations").Migrate(); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	s := NewServices(db)
+	ctx := t.Context()
+
+	count := func(table string) int {
+		t.Helper()
+		var n int
+		if err := db.Reader.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatalf("failed to count %s: %v", table, err)
+		}
+		return n
+	}
+
+	if err := importGroup(ctx, s, "Greetings", [][2]string{{"你好", "hello"}, {"再见", "goodbye"}}); err != nil {
+		t.Fatalf("importGroup() error = %v", err)
+	}
+
+	// A later step fails after a nested import succeeded, so the import is undone too
+	err = s.Tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		if err := importGroup(ctx, s, "Colours", [][2]string{{"红", "red"}}); err != nil {
+			return err
+		}
+		_, err := s.Example.CreateExample(ctx, 1, "没有这个词", "missing", "")
+		return err
+	})
+	if !errors.Is(err, ErrExampleMissingWord) {
+		t.Fatalf("InTx() error = %v, want %v", err, ErrExampleMissingWord)
+	}
+
+	for table, want := range map[string]int{"words": 2, "groups": 1, "words_groups": 2, "word_examples": 2} {
+		if got := count(table); got != want {
+			t.Errorf("%s: %d rows, want %d", table, got, want)
+		}
+	}
+	var name string
+	if err := db.Reader.QueryRow("SELECT name FROM groups").Scan(&name); err != nil || name != "Greetings" {
+		t.Errorf("group = %q, want Greetings", name)
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestItemsStayInSessionGroup checks that sentences and cloze examples are
+// only practised in sessions of their group, and cloze skips suspended words
+func TestItemsStayInSessionGroup(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 2, 0)
+	if _, err := db.Exec(`
+		INSERT INTO groups (id, name) VALUES (2, 'Other');
+		INSERT INTO study_sessions (id, group_id, study_activity_id) VALUES (2, 2, 1);
+	`); err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	sentences := NewSentenceService(db)
+	sentence, err := sentences.CreateSentence(ctx, 1, "w1w2", "word 1 word 2", nil)
+	if err != nil {
+		t.Fatalf("CreateSentence: %v", err)
+	}
+	if _, err := sentences.GradeSentence(ctx, sentence.ID, 2, "w1w2"); !errors.Is(err, ErrSentenceNotFound) {
+		t.Errorf("GradeSentence(other group) error = %v, want %v", err, ErrSentenceNotFound)
+	}
+	if _, err := sentences.GetHint(ctx, sentence.ID, 2, 1); !errors.Is(err, ErrSentenceNotFound) {
+		t.Errorf("GetHint(other group) error = %v, want %v", err, ErrSentenceNotFound)
+	}
+	if grade, err := sentences.GradeSentence(ctx, sentence.ID, 1, "w1w2"); err != nil || !grade.Correct {
+		t.Errorf("GradeSentence = %+v, %v; want a correct answer", grade, err)
+	}
+
+	examples := NewExampleService(db)
+	example, err := examples.CreateExample(ctx, 1, "我说w1", "I say word 1", "")
+	if err != nil {
+		t.Fatalf("CreateExample: %v", err)
+	}
+	if _, err := examples.GetCloze(ctx, 2); !errors.Is(err, ErrExampleNotFound) {
+		t.Errorf("GetCloze(other group) error = %v, want %v", err, ErrExampleNotFound)
+	}
+	if _, err := examples.AnswerCloze(ctx, 2, example.ID, "w1"); !errors.Is(err, ErrExampleNotFound) {
+		t.Errorf("AnswerCloze(other group) error = %v, want %v", err, ErrExampleNotFound)
+	}
+	if item, err := examples.GetCloze(ctx, 1); err != nil || item.ExampleID != example.ID {
+		t.Fatalf("GetCloze = %+v, %v; want example %d", item, err, example.ID)
+	}
+	if result, err := examples.AnswerCloze(ctx, 1, example.ID, "w1"); err != nil || !result.Correct {
+		t.Errorf("AnswerCloze = %+v, %v; want a correct answer", result, err)
+	}
+
+	if _, err := NewWordService(db).SuspendWord(ctx, 1); err != nil {
+		t.Fatalf("SuspendWord: %v", err)
+	}
+	if _, err := examples.GetCloze(ctx, 1); !errors.Is(err, ErrExampleNotFound) {
+		t.Errorf("GetCloze(suspended word) error = %v, want %v", err, ErrExampleNotFound)
+	}
+	if _, err := examples.AnswerCloze(ctx, 1, example.ID, "w1"); !errors.Is(err, ErrExampleNotFound) {
+		t.Errorf("AnswerCloze(suspended word) error = %v, want %v", err, ErrExampleNotFound)
+	}
+}
+
+func TestHintPatternCapitalizesRoles(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 2, 0)
+	if _, err := db.Exec("UPDATE words SET chinese = CASE id WHEN 1 THEN '猫' ELSE '吃' END"); err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	sentences := NewSentenceService(db)
+	sentence, err := sentences.CreateSentence(ctx, 1, "猫吃", "the cat eats", []string{"évènement", "動詞"})
+	if err != nil {
+		t.Fatalf("CreateSentence: %v", err)
+	}
+	hint, err := sentences.GetHint(ctx, sentence.ID, 1, 1)
+	if err != nil {
+		t.Fatalf("GetHint: %v", err)
+	}
+	if hint.Pattern != "Évènement + 動詞" {
+		t.Errorf("Pattern = %q, want %q", hint.Pattern, "Évènement + 動詞")
+	}
+}
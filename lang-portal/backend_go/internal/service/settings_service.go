@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+	// Embedded so timezone settings work on hosts without a zoneinfo database
+	_ "time/tzdata"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+)
+
+// SettingsService handles the learner's settings
+type SettingsService struct {
+	db  database.DBTX
+	tx  *database.TxRunner
+	now func() time.Time
+}
+
+// NewSettingsService creates a new SettingsService
+func NewSettingsService(db *sql.DB) *SettingsService {
+	return &SettingsService{db: database.Handle(db), tx: database.NewTxRunner(db), now: time.Now}
+}
+
+// GetSettings returns the current settings
+func (s *SettingsService) GetSettings(ctx context.Context) (*models.Settings, error) {
+	return loadSettings(ctx, s.db)
+}
+
+// UpdateSettings applies a partial JSON update to the settings. Changing a
+// mastery threshold recomputes every word's mastery from its history.
+// Finished days are settled first, so a new timezone doesn't move past
+// activity into other days.
+func (s *SettingsService) UpdateSettings(ctx context.Context, patch []byte) (*models.Settings, error) {
+	var updated models.Settings
+	err := s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		if err := settleStreaks(ctx, tx, s.now()); err != nil {
+			return err
+		}
+		current, err := loadSettings(ctx, tx)
+		if err != nil {
+			return err
+		}
+		updated = *current
+		if err = json.Unmarshal(patch, &updated); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSettings, err)
+		}
+		if err = validateSettings(updated); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(updated)
+		if err != nil {
+			return fmt.Errorf("failed to encode settings: %w", err)
+		}
+		if _, err = tx.ExecContext(ctx, `
+			INSERT INTO settings (id, data, updated_at)
+			VALUES (1, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (id) DO UPDATE SET
+				data = excluded.data,
+				updated_at = excluded.updated_at
+		`, string(data)); err != nil {
+			return fmt.Errorf("failed to save settings: %w", err)
+		}
+
+		if updated.MasteryReviewingStreak != current.MasteryReviewingStreak ||
+			updated.MasteryMasteredStreak != current.MasteryMasteredStreak ||
+			updated.MasteryMasteredAccuracy != current.MasteryMasteredAccuracy {
+			return rebuildMastery(ctx, tx, updated)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// RebuildMastery recomputes every word's mastery state from its review history
+func (s *SettingsService) RebuildMastery(ctx context.Context) (*models.MasteryBreakdown, error) {
+	var breakdown models.MasteryBreakdown
+	err := s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		cfg, err := loadSettings(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if err = rebuildMastery(ctx, tx, *cfg); err != nil {
+			return err
+		}
+		breakdown, err = masteryBreakdown(ctx, tx, 0)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &breakdown, nil
+}
+
+// loadSettings returns the stored settings layered over the defaults
+func loadSettings(ctx context.Context, q database.DBTX) (*models.Settings, error) {
+	settings := models.DefaultSettings()
+
+	var data string
+	err := q.QueryRowContext(ctx, "SELECT data FROM settings WHERE id = 1").Scan(&data)
+	if err == sql.ErrNoRows {
+		return &settings, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch settings: %w", err)
+	}
+	if err := json.Unmarshal([]byte(data), &settings); err != nil {
+		return nil, fmt.Errorf("failed to decode settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// validateSettings checks that thresholds are usable
+func validateSettings(s models.Settings) error {
+	switch {
+	case s.MasteryReviewingStreak < 1:
+		return fmt.Errorf("%w: mastery_reviewing_streak must be at least 1", ErrInvalidSettings)
+	case s.MasteryMasteredStreak < s.MasteryReviewingStreak:
+		return fmt.Errorf("%w: mastery_mastered_streak must not be below mastery_reviewing_streak", ErrInvalidSettings)
+	case s.MasteryMasteredAccuracy < 0 || s.MasteryMasteredAccuracy > 1:
+		return fmt.Errorf("%w: mastery_mastered_accuracy must be between 0 and 1", ErrInvalidSettings)
+	case s.LeechThreshold < 0:
+		return fmt.Errorf("%w: leech_threshold must not be negative", ErrInvalidSettings)
+	case s.ReviewEditWindowMinutes < 0:
+		return fmt.Errorf("%w: review_edit_window_minutes must not be negative", ErrInvalidSettings)
+	case s.Timezone == "":
+		return fmt.Errorf("%w: timezone is required", ErrInvalidSettings)
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidSettings, s.Timezone)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// wordTraits holds the features used to compare words for distractor selection
+type wordTraits struct {
+	POS    string
+	Pinyin string // lower case, tone marks and spaces removed
+	Chars  map[rune]bool
+}
+
+// toneless maps pinyin vowels with tone marks to their plain form
+var toneless = strings.NewReplacer(
+	"ā", "a", "á", "a", "ǎ", "a", "à", "a",
+	"ē", "e", "é", "e", "ě", "e", "è", "e",
+	"ī", "i", "í", "i", "ǐ", "i", "ì", "i",
+	"ō", "o", "ó", "o", "ǒ", "o", "ò", "o",
+	"ū", "u", "ú", "u", "ǔ", "u", "ù", "u",
+	"ǖ", "v", "ǘ", "v", "ǚ", "v", "ǜ", "v", "ü", "v",
+	" ", "",
+)
+
+// newWordTraits extracts comparison traits from a word's Chinese form and parts JSON
+func newWordTraits(chinese string, parts []byte) wordTraits {
+	var p struct {
+		POS    string `json:"pos"`
+		Pinyin string `json:"pinyin"`
+	}
+	_ = json.Unmarshal(parts, &p)
+
+	t := wordTraits{
+		POS:    strings.ToLower(p.POS),
+		Pinyin: toneless.Replace(strings.ToLower(p.Pinyin)),
+		Chars:  make(map[rune]bool),
+	}
+	for _, r := range chinese {
+		t.Chars[r] = true
+	}
+	return t
+}
+
+// similarity scores how easily two words could be confused; higher is closer
+func similarity(a, b wordTraits) int {
+	score := 0
+	if a.POS != "" && a.POS == b.POS {
+		score += 3
+	}
+	for r := range a.Chars {
+		if b.Chars[r] {
+			score += 2
+		}
+	}
+	if a.Pinyin != "" && b.Pinyin != "" {
+		if d := levenshtein(a.Pinyin, b.Pinyin); d < 3 {
+			score += 3 - d
+		}
+	}
+	return score
+}
+
+// levenshtein returns the edit distance between two strings, counted in runes
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j-1]+cost, prev[j]+1, cur[j-1]+1)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
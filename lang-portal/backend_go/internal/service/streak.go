@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+)
+
+// dateLayout formats learner-local calendar days
+const dateLayout = "2006-01-02"
+
+// activityBucket is the granularity, in seconds, at which activity timestamps
+// are grouped before being mapped to local days. Every UTC offset is a
+// multiple of 15 minutes, so a bucket never straddles a local midnight.
+const activityBucket = 15 * 60
+
+// calculateStreak computes streaks from the days with activity and the days
+// covered by a streak freeze. A frozen day keeps a run going without adding to
+// it. Today only breaks the current streak once it is over, so a run ending
+// yesterday is still current.
+func calculateStreak(active, frozen []string, today string) models.StudyStreak {
+	var streak models.StudyStreak
+	if len(active) == 0 {
+		return streak
+	}
+
+	activeSet := make(map[string]bool, len(active))
+	for _, d := range active {
+		activeSet[d] = true
+	}
+	frozenSet := make(map[string]bool, len(frozen))
+	for _, d := range frozen {
+		frozenSet[d] = true
+	}
+
+	days := make([]string, 0, len(activeSet))
+	for d := range activeSet {
+		days = append(days, d)
+	}
+	sort.Strings(days)
+	streak.LastStudyDate = days[len(days)-1]
+	streak.StudiedToday = activeSet[today]
+
+	// Longest run: walk every calendar day between the first and last activity
+	first, _ := time.Parse(dateLayout, days[0])
+	last, _ := time.Parse(dateLayout, days[len(days)-1])
+	run := 0
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		key := d.Format(dateLayout)
+		switch {
+		case activeSet[key]:
+			run++
+			streak.Longest = max(streak.Longest, run)
+		case !frozenSet[key]:
+			run = 0
+		}
+	}
+
+	// Current run: walk back from today, skipping today if not studied yet
+	d, err := time.Parse(dateLayout, today)
+	if err != nil {
+		return streak
+	}
+	if !streak.StudiedToday {
+		d = d.AddDate(0, 0, -1)
+	}
+	for ; ; d = d.AddDate(0, 0, -1) {
+		key := d.Format(dateLayout)
+		if activeSet[key] {
+			streak.Current++
+		} else if frozenSet[key] && key > days[0] {
+			streak.FreezeDays++
+		} else {
+			break
+		}
+	}
+	if streak.Current == 0 {
+		streak.FreezeDays = 0
+	}
+
+	return streak
+}
+
+// learnerLocation returns the configured timezone of the learner
+func learnerLocation(cfg *models.Settings) (*time.Location, error) {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load timezone %q: %w", cfg.Timezone, err)
+	}
+	return loc, nil
+}
+
+// activityDays returns every learner-local day with a study session or review
+func activityDays(ctx context.Context, q database.DBTX, loc *time.Location) ([]string, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT DISTINCT CAST(strftime('%s', created_at) AS INTEGER) / ?
+		FROM (
+			SELECT created_at FROM study_sessions
+			UNION ALL
+			SELECT created_at FROM word_review_items
+		)
+	`, activityBucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch study activity: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[string]bool)
+	var days []string
+	for rows.Next() {
+		var bucket int64
+		if err := rows.Scan(&bucket); err != nil {
+			return nil, fmt.Errorf("failed to scan study activity: %w", err)
+		}
+		day := time.Unix(bucket*activityBucket, 0).In(loc).Format(dateLayout)
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating study activity: %w", err)
+	}
+	return days, nil
+}
+
+// frozenDays returns the learner-local days covered by a streak freeze
+func frozenDays(ctx context.Context, q database.DBTX) ([]string, error) {
+	rows, err := q.QueryContext(ctx, "SELECT day FROM streak_freezes")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch streak freezes: %w", err)
+	}
+	defer rows.Close()
+
+	var days []string
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("failed to scan streak freeze: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating streak freezes: %w", err)
+	}
+	return days, nil
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"lang-portal/internal/database/migrations"
+	"lang-portal/internal/models"
+)
+
+func newTestDB(t testing.TB) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:"+t.Name()+"?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if err := migrations.NewManager(db, "../database/migrations").Migrate(); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db
+}
+
+func TestCalculateStreak(t *testing.T) {
+	tests := []struct {
+		name    string
+		active  []string
+		frozen  []string
+		today   string
+		current int
+		longest int
+		freezes int
+	}{
+		{
+			name:  "no activity",
+			today: "2025-03-10",
+		},
+		{
+			name:    "studied today",
+			active:  []string{"2025-03-08", "2025-03-09", "2025-03-10"},
+			today:   "2025-03-10",
+			current: 3,
+			longest: 3,
+		},
+		{
+			name:    "today not studied yet keeps the streak",
+			active:  []string{"2025-03-08", "2025-03-09"},
+			today:   "2025-03-10",
+			current: 2,
+			longest: 2,
+		},
+		{
+			name:    "missed yesterday breaks the streak",
+			active:  []string{"2025-03-07", "2025-03-08"},
+			today:   "2025-03-10",
+			current: 0,
+			longest: 2,
+		},
+		{
+			name:    "longest run in the past",
+			active:  []string{"2025-03-01", "2025-03-02", "2025-03-03", "2025-03-04", "2025-03-09", "2025-03-10"},
+			today:   "2025-03-10",
+			current: 2,
+			longest: 4,
+		},
+		{
+			name:    "freeze bridges a missed day without counting",
+			active:  []string{"2025-03-07", "2025-03-08", "2025-03-10"},
+			frozen:  []string{"2025-03-09"},
+			today:   "2025-03-10",
+			current: 3,
+			longest: 3,
+			freezes: 1,
+		},
+		{
+			name:    "freeze on yesterday keeps an unfinished today alive",
+			active:  []string{"2025-03-08"},
+			frozen:  []string{"2025-03-09"},
+			today:   "2025-03-10",
+			current: 1,
+			longest: 1,
+			freezes: 1,
+		},
+		{
+			name:    "freezes outside the current run are ignored",
+			active:  []string{"2025-03-01", "2025-03-10"},
+			frozen:  []string{"2025-03-02"},
+			today:   "2025-03-10",
+			current: 1,
+			longest: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateStreak(tt.active, tt.frozen, tt.today)
+			if got.Current != tt.current {
+				t.Errorf("current = %d; want %d", got.Current, tt.current)
+			}
+			if got.Longest != tt.longest {
+				t.Errorf("longest = %d; want %d", got.Longest, tt.longest)
+			}
+			if got.FreezeDays != tt.freezes {
+				t.Errorf("freeze days = %d; want %d", got.FreezeDays, tt.freezes)
+			}
+		})
+	}
+}
+
+func TestGetStreakUsesLearnerTimezone(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+
+	if _, err := NewSettingsService(db).UpdateSettings(ctx, []byte(`{"timezone":"America/Los_Angeles"}`)); err != nil {
+		t.Fatalf("failed to set timezone: %v", err)
+	}
+
+	// 9pm local on two evenings in a row; both are the next day in UTC
+	for _, ts := range []string{"2025-01-14 05:00:00", "2025-01-15 05:00:00"} {
+		if _, err := db.Exec("INSERT INTO study_sessions (group_id, study_activity_id, created_at) VALUES (1, 1, ?)", ts); err != nil {
+			t.Fatalf("failed to insert session: %v", err)
+		}
+	}
+
+	s := NewStudyService(db)
+	tests := []struct {
+		name    string
+		now     time.Time
+		current int
+		today   bool
+	}{
+		{"same evening", time.Date(2025, 1, 15, 6, 0, 0, 0, time.UTC), 2, true},
+		{"next morning", time.Date(2025, 1, 15, 17, 0, 0, 0, time.UTC), 2, false},
+		{"day after next", time.Date(2025, 1, 17, 17, 0, 0, 0, time.UTC), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.now = func() time.Time { return tt.now }
+
+			got, err := s.GetStreak(ctx)
+			if err != nil {
+				t.Fatalf("GetStreak() error = %v", err)
+			}
+			if got.Current != tt.current {
+				t.Errorf("current = %d; want %d", got.Current, tt.current)
+			}
+			if got.StudiedToday != tt.today {
+				t.Errorf("studied today = %v; want %v", got.StudiedToday, tt.today)
+			}
+			if got.LastStudyDate != "2025-01-14" {
+				t.Errorf("last study date = %q; want %q", got.LastStudyDate, "2025-01-14")
+			}
+		})
+	}
+}
+
+func TestStreakFreezesEarnedAndSpent(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+
+	if _, err := NewGoalService(db).UpdateGoals(ctx, models.DailyGoals{Reviews: 1}); err != nil {
+		t.Fatalf("failed to set goals: %v", err)
+	}
+
+	// A perfect week (Monday 6th to Sunday 12th), a missed Monday, then Tuesday
+	studied := []string{"06", "07", "08", "09", "10", "11", "12", "14"}
+	for i, day := range studied {
+		ts := "2025-01-" + day + " 10:00:00"
+		if _, err := db.Exec("INSERT INTO study_sessions (id, group_id, study_activity_id, created_at) VALUES (?, 1, 1, ?)", i+1, ts); err != nil {
+			t.Fatalf("failed to insert session: %v", err)
+		}
+		if _, err := db.Exec("INSERT INTO word_review_items (word_id, study_session_id, correct, created_at) VALUES (1, ?, 1, ?)", i+1, ts); err != nil {
+			t.Fatalf("failed to insert review: %v", err)
+		}
+	}
+
+	s := NewStudyService(db)
+	s.now = func() time.Time { return time.Date(2025, 1, 14, 12, 0, 0, 0, time.UTC) }
+
+	streak, err := s.GetStreak(ctx)
+	if err != nil {
+		t.Fatalf("GetStreak() error = %v", err)
+	}
+	if streak.Current != 8 || streak.FreezeDays != 1 {
+		t.Errorf("streak = %d with %d freeze days; want 8 with 1", streak.Current, streak.FreezeDays)
+	}
+	var states, spent int
+	if err := db.QueryRow("SELECT (SELECT COUNT(*) FROM streak_state), (SELECT COUNT(*) FROM streak_freezes)").Scan(&states, &spent); err != nil {
+		t.Fatalf("failed to count streak rows: %v", err)
+	}
+	if states != 0 || spent != 0 {
+		t.Errorf("GetStreak stored %d streak states and %d freezes; want reads to store nothing", states, spent)
+	}
+
+	progress, err := todayProgress(ctx, db, s.now())
+	if err != nil {
+		t.Fatalf("todayProgress() error = %v", err)
+	}
+	if progress.StreakFreezes != 0 {
+		t.Errorf("streak freezes = %d; want 0 after spending", progress.StreakFreezes)
+	}
+	if !progress.Completed || progress.Reviews != 1 {
+		t.Errorf("progress = %+v; want one review and the goal completed", progress)
+	}
+
+	// Two more missed days with no freezes left break the streak
+	s.now = func() time.Time { return time.Date(2025, 1, 17, 12, 0, 0, 0, time.UTC) }
+	if streak, err = s.GetStreak(ctx); err != nil {
+		t.Fatalf("GetStreak() error = %v", err)
+	}
+	if streak.Current != 0 || streak.Longest != 8 {
+		t.Errorf("streak = %d (longest %d); want 0 (longest 8)", streak.Current, streak.Longest)
+	}
+
+	// Starting a session settles the finished days for good
+	if _, err := db.Exec("INSERT INTO groups (id, name) VALUES (1, 'Streak')"); err != nil {
+		t.Fatalf("failed to insert group: %v", err)
+	}
+	if _, err := s.StartStudySession(ctx, 1, 1); err != nil {
+		t.Fatalf("StartStudySession() error = %v", err)
+	}
+	var freezeDay, settled string
+	if err := db.QueryRow("SELECT f.day, s.settled_through FROM streak_freezes f, streak_state s").Scan(&freezeDay, &settled); err != nil {
+		t.Fatalf("failed to read settled streak: %v", err)
+	}
+	if freezeDay != "2025-01-13" || settled != "2025-01-16" {
+		t.Errorf("settled freeze on %s through %s; want 2025-01-13 through 2025-01-16", freezeDay, settled)
+	}
+	if again, err := s.GetStreak(ctx); err != nil || again.Longest != 8 {
+		t.Errorf("GetStreak() after settling = %+v, %v; want longest 8", again, err)
+	}
+}
+
+func TestTimezoneChangeSettlesFirst(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	if _, err := db.Exec("INSERT INTO study_sessions (id, group_id, study_activity_id, created_at) VALUES (1, 1, 1, '2025-01-10 10:00:00')"); err != nil {
+		t.Fatalf("failed to insert session: %v", err)
+	}
+
+	s := NewSettingsService(db)
+	s.now = func() time.Time { return time.Date(2025, 1, 14, 12, 0, 0, 0, time.UTC) }
+	if _, err := s.UpdateSettings(ctx, []byte(`{"timezone":"Pacific/Kiritimati"}`)); err != nil {
+		t.Fatalf("UpdateSettings() error = %v", err)
+	}
+
+	// Settled through yesterday in UTC, not in the new zone 14 hours ahead
+	var settled string
+	if err := db.QueryRow("SELECT settled_through FROM streak_state").Scan(&settled); err != nil {
+		t.Fatalf("failed to read streak state: %v", err)
+	}
+	if settled != "2025-01-13" {
+		t.Errorf("settled through %s; want 2025-01-13", settled)
+	}
+}
@@ -1,110 +1,543 @@
 package service
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"lang-portal/internal/database"
 	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
 )
 
 // StudyService handles study session related business logic
 type StudyService struct {
-	db *sql.DB
+	db database.DBTX
+	tx *database.TxRunner
+	// read serves read-only queries; NewServices points it at the reader pool
+	read database.DBTX
+	now  func() time.Time
 }
 
 // NewStudyService creates a new StudyService
 func NewStudyService(db *sql.DB) *StudyService {
-	return &StudyService{db: db}
+	return &StudyService{db: database.Handle(db), tx: database.NewTxRunner(db), read: database.Handle(db), now: time.Now}
 }
 
-// StartStudySession starts a new study session
-func (s *StudyService) StartStudySession(groupID, activityID int64) (*models.StudySession, error) {
+// StartStudySession starts a new study session of a group in a study
+// activity. Finished days are settled first, spending or earning streak freezes.
+func (s *StudyService) StartStudySession(ctx context.Context, groupID, activityID int64) (*models.StudySession, error) {
 	var session models.StudySession
 
-	tx, err := s.db.Begin()
+	err := s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		var groupExists, activityExists bool
+		if err := tx.QueryRowContext(ctx, `
+			SELECT
+				EXISTS (SELECT 1 FROM groups WHERE id = ?),
+				EXISTS (SELECT 1 FROM study_activities WHERE id = ?)
+		`, groupID, activityID).Scan(&groupExists, &activityExists); err != nil {
+			return fmt.Errorf("failed to check study session references: %w", err)
+		}
+		if !groupExists {
+			return ErrGroupNotFound
+		}
+		if !activityExists {
+			return ErrActivityNotFound
+		}
+		if err := settleStreaks(ctx, tx, s.now()); err != nil {
+			return err
+		}
+
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO study_sessions (group_id, study_activity_id)
+			VALUES (?, ?)
+			RETURNING id, created_at
+		`, groupID, activityID).Scan(&session.ID, database.ScanTime(&session.CreatedAt)); err != nil {
+			return fmt.Errorf("failed to create study session: %w", err)
+		}
+
+		session.GroupID = groupID
+		session.StudyActivityID = activityID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// RecordWordReview records a word review in a study session. The answer and
+// response time are optional; when a wrong answer names another word the
+// confusion is recorded.
+func (s *StudyService) RecordWordReview(ctx context.Context, review models.WordReviewItem) (*models.WordReviewItem, error) {
+	err := s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		var sessionExists, wordExists bool
+		if err := tx.QueryRowContext(ctx, `
+			SELECT
+				EXISTS (SELECT 1 FROM study_sessions WHERE id = ?),
+				EXISTS (SELECT 1 FROM words WHERE id = ?)
+		`, review.StudySessionID, review.WordID).Scan(&sessionExists, &wordExists); err != nil {
+			return fmt.Errorf("failed to check review references: %w", err)
+		}
+		if !sessionExists {
+			return ErrSessionNotFound
+		}
+		if !wordExists {
+			return ErrWordNotFound
+		}
+		if err := settleStreaks(ctx, tx, s.now()); err != nil {
+			return err
+		}
+		return insertReview(ctx, tx, &review)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, err
 	}
-	defer func() {
+
+	return &review, nil
+}
+
+// maxBatchReviews caps how many reviews one batch may record
+const maxBatchReviews = 200
+
+// answerClockSkew is how far past the server's clock an answered_at may be
+const answerClockSkew = time.Minute
+
+// RecordReviews records a batch of reviews in a study session in one
+// transaction. Every word must belong to the session's group; if any
+// submission is invalid nothing is recorded and the error lists each problem.
+// Reviews are recorded in the order they were answered, so stats and
+// schedules advance as if they had been sent one by one.
+func (s *StudyService) RecordReviews(ctx context.Context, sessionID int64, submissions []models.ReviewSubmission) (*models.ReviewBatchResult, error) {
+	switch {
+	case len(submissions) == 0:
+		return nil, fmt.Errorf("%w: no reviews given", ErrInvalidReviews)
+	case len(submissions) > maxBatchReviews:
+		return nil, fmt.Errorf("%w: at most %d reviews per batch", ErrInvalidReviews, maxBatchReviews)
+	}
+
+	result := models.ReviewBatchResult{Items: make([]models.ReviewBatchItem, len(submissions))}
+	err := s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		var groupID int64
+		var startedAt time.Time
+		err := tx.QueryRowContext(ctx,
+			"SELECT group_id, created_at FROM study_sessions WHERE id = ?", sessionID,
+		).Scan(&groupID, database.ScanTime(&startedAt))
+		if err == sql.ErrNoRows {
+			return ErrSessionNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch study session: %w", err)
+		}
+		lastReviews, err := groupLastReviews(ctx, tx, groupID)
+		if err != nil {
+			return err
+		}
+
+		now := s.now().UTC()
+		if err := settleStreaks(ctx, tx, now); err != nil {
+			return err
+		}
+		reviews := make([]models.WordReviewItem, len(submissions))
+		var problems []string
+		for i, sub := range submissions {
+			review, err := submissionReview(sub, lastReviews, startedAt, now)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("item %d: %v", i, err))
+				continue
+			}
+			review.StudySessionID = sessionID
+			reviews[i] = review
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("%w: %s", ErrInvalidReviews, strings.Join(problems, "; "))
+		}
+
+		order := make([]int, len(reviews))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return reviews[order[a]].CreatedAt.Before(reviews[order[b]].CreatedAt)
+		})
+		cfg, err := loadSettings(ctx, tx)
 		if err != nil {
-			_ = tx.Rollback()
+			return err
+		}
+		repos := repository.NewSQLite(tx)
+		for _, i := range order {
+			mastery, err := recordReview(ctx, repos, *cfg, &reviews[i])
+			if err != nil {
+				return err
+			}
+			result.Items[i] = models.ReviewBatchItem{Index: i, Review: reviews[i], Mastery: mastery.State}
 		}
-	}()
 
-	if err = tx.QueryRow(`
-		INSERT INTO study_sessions (group_id, study_activity_id)
-		VALUES (?, ?)
-		RETURNING id, created_at
-	`, groupID, activityID).Scan(&session.ID, &session.CreatedAt); err != nil {
-		return nil, fmt.Errorf("failed to create study session: %w", err)
+		result.Session, err = sessionReviewSummary(ctx, tx, sessionID)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	session.GroupID = groupID
-	session.StudyActivityID = activityID
+	return &result, nil
+}
+
+// submissionReview checks one submission of a batch and turns it into a review.
+// A submission without answered_at is answered now. One answered before its
+// word's last review is refused: reviews are replayed in the order they are
+// stored, so it would move the word's stats and schedule backwards.
+func submissionReview(sub models.ReviewSubmission, lastReviews map[int64]time.Time, startedAt, now time.Time) (models.WordReviewItem, error) {
+	review := models.WordReviewItem{WordID: sub.WordID, Grade: sub.Grade, Base: models.Base{CreatedAt: now}}
+	lastReview, ok := lastReviews[sub.WordID]
+	if !ok {
+		return review, fmt.Errorf("word %d is not in the session's group", sub.WordID)
+	}
+	correct, err := gradeCorrect(sub.Correct, sub.Grade)
+	if err != nil {
+		return review, err
+	}
+	review.Correct = correct
 
-	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	if sub.AnsweredAt != nil {
+		at := sub.AnsweredAt.UTC().Truncate(time.Second)
+		switch {
+		case at.Before(startedAt):
+			return review, errors.New("answered_at is before the session started")
+		case at.Before(lastReview):
+			return review, fmt.Errorf("answered_at is before word %d's last review", sub.WordID)
+		case at.After(now.Add(answerClockSkew)):
+			return review, errors.New("answered_at is in the future")
+		}
+		// A client clock a little ahead is trusted up to now, so later
+		// reviews are never stored before this one
+		if at.Before(now) {
+			review.CreatedAt = at
+		}
 	}
+	return review, nil
+}
 
-	return &session, nil
+// gradeCorrect checks a review's correct flag and 0-5 grade, either of which
+// may be missing, and returns whether the review counts as correct
+func gradeCorrect(correct *bool, grade *int) (bool, error) {
+	switch {
+	case correct == nil && grade == nil:
+		return false, errors.New("correct or grade is required")
+	case grade != nil && (*grade < 0 || *grade > 5):
+		return false, errors.New("grade must be between 0 and 5")
+	case grade != nil && correct != nil && *correct != (*grade >= models.PassingGrade):
+		return false, fmt.Errorf("grade %d does not agree with correct %v", *grade, *correct)
+	case correct != nil:
+		return *correct, nil
+	default:
+		return *grade >= models.PassingGrade, nil
+	}
 }
 
-// RecordWordReview records a word review in a study session
-func (s *StudyService) RecordWordReview(sessionID, wordID int64, correct bool) (*models.WordReviewItem, error) {
-	var review models.WordReviewItem
+// groupLastReviews returns the words of a group with the time each was last
+// reviewed, zero for words never reviewed
+func groupLastReviews(ctx context.Context, q database.DBTX, groupID int64) (map[int64]time.Time, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT wg.word_id, MAX(wri.created_at)
+		FROM words_groups wg
+		LEFT JOIN word_review_items wri ON wri.word_id = wg.word_id
+		WHERE wg.group_id = ?
+		GROUP BY wg.word_id
+	`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group words: %w", err)
+	}
+	defer rows.Close()
+
+	words := make(map[int64]time.Time)
+	for rows.Next() {
+		var id int64
+		var last time.Time
+		if err := rows.Scan(&id, database.ScanTime(&last)); err != nil {
+			return nil, fmt.Errorf("failed to scan group word: %w", err)
+		}
+		words[id] = last
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group words: %w", err)
+	}
+	return words, nil
+}
 
-	tx, err := s.db.Begin()
+// sessionReviewSummary returns a study session with its review totals
+func sessionReviewSummary(ctx context.Context, q database.DBTX, sessionID int64) (models.StudySessionReviewSummary, error) {
+	var summary models.StudySessionReviewSummary
+	err := q.QueryRowContext(ctx, `
+		SELECT
+			ss.id, ss.group_id, ss.study_activity_id, ss.created_at, g.name,
+			COUNT(wri.id),
+			COUNT(CASE WHEN wri.correct = 1 THEN 1 END),
+			COUNT(CASE WHEN wri.correct = 0 THEN 1 END),
+			COUNT(DISTINCT wri.word_id),
+			MAX(wri.created_at)
+		FROM study_sessions ss
+		JOIN groups g ON g.id = ss.group_id
+		LEFT JOIN word_review_items wri ON wri.study_session_id = ss.id
+		WHERE ss.id = ?
+		GROUP BY ss.id
+	`, sessionID).Scan(
+		&summary.ID, &summary.GroupID, &summary.StudyActivityID, database.ScanTime(&summary.CreatedAt), &summary.GroupName,
+		&summary.ReviewItemsCount, &summary.CorrectCount, &summary.WrongCount, &summary.WordsReviewed,
+		database.ScanNullTime(&summary.EndTime),
+	)
+	if err == sql.ErrNoRows {
+		return summary, ErrSessionNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return summary, fmt.Errorf("failed to fetch study session summary: %w", err)
 	}
-	defer func() {
+	return summary, nil
+}
+
+// UndoLastReview deletes the most recent review of a study session and
+// recomputes its word's stats, schedule and mastery from the reviews left.
+// The session's queue then serves the word again.
+func (s *StudyService) UndoLastReview(ctx context.Context, sessionID int64) (*models.ReviewChange, error) {
+	var change models.ReviewChange
+	err := s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		if _, err := sessionGroupID(ctx, tx, sessionID); err != nil {
+			return err
+		}
+		if err := settleStreaks(ctx, tx, s.now()); err != nil {
+			return err
+		}
+		var id sql.NullInt64
+		if err := tx.QueryRowContext(ctx,
+			"SELECT MAX(id) FROM word_review_items WHERE study_session_id = ?", sessionID,
+		).Scan(&id); err != nil {
+			return fmt.Errorf("failed to fetch last review: %w", err)
+		}
+		if !id.Valid {
+			return ErrNoReviews
+		}
+		review, err := getReview(ctx, tx, id.Int64)
+		if err != nil {
+			return err
+		}
+		cfg, err := loadSettings(ctx, tx)
 		if err != nil {
-			_ = tx.Rollback()
+			return err
+		}
+		if err = s.checkEditWindow(*cfg, review); err != nil {
+			return err
 		}
-	}()
 
-	if err = tx.QueryRow(`
-		INSERT INTO word_review_items (word_id, study_session_id, correct)
-		VALUES (?, ?, ?)
-		RETURNING id, created_at
-	`, wordID, sessionID, correct).Scan(&review.ID, &review.CreatedAt); err != nil {
-		return nil, fmt.Errorf("failed to create word review: %w", err)
+		if _, err = tx.ExecContext(ctx, "DELETE FROM word_review_items WHERE id = ?", review.ID); err != nil {
+			return fmt.Errorf("failed to delete word review: %w", err)
+		}
+		if change, err = recomputeWord(ctx, repository.NewSQLite(tx), *cfg, review.WordID); err != nil {
+			return err
+		}
+		change.Audit, err = auditReview(ctx, tx, models.ReviewUndo, review, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	review.WordID = wordID
-	review.StudySessionID = sessionID
-	review.Correct = correct
+	return &change, nil
+}
+
+// UpdateReview re-grades a review with correct, a 0-5 grade or both, which
+// must agree as in RecordReviews; with neither the review is flipped. A
+// grade the new correct value still agrees with is kept. The word's stats,
+// schedule and mastery are recomputed, and a wrong answer is matched against
+// the vocabulary again so confusions stay accurate.
+func (s *StudyService) UpdateReview(ctx context.Context, id int64, correct *bool, grade *int) (*models.ReviewChange, error) {
+	var change models.ReviewChange
+	err := s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		before, err := getReview(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := settleStreaks(ctx, tx, s.now()); err != nil {
+			return err
+		}
+		if correct == nil && grade == nil {
+			flipped := !before.Correct
+			correct = &flipped
+		}
+		if grade == nil && correct != nil && before.Grade != nil && (*before.Grade >= models.PassingGrade) == *correct {
+			grade = before.Grade
+		}
+		after := before
+		after.Grade = grade
+		if after.Correct, err = gradeCorrect(correct, grade); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidGrade, err)
+		}
+		if after.Correct == before.Correct && sameGrade(after.Grade, before.Grade) {
+			return ErrReviewUnchanged
+		}
+		cfg, err := loadSettings(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if err = s.checkEditWindow(*cfg, before); err != nil {
+			return err
+		}
+
+		repos := repository.NewSQLite(tx)
+		after.AnswerWordID = nil
+		if !after.Correct && after.Answer != "" {
+			if after.AnswerWordID, err = matchAnswer(ctx, repos.Words, after.Answer, after.WordID); err != nil {
+				return err
+			}
+		}
+		if _, err = tx.ExecContext(ctx, `
+			UPDATE word_review_items SET correct = ?, answer_word_id = ?, grade = ? WHERE id = ?
+		`, after.Correct, after.AnswerWordID, after.Grade, id); err != nil {
+			return fmt.Errorf("failed to update word review: %w", err)
+		}
+		if change, err = recomputeWord(ctx, repos, *cfg, after.WordID); err != nil {
+			return err
+		}
+		change.Audit, err = auditReview(ctx, tx, models.ReviewRegrade, before, &after)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	return &change, nil
+}
+
+// sameGrade reports whether two optional grades are equal
+func sameGrade(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
+	return *a == *b
+}
 
-	return &review, nil
+// GetReviewAudit lists the undone and re-graded reviews of a study session, newest first
+func (s *StudyService) GetReviewAudit(ctx context.Context, sessionID int64) ([]models.ReviewAudit, error) {
+	if _, err := sessionGroupID(ctx, s.read, sessionID); err != nil {
+		return nil, err
+	}
+	rows, err := s.read.QueryContext(ctx, `
+		SELECT id, review_id, study_session_id, word_id, action, before, after, created_at
+		FROM review_audit
+		WHERE study_session_id = ?
+		ORDER BY id DESC
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch review audit: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.ReviewAudit{}
+	for rows.Next() {
+		var e models.ReviewAudit
+		var before string
+		var after sql.NullString
+		if err := rows.Scan(&e.ID, &e.ReviewID, &e.StudySessionID, &e.WordID, &e.Action, &before, &after, database.ScanTime(&e.CreatedAt)); err != nil {
+			return nil, fmt.Errorf("failed to scan review audit: %w", err)
+		}
+		if err := json.Unmarshal([]byte(before), &e.Before); err != nil {
+			return nil, fmt.Errorf("failed to decode review audit: %w", err)
+		}
+		if after.Valid {
+			e.After = &models.WordReviewItem{}
+			if err := json.Unmarshal([]byte(after.String), e.After); err != nil {
+				return nil, fmt.Errorf("failed to decode review audit: %w", err)
+			}
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review audit: %w", err)
+	}
+
+	return entries, nil
+}
+
+// checkEditWindow returns ErrReviewLocked once a review is older than the
+// edit window in the settings
+func (s *StudyService) checkEditWindow(cfg models.Settings, review models.WordReviewItem) error {
+	window := time.Duration(cfg.ReviewEditWindowMinutes) * time.Minute
+	if window <= 0 || s.now().Sub(review.CreatedAt) > window {
+		return ErrReviewLocked
+	}
+	return nil
 }
 
 // GetStudyProgress returns study progress statistics
-func (s *StudyService) GetStudyProgress() (*models.StudyProgress, error) {
+func (s *StudyService) GetStudyProgress(ctx context.Context) (*models.StudyProgress, error) {
 	var progress models.StudyProgress
 
-	err := s.db.QueryRow(`
+	err := s.read.QueryRowContext(ctx, `
 		SELECT 
-			COUNT(DISTINCT word_id) as total_words_studied,
-			(SELECT COUNT(*) FROM words) as total_available_words
-		FROM word_review_items
+			COUNT(DISTINCT wri.word_id) as total_words_studied,
+			(SELECT COUNT(*) FROM words w WHERE `+notSuspended+`) as total_available_words
+		FROM word_review_items wri
+		JOIN words w ON w.id = wri.word_id
+		WHERE `+notSuspended+`
 	`).Scan(&progress.TotalWordsStudied, &progress.TotalAvailableWords)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch study progress: %w", err)
 	}
 
+	if progress.Mastery, err = masteryBreakdown(ctx, s.read, 0); err != nil {
+		return nil, err
+	}
+
+	if progress.Goal, err = todayProgress(ctx, s.read, s.now()); err != nil {
+		return nil, err
+	}
+
 	return &progress, nil
 }
 
+// GetStreak returns the learner's study streaks, counting days in their timezone.
+// Freezes that settling the missed days would spend already count.
+func (s *StudyService) GetStreak(ctx context.Context) (*models.StudyStreak, error) {
+	now := s.now()
+	cfg, err := loadSettings(ctx, s.read)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := learnerLocation(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := activityDays(ctx, s.read, loc)
+	if err != nil {
+		return nil, err
+	}
+	frozen, err := frozenDays(ctx, s.read)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := pendingSettlement(ctx, s.read, now)
+	if err != nil {
+		return nil, err
+	}
+	frozen = append(frozen, plan.Spent...)
+
+	streak := calculateStreak(active, frozen, now.In(loc).Format(dateLayout))
+	streak.Timezone = loc.String()
+	return &streak, nil
+}
+
 // GetQuickStats returns quick study statistics
-func (s *StudyService) GetQuickStats() (*models.QuickStats, error) {
+func (s *StudyService) GetQuickStats(ctx context.Context) (*models.QuickStats, error) {
 	var stats models.QuickStats
 
-	err := s.db.QueryRow(`
+	err := s.read.QueryRowContext(ctx, `
 		WITH stats AS (
 			SELECT 
 				COALESCE(AVG(CASE WHEN correct THEN 1.0 ELSE 0.0 END) * 100, 0) as success_rate,
@@ -121,55 +554,59 @@ func (s *StudyService) GetQuickStats() (*models.QuickStats, error) {
 			SELECT COUNT(DISTINCT group_id) as count
 			FROM study_sessions
 			WHERE created_at >= datetime('now', '-30 days')
-		),
-		streak AS (
-			SELECT COUNT(DISTINCT date(created_at)) as days
-			FROM study_sessions
-			WHERE created_at >= (
-				SELECT MAX(created_at)
-				FROM (
-					SELECT created_at,
-						   julianday(created_at) - julianday(LAG(created_at) OVER (ORDER BY created_at)) as gap
-					FROM study_sessions
-					ORDER BY created_at DESC
-				)
-				WHERE gap > 1 OR gap IS NULL
-			)
 		)
 		SELECT 
 			stats.success_rate,
 			stats.total_sessions,
-			active_groups.count,
-			streak.days
-		FROM stats, active_groups, streak
+			active_groups.count
+		FROM stats, active_groups
 	`).Scan(
 		&stats.SuccessRate,
 		&stats.TotalStudySessions,
 		&stats.TotalActiveGroups,
-		&stats.StudyStreakDays,
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch quick stats: %w", err)
 	}
 
+	streak, err := s.GetStreak(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.StudyStreakDays = streak.Current
+	stats.LongestStreakDays = streak.Longest
+
+	if stats.Goal, err = todayProgress(ctx, s.read, s.now()); err != nil {
+		return nil, err
+	}
+
+	mastery, err := masteryBreakdown(ctx, s.read, 0)
+	if err != nil {
+		return nil, err
+	}
+	stats.WordsLearned = mastery.Mastered
+	stats.WordsInProgress = mastery.Learning + mastery.Reviewing + mastery.Lapsed
+
 	return &stats, nil
 }
 
 // GetLastStudySession returns the most recent study session
-func (s *StudyService) GetLastStudySession() (*models.StudySessionWithDetails, error) {
+func (s *StudyService) GetLastStudySession(ctx context.Context) (*models.StudySessionWithDetails, error) {
 	var session models.StudySessionWithDetails
 
-	err := s.db.QueryRow(`
+	err := s.read.QueryRowContext(ctx, `
 		SELECT 
 			ss.id,
 			ss.group_id,
 			ss.study_activity_id,
 			ss.created_at,
 			g.name as group_name,
+			sa.name as activity_name,
 			COUNT(wri.id) as reviewed_words
 		FROM study_sessions ss
 		JOIN groups g ON ss.group_id = g.id
+		JOIN study_activities sa ON ss.study_activity_id = sa.id
 		LEFT JOIN word_review_items wri ON ss.id = wri.study_session_id
 		WHERE ss.id = (
 			SELECT id FROM study_sessions 
@@ -181,8 +618,9 @@ func (s *StudyService) GetLastStudySession() (*models.StudySessionWithDetails, e
 		&session.ID,
 		&session.GroupID,
 		&session.StudyActivityID,
-		&session.CreatedAt,
+		database.ScanTime(&session.CreatedAt),
 		&session.GroupName,
+		&session.ActivityName,
 		&session.ReviewedWords,
 	)
 
@@ -197,7 +635,7 @@ func (s *StudyService) GetLastStudySession() (*models.StudySessionWithDetails, e
 }
 
 // GetStudySessions returns paginated study sessions
-func (s *StudyService) GetStudySessions(page, perPage int) (*models.PaginatedResponse[models.StudySessionSummary], error) {
+func (s *StudyService) GetStudySessions(ctx context.Context, page, perPage int) (*models.PaginatedResponse[models.StudySessionSummary], error) {
 	if page < 1 {
 		page = 1
 	}
@@ -205,7 +643,7 @@ func (s *StudyService) GetStudySessions(page, perPage int) (*models.PaginatedRes
 		perPage = 100
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.read.QueryContext(ctx, `
         SELECT ss.id, ss.group_id, ss.study_activity_id, ss.created_at, g.name as group_name
         FROM study_sessions ss
         JOIN groups g ON g.id = ss.group_id
@@ -220,7 +658,7 @@ func (s *StudyService) GetStudySessions(page, perPage int) (*models.PaginatedRes
 	var items []models.StudySessionSummary
 	for rows.Next() {
 		var item models.StudySessionSummary
-		if err := rows.Scan(&item.ID, &item.GroupID, &item.StudyActivityID, &item.CreatedAt, &item.GroupName); err != nil {
+		if err := rows.Scan(&item.ID, &item.GroupID, &item.StudyActivityID, database.ScanTime(&item.CreatedAt), &item.GroupName); err != nil {
 			return nil, fmt.Errorf("failed to scan study session: %w", err)
 		}
 		items = append(items, item)
@@ -230,7 +668,7 @@ func (s *StudyService) GetStudySessions(page, perPage int) (*models.PaginatedRes
 	}
 
 	var total int
-	if err := s.db.QueryRow("SELECT COUNT(*) FROM study_sessions").Scan(&total); err != nil {
+	if err := s.read.QueryRowContext(ctx, "SELECT COUNT(*) FROM study_sessions").Scan(&total); err != nil {
 		return nil, fmt.Errorf("failed to count study sessions: %w", err)
 	}
 
@@ -246,14 +684,14 @@ func (s *StudyService) GetStudySessions(page, perPage int) (*models.PaginatedRes
 }
 
 // GetStudySession returns a single study session summary
-func (s *StudyService) GetStudySession(id int64) (*models.StudySessionSummary, error) {
+func (s *StudyService) GetStudySession(ctx context.Context, id int64) (*models.StudySessionSummary, error) {
 	var item models.StudySessionSummary
-	err := s.db.QueryRow(`
+	err := s.read.QueryRowContext(ctx, `
         SELECT ss.id, ss.group_id, ss.study_activity_id, ss.created_at, g.name as group_name
         FROM study_sessions ss
         JOIN groups g ON g.id = ss.group_id
         WHERE ss.id = ?
-    `, id).Scan(&item.ID, &item.GroupID, &item.StudyActivityID, &item.CreatedAt, &item.GroupName)
+    `, id).Scan(&item.ID, &item.GroupID, &item.StudyActivityID, database.ScanTime(&item.CreatedAt), &item.GroupName)
 	if err == sql.ErrNoRows {
 		return nil, err
 	}
@@ -264,7 +702,7 @@ func (s *StudyService) GetStudySession(id int64) (*models.StudySessionSummary, e
 }
 
 // GetStudySessionWords returns words associated with a study session
-func (s *StudyService) GetStudySessionWords(sessionID int64, page, perPage int) (*models.PaginatedResponse[models.Word], error) {
+func (s *StudyService) GetStudySessionWords(ctx context.Context, sessionID int64, page, perPage int) (*models.PaginatedResponse[models.Word], error) {
 	if page < 1 {
 		page = 1
 	}
@@ -272,7 +710,7 @@ func (s *StudyService) GetStudySessionWords(sessionID int64, page, perPage int)
 		perPage = 100
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.read.QueryContext(ctx, `
         SELECT w.id, w.chinese, w.english, w.parts, w.created_at
         FROM words w
         JOIN word_review_items wri ON wri.word_id = w.id
@@ -289,9 +727,13 @@ func (s *StudyService) GetStudySessionWords(sessionID int64, page, perPage int)
 	var items []models.Word
 	for rows.Next() {
 		var w models.Word
-		if err := rows.Scan(&w.ID, &w.Chinese, &w.English, &w.Parts, &w.CreatedAt); err != nil {
+		var parts []byte
+		if err := rows.Scan(&w.ID, &w.Chinese, &w.English, &parts, database.ScanTime(&w.CreatedAt)); err != nil {
 			return nil, fmt.Errorf("failed to scan word: %w", err)
 		}
+		if len(parts) > 0 {
+			w.Parts = parts
+		}
 		items = append(items, w)
 	}
 	if err := rows.Err(); err != nil {
@@ -299,7 +741,7 @@ func (s *StudyService) GetStudySessionWords(sessionID int64, page, perPage int)
 	}
 
 	var total int
-	if err := s.db.QueryRow(`
+	if err := s.read.QueryRowContext(ctx, `
         SELECT COUNT(DISTINCT w.id)
         FROM words w
         JOIN word_review_items wri ON wri.word_id = w.id
@@ -320,7 +762,7 @@ func (s *StudyService) GetStudySessionWords(sessionID int64, page, perPage int)
 }
 
 // GetStudySessionsByActivity returns paginated sessions for a given activity
-func (s *StudyService) GetStudySessionsByActivity(activityID int64, page, perPage int) (*models.PaginatedResponse[models.ActivitySessionListItem], error) {
+func (s *StudyService) GetStudySessionsByActivity(ctx context.Context, activityID int64, page, perPage int) (*models.PaginatedResponse[models.ActivitySessionListItem], error) {
 	if page < 1 {
 		page = 1
 	}
@@ -328,15 +770,17 @@ func (s *StudyService) GetStudySessionsByActivity(activityID int64, page, perPag
 		perPage = 100
 	}
 
-	rows, err := s.db.Query(`
+	rows, err := s.read.QueryContext(ctx, `
         SELECT 
             ss.id,
+            sa.name as activity_name,
             g.name as group_name,
             ss.created_at as start_time,
             MAX(wri.created_at) as end_time,
             COUNT(wri.id) as review_items_count
         FROM study_sessions ss
         JOIN groups g ON g.id = ss.group_id
+        JOIN study_activities sa ON sa.id = ss.study_activity_id
         LEFT JOIN word_review_items wri ON wri.study_session_id = ss.id
         WHERE ss.study_activity_id = ?
         GROUP BY ss.id
@@ -351,18 +795,9 @@ func (s *StudyService) GetStudySessionsByActivity(activityID int64, page, perPag
 	var items []models.ActivitySessionListItem
 	for rows.Next() {
 		var item models.ActivitySessionListItem
-		var startTime sql.NullString
-		var endTime sql.NullString
-		if err := rows.Scan(&item.ID, &item.GroupName, &startTime, &endTime, &item.ReviewItemsCount); err != nil {
+		if err := rows.Scan(&item.ID, &item.ActivityName, &item.GroupName, database.ScanTime(&item.StartTime), database.ScanNullTime(&item.EndTime), &item.ReviewItemsCount); err != nil {
 			return nil, fmt.Errorf("failed to scan activity session: %w", err)
 		}
-		item.StartTime = startTime.String
-		if endTime.Valid {
-			item.EndTime = endTime.String
-		}
-		if details, err := s.GetStudyActivityDetails(activityID); err == nil {
-			item.ActivityName = details.Name
-		}
 		items = append(items, item)
 	}
 	if err := rows.Err(); err != nil {
@@ -370,7 +805,7 @@ func (s *StudyService) GetStudySessionsByActivity(activityID int64, page, perPag
 	}
 
 	var total int
-	if err := s.db.QueryRow("SELECT COUNT(*) FROM study_sessions WHERE study_activity_id = ?", activityID).Scan(&total); err != nil {
+	if err := s.read.QueryRowContext(ctx, "SELECT COUNT(*) FROM study_sessions WHERE study_activity_id = ?", activityID).Scan(&total); err != nil {
 		return nil, fmt.Errorf("failed to count activity sessions: %w", err)
 	}
 
@@ -385,18 +820,22 @@ func (s *StudyService) GetStudySessionsByActivity(activityID int64, page, perPag
 	}, nil
 }
 
-// GetStudyActivityDetails returns details for a study activity from a static catalog
-func (s *StudyService) GetStudyActivityDetails(activityID int64) (*models.StudyActivityDetails, error) {
-	catalog := map[int64]models.StudyActivityDetails{
-		1: {
-			Name:         "Vocabulary Quiz",
-			ThumbnailURL: "https://example.com/thumbnail.jpg",
-			Description:  "Practice your vocabulary with flashcards",
-		},
+// GetStudyActivity returns a study activity from the catalog
+func (s *StudyService) GetStudyActivity(ctx context.Context, activityID int64) (*models.StudyActivity, error) {
+	var a models.StudyActivity
+	var thumbnail, description sql.NullString
+	err := s.read.QueryRowContext(ctx, `
+		SELECT id, name, thumbnail_url, description, created_at
+		FROM study_activities
+		WHERE id = ?
+	`, activityID).Scan(&a.ID, &a.Name, &thumbnail, &description, database.ScanTime(&a.CreatedAt))
+	if err == sql.ErrNoRows {
+		return nil, ErrActivityNotFound
 	}
-	if v, ok := catalog[activityID]; ok {
-		v.ID = activityID
-		return &v, nil
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch study activity: %w", err)
 	}
-	return nil, sql.ErrNoRows
+	a.ThumbnailURL = thumbnail.String
+	a.Description = description.String
+	return &a, nil
 }
@@ -1,33 +1,56 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
+	"lang-portal/internal/database"
 	"lang-portal/internal/database/query"
 	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
 )
 
 // WordService handles word-related business logic
 type WordService struct {
-	db *sql.DB
+	db database.DBTX
+	tx *database.TxRunner
+	// read serves read-only queries; NewServices points it at the reader pool
+	read database.DBTX
+	now  func() time.Time
 }
 
 // NewWordService creates a new WordService
 func NewWordService(db *sql.DB) *WordService {
-	return &WordService{db: db}
+	return &WordService{db: database.Handle(db), tx: database.NewTxRunner(db), read: database.Handle(db), now: time.Now}
 }
 
-// GetWords returns a paginated list of words with their stats
-func (s *WordService) GetWords(page, perPage int) (*models.PaginatedResponse[models.WordWithStats], error) {
+// GetWords returns a paginated list of words with their stats, optionally
+// limited to words flagged as leeches
+func (s *WordService) GetWords(ctx context.Context, page, perPage int, leechOnly bool) (*models.PaginatedResponse[models.WordWithStats], error) {
 	q := query.New("SELECT w.*, " +
-		"(SELECT COUNT(*) FROM word_review_items wri WHERE wri.word_id = w.id AND wri.correct = 1) as correct_count, " +
-		"(SELECT COUNT(*) FROM word_review_items wri WHERE wri.word_id = w.id AND wri.correct = 0) as wrong_count " +
-		"FROM words w")
+		"COALESCE(wst.correct_count, 0) as correct_count, " +
+		"COALESCE(wst.wrong_count, 0) as wrong_count, " +
+		"wst.last_reviewed_at, " +
+		"COALESCE(wst.streak, 0) as streak, " +
+		"COALESCE(wf.leech, 0) as leech, " +
+		"COALESCE(wf.suspended, 0) as suspended " +
+		"FROM words w " +
+		"LEFT JOIN word_stats wst ON wst.word_id = w.id " +
+		"LEFT JOIN word_flags wf ON wf.word_id = w.id")
+	if leechOnly {
+		q.Where("wf.leech = 1")
+	}
+
+	total, err := q.ExecuteCount(ctx, s.read)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count words: %w", err)
+	}
 
 	q.OrderBy("w.created_at DESC").Paginate(page, perPage)
 
-	rows, err := q.Execute(s.db)
+	rows, err := q.Execute(ctx, s.read)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch words: %w", err)
 	}
@@ -44,9 +67,13 @@ func (s *WordService) GetWords(page, perPage int) (*models.PaginatedResponse[mod
 			&w.Chinese,
 			&w.English,
 			&parts,
-			&w.CreatedAt,
+			database.ScanTime(&w.CreatedAt),
 			&correctCount,
 			&wrongCount,
+			database.ScanNullTime(&w.Stats.LastReviewedAt),
+			&w.Stats.Streak,
+			&w.Stats.Leech,
+			&w.Stats.Suspended,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan word: %w", err)
@@ -55,10 +82,8 @@ func (s *WordService) GetWords(page, perPage int) (*models.PaginatedResponse[mod
 		// Preserve raw JSON for parts without unmarshalling
 		w.Parts = parts
 
-		w.Stats = models.WordStats{
-			CorrectCount: correctCount,
-			WrongCount:   wrongCount,
-		}
+		w.Stats.CorrectCount = correctCount
+		w.Stats.WrongCount = wrongCount
 
 		words = append(words, w)
 	}
@@ -67,11 +92,6 @@ func (s *WordService) GetWords(page, perPage int) (*models.PaginatedResponse[mod
 		return nil, fmt.Errorf("error iterating words: %w", err)
 	}
 
-	var total int
-	if err := s.db.QueryRow("SELECT COUNT(*) FROM words").Scan(&total); err != nil {
-		return nil, fmt.Errorf("failed to count words: %w", err)
-	}
-
 	return &models.PaginatedResponse[models.WordWithStats]{
 		Items: words,
 		Pagination: models.Pagination{
@@ -84,14 +104,21 @@ func (s *WordService) GetWords(page, perPage int) (*models.PaginatedResponse[mod
 }
 
 // GetWordByID returns a single word with its stats
-func (s *WordService) GetWordByID(id int64) (*models.WordWithStats, error) {
+func (s *WordService) GetWordByID(ctx context.Context, id int64) (*models.WordWithStats, error) {
 	q := query.New("SELECT w.*, " +
-		"(SELECT COUNT(*) FROM word_review_items wri WHERE wri.word_id = w.id AND wri.correct = 1) as correct_count, " +
-		"(SELECT COUNT(*) FROM word_review_items wri WHERE wri.word_id = w.id AND wri.correct = 0) as wrong_count " +
-		"FROM words w")
+		"COALESCE(wst.correct_count, 0) as correct_count, " +
+		"COALESCE(wst.wrong_count, 0) as wrong_count, " +
+		"wst.last_reviewed_at, " +
+		"COALESCE(wst.streak, 0) as streak, " +
+		"COALESCE((SELECT wm.state FROM word_mastery wm WHERE wm.word_id = w.id), '" + models.MasteryNew + "') as mastery, " +
+		"COALESCE(wf.leech, 0) as leech, " +
+		"COALESCE(wf.suspended, 0) as suspended " +
+		"FROM words w " +
+		"LEFT JOIN word_stats wst ON wst.word_id = w.id " +
+		"LEFT JOIN word_flags wf ON wf.word_id = w.id")
 	q.Where("w.id = ?", id)
 
-	rows, err := q.Execute(s.db)
+	rows, err := q.Execute(ctx, s.read)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch word: %w", err)
 	}
@@ -104,15 +131,21 @@ func (s *WordService) GetWordByID(id int64) (*models.WordWithStats, error) {
 	var w models.WordWithStats
 	var parts []byte
 	var correctCount, wrongCount int
+	var mastery string
 
 	err = rows.Scan(
 		&w.ID,
 		&w.Chinese,
 		&w.English,
 		&parts,
-		&w.CreatedAt,
+		database.ScanTime(&w.CreatedAt),
 		&correctCount,
 		&wrongCount,
+		database.ScanNullTime(&w.Stats.LastReviewedAt),
+		&w.Stats.Streak,
+		&mastery,
+		&w.Stats.Leech,
+		&w.Stats.Suspended,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan word: %w", err)
@@ -121,17 +154,155 @@ func (s *WordService) GetWordByID(id int64) (*models.WordWithStats, error) {
 	// Preserve raw JSON for parts
 	w.Parts = parts
 
-	w.Stats = models.WordStats{
-		CorrectCount: correctCount,
-		WrongCount:   wrongCount,
-	}
+	w.Stats.CorrectCount = correctCount
+	w.Stats.WrongCount = wrongCount
+	w.Stats.Mastery = mastery
 
 	return &w, nil
 }
 
+// GetWordHistory returns every review of a word in order, with the schedule
+// each review produced and the word's predicted forgetting curve. Schedules
+// are replayed from the history so every review shows its interval.
+func (s *WordService) GetWordHistory(ctx context.Context, id int64) (*models.WordHistory, error) {
+	history := models.WordHistory{
+		WordID:    id,
+		Reviews:   []models.WordHistoryEntry{},
+		Retention: []models.RetentionPoint{},
+	}
+	err := s.read.QueryRowContext(ctx, "SELECT chinese, english FROM words WHERE id = ?", id).Scan(&history.Chinese, &history.English)
+	if err == sql.ErrNoRows {
+		return nil, ErrWordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch word: %w", err)
+	}
+
+	rows, err := s.read.QueryContext(ctx, `
+		SELECT
+			wri.id,
+			wri.study_session_id,
+			ss.study_activity_id,
+			ss.group_id,
+			g.name,
+			sa.name,
+			wri.created_at,
+			wri.correct,
+			wri.answer,
+			wri.response_ms,
+			wri.grade
+		FROM word_review_items wri
+		JOIN study_sessions ss ON ss.id = wri.study_session_id
+		JOIN groups g ON g.id = ss.group_id
+		JOIN study_activities sa ON sa.id = ss.study_activity_id
+		WHERE wri.word_id = ?
+		ORDER BY wri.id ASC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch word history: %w", err)
+	}
+	defer rows.Close()
+
+	sched := models.WordSchedule{WordID: id, Ease: defaultEase}
+	for rows.Next() {
+		var e models.WordHistoryEntry
+		var answer sql.NullString
+		var responseMs sql.NullInt64
+		if err := rows.Scan(
+			&e.ReviewID,
+			&e.StudySessionID,
+			&e.ActivityID,
+			&e.GroupID,
+			&e.GroupName,
+			&e.ActivityName,
+			database.ScanTime(&e.CreatedAt),
+			&e.Correct,
+			&answer,
+			&responseMs,
+			&e.Grade,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan word history: %w", err)
+		}
+		e.Answer = answer.String
+		if responseMs.Valid {
+			ms := int(responseMs.Int64)
+			e.ResponseMs = &ms
+		}
+
+		if len(history.Reviews) > 0 {
+			elapsed := e.CreatedAt.Sub(sched.LastReviewedAt).Hours() / 24
+			recall := retention(elapsed, sched.IntervalDays)
+			e.ElapsedDays = &elapsed
+			e.RetentionAtReview = &recall
+		}
+
+		sched = nextSchedule(sched, e.Correct, e.Grade, e.CreatedAt)
+		e.IntervalDays = sched.IntervalDays
+		e.Ease = sched.Ease
+		e.DueAt = sched.DueAt
+		history.Reviews = append(history.Reviews, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word history: %w", err)
+	}
+
+	if len(history.Reviews) > 0 {
+		current := retention(s.now().Sub(sched.LastReviewedAt).Hours()/24, sched.IntervalDays)
+		history.CurrentRetention = &current
+		history.Retention = retentionCurve(sched.IntervalDays)
+	}
+
+	return &history, nil
+}
+
+// SuspendWord excludes a word from study until it is unsuspended
+func (s *WordService) SuspendWord(ctx context.Context, id int64) (*models.WordFlags, error) {
+	return s.setSuspended(ctx, id, true)
+}
+
+// UnsuspendWord returns a suspended word to study. A leech keeps its flag.
+func (s *WordService) UnsuspendWord(ctx context.Context, id int64) (*models.WordFlags, error) {
+	return s.setSuspended(ctx, id, false)
+}
+
+func (s *WordService) setSuspended(ctx context.Context, id int64, suspended bool) (*models.WordFlags, error) {
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM words WHERE id = ?)", id).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check word: %w", err)
+	}
+	if !exists {
+		return nil, ErrWordNotFound
+	}
+	progress := repository.NewSQLite(s.db).Progress
+	if err := progress.SetSuspended(ctx, id, suspended); err != nil {
+		return nil, err
+	}
+	return progress.Flags(ctx, id)
+}
+
+// RebuildStats recomputes every word's review totals from the review history
+// and returns the number of words that have been reviewed
+func (s *WordService) RebuildStats(ctx context.Context) (int, error) {
+	var count int
+	err := s.tx.InTx(ctx, func(ctx context.Context, tx database.DBTX) error {
+		if err := rebuildWordStats(ctx, tx); err != nil {
+			return err
+		}
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM word_stats").Scan(&count); err != nil {
+			return fmt.Errorf("failed to count word stats: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 // GetGroupsForWord returns the groups that contain a given word
-func (s *WordService) GetGroupsForWord(wordID int64) ([]models.Group, error) {
-	rows, err := s.db.Query(`
+func (s *WordService) GetGroupsForWord(ctx context.Context, wordID int64) ([]models.Group, error) {
+	rows, err := s.read.QueryContext(ctx, `
         SELECT g.id, g.name, g.created_at
         FROM groups g
         JOIN words_groups wg ON wg.group_id = g.id
@@ -146,7 +317,7 @@ func (s *WordService) GetGroupsForWord(wordID int64) ([]models.Group, error) {
 	var groups []models.Group
 	for rows.Next() {
 		var g models.Group
-		if err := rows.Scan(&g.ID, &g.Name, &g.CreatedAt); err != nil {
+		if err := rows.Scan(&g.ID, &g.Name, database.ScanTime(&g.CreatedAt)); err != nil {
 			return nil, fmt.Errorf("failed to scan group: %w", err)
 		}
 		groups = append(groups, g)
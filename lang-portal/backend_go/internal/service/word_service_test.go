@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+func TestWordHistoryReplaysSchedule(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 1, 0)
+
+	day := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	study := NewStudyService(db)
+	for _, r := range []struct {
+		days    int
+		correct bool
+	}{{0, true}, {1, true}, {5, false}} {
+		review := models.WordReviewItem{WordID: 1, StudySessionID: 1, Correct: r.correct, Base: models.Base{CreatedAt: day.AddDate(0, 0, r.days)}}
+		if _, err := study.RecordWordReview(ctx, review); err != nil {
+			t.Fatalf("RecordWordReview: %v", err)
+		}
+	}
+
+	words := NewWordService(db)
+	history, err := words.GetWordHistory(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetWordHistory: %v", err)
+	}
+	if len(history.Reviews) != 3 || history.Chinese != "w1" || history.Reviews[0].GroupName != "Bench" {
+		t.Fatalf("history = %+v, want three reviews of w1 in Bench", history)
+	}
+
+	first, second, lapse := history.Reviews[0], history.Reviews[1], history.Reviews[2]
+	if first.ElapsedDays != nil || first.RetentionAtReview != nil {
+		t.Errorf("first review has elapsed %v and retention %v, want neither", first.ElapsedDays, first.RetentionAtReview)
+	}
+	if second.ElapsedDays == nil || *second.ElapsedDays != 1 || second.RetentionAtReview == nil {
+		t.Errorf("second review elapsed %v, want 1 day with a retention", second.ElapsedDays)
+	}
+	if second.IntervalDays <= first.IntervalDays || lapse.IntervalDays >= second.IntervalDays || lapse.Ease >= second.Ease {
+		t.Errorf("intervals %.1f, %.1f, %.1f with ease %.2f, %.2f; want growth then a shorter interval and lower ease after the lapse",
+			first.IntervalDays, second.IntervalDays, lapse.IntervalDays, second.Ease, lapse.Ease)
+	}
+
+	// The replay ends where recording the reviews left the stored schedule
+	sched, err := loadSchedule(ctx, repository.NewSQLite(db).Progress, 1)
+	if err != nil {
+		t.Fatalf("loadSchedule: %v", err)
+	}
+	if lapse.IntervalDays != sched.IntervalDays || lapse.Ease != sched.Ease || !lapse.DueAt.Equal(sched.DueAt) {
+		t.Errorf("replayed schedule = %.2f days, ease %.2f, due %v; stored %.2f days, ease %.2f, due %v",
+			lapse.IntervalDays, lapse.Ease, lapse.DueAt, sched.IntervalDays, sched.Ease, sched.DueAt)
+	}
+
+	if _, err := words.GetWordHistory(ctx, 99); !errors.Is(err, ErrWordNotFound) {
+		t.Errorf("GetWordHistory(missing) error = %v, want %v", err, ErrWordNotFound)
+	}
+}
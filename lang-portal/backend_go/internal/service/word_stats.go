@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"lang-portal/internal/database"
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+// loadStats returns a word's stored totals, or zero ones if it was never reviewed
+func loadStats(ctx context.Context, p repository.ProgressRepository, wordID int64) (models.WordStats, error) {
+	stats, err := p.Stats(ctx, wordID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.WordStats{}, nil
+	}
+	if err != nil {
+		return models.WordStats{}, err
+	}
+	return *stats, nil
+}
+
+// countReview adds a review to a word's running totals
+func countReview(stats models.WordStats, r models.WordReviewItem) models.WordStats {
+	if r.Correct {
+		stats.CorrectCount++
+		stats.Streak++
+	} else {
+		stats.WrongCount++
+		stats.Streak = 0
+	}
+	reviewedAt := r.CreatedAt
+	stats.LastReviewedAt = &reviewedAt
+	return stats
+}
+
+// replayReviews folds a word's reviews, oldest first, into the totals
+// word_stats keeps and the schedule word_schedules keeps
+func replayReviews(wordID int64, reviews []models.WordReviewItem) (models.WordStats, models.WordSchedule) {
+	var stats models.WordStats
+	sched := models.WordSchedule{WordID: wordID, Ease: defaultEase}
+	for _, r := range reviews {
+		stats = countReview(stats, r)
+		sched = nextSchedule(sched, r.Correct, r.Grade, r.CreatedAt)
+	}
+	return stats, sched
+}
+
+// rebuildWordStats recomputes every word's totals from its review history
+func rebuildWordStats(ctx context.Context, q database.DBTX) error {
+	if _, err := q.ExecContext(ctx, "DELETE FROM word_stats"); err != nil {
+		return fmt.Errorf("failed to clear word stats: %w", err)
+	}
+	if _, err := q.ExecContext(ctx, `
+		WITH last_wrong AS (
+			SELECT word_id, MAX(id) AS id FROM word_review_items WHERE correct = 0 GROUP BY word_id
+		)
+		INSERT INTO word_stats (word_id, correct_count, wrong_count, last_reviewed_at, streak)
+		SELECT
+			wri.word_id,
+			COUNT(CASE WHEN wri.correct = 1 THEN 1 END),
+			COUNT(CASE WHEN wri.correct = 0 THEN 1 END),
+			MAX(wri.created_at),
+			COUNT(CASE WHEN wri.correct = 1 AND wri.id > COALESCE(lw.id, 0) THEN 1 END)
+		FROM word_review_items wri
+		LEFT JOIN last_wrong lw ON lw.word_id = wri.word_id
+		GROUP BY wri.word_id
+	`); err != nil {
+		return fmt.Errorf("failed to rebuild word stats: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"lang-portal/internal/models"
+	"lang-portal/internal/repository"
+)
+
+const (
+	benchWords   = 1000
+	benchReviews = 100000
+)
+
+// seedReviews inserts words in one group and spreads reviews across them,
+// roughly one in four wrong
+func seedReviews(tb testing.TB, db *sql.DB, words, reviews int) {
+	tb.Helper()
+	ctx := tb.Context()
+	stmts := []string{
+		"INSERT INTO groups (id, name) VALUES (1, 'Bench')",
+		"INSERT INTO study_sessions (id, group_id, study_activity_id) VALUES (1, 1, 1)",
+		`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < ?)
+		 INSERT INTO words (chinese, english, parts, created_at)
+		 SELECT 'w' || i, 'word ' || i, '{}', datetime('2025-01-01', '+' || i || ' minutes') FROM n`,
+		"INSERT INTO words_groups (word_id, group_id) SELECT id, 1 FROM words",
+		`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < ?)
+		 INSERT INTO word_review_items (word_id, study_session_id, correct, created_at)
+		 SELECT (i * 7919) % ? + 1, 1, (i * 31) % 4 != 0, datetime('2025-02-01', '+' || i || ' seconds') FROM n`,
+	}
+	args := [][]interface{}{nil, nil, {words}, nil, {reviews, words}}
+	if reviews == 0 {
+		stmts, args = stmts[:len(stmts)-1], args[:len(args)-1]
+	}
+	for i, stmt := range stmts {
+		if _, err := db.Exec(stmt, args[i]...); err != nil {
+			tb.Fatalf("failed to seed: %v", err)
+		}
+	}
+	if err := rebuildWordStats(ctx, db); err != nil {
+		tb.Fatalf("failed to rebuild word stats: %v", err)
+	}
+}
+
+func TestWordStatsTrackReviews(t *testing.T) {
+	db := newTestDB(t)
+	ctx := t.Context()
+	seedReviews(t, db, 3, 0)
+
+	answers := []struct {
+		wordID  int64
+		correct bool
+	}{
+		{1, true}, {1, false}, {1, true}, {1, true}, {2, false}, {2, true}, {1, false}, {2, true},
+	}
+	for _, a := range answers {
+		review := models.WordReviewItem{WordID: a.wordID, StudySessionID: 1, Correct: a.correct}
+		if err := insertReview(ctx, db, &review); err != nil {
+			t.Fatalf("insertReview: %v", err)
+		}
+	}
+
+	want := map[int64]models.WordStats{
+		1: {CorrectCount: 3, WrongCount: 2, Streak: 0},
+		2: {CorrectCount: 2, WrongCount: 1, Streak: 2},
+	}
+	check := func(stage string) {
+		t.Helper()
+		for wordID, w := range want {
+			word, err := NewWordService(db).GetWordByID(ctx, wordID)
+			if err != nil {
+				t.Fatalf("%s: GetWordByID(%d): %v", stage, wordID, err)
+			}
+			got := word.Stats
+			if got.CorrectCount != w.CorrectCount || got.WrongCount != w.WrongCount || got.Streak != w.Streak {
+				t.Errorf("%s: word %d stats = %d/%d streak %d, want %d/%d streak %d", stage, wordID,
+					got.CorrectCount, got.WrongCount, got.Streak, w.CorrectCount, w.WrongCount, w.Streak)
+			}
+			if got.LastReviewedAt == nil {
+				t.Errorf("%s: word %d has no last review time", stage, wordID)
+			}
+		}
+		word, err := NewWordService(db).GetWordByID(ctx, 3)
+		if err != nil {
+			t.Fatalf("%s: GetWordByID(3): %v", stage, err)
+		}
+		if word.Stats.CorrectCount != 0 || word.Stats.WrongCount != 0 || word.Stats.LastReviewedAt != nil {
+			t.Errorf("%s: unreviewed word has stats %+v", stage, word.Stats)
+		}
+	}
+
+	check("incremental")
+	count, err := NewWordService(db).RebuildStats(ctx)
+	if err != nil {
+		t.Fatalf("RebuildStats: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("RebuildStats rebuilt %d words, want 2", count)
+	}
+	check("rebuilt")
+}
+
+func TestRecordReviewInMemory(t *testing.T) {
+	repos := repository.NewMemory()
+	ctx := t.Context()
+
+	word := models.Word{Chinese: "猫", English: "cat"}
+	dog := models.Word{Chinese: "狗", English: "dog"}
+	group := models.Group{Name: "Animals"}
+	for _, w := range []*models.Word{&word, &dog} {
+		if err := repos.Words.Create(ctx, w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repos.Groups.Create(ctx, &group); err != nil {
+		t.Fatal(err)
+	}
+	session := models.StudySession{GroupID: group.ID, StudyActivityID: 1}
+	if err := repos.Sessions.Create(ctx, &session); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := models.DefaultSettings()
+	cfg.LeechThreshold = 1
+	start := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	for i, correct := range []bool{true, true, false, true} {
+		review := models.WordReviewItem{WordID: word.ID, StudySessionID: session.ID, Correct: correct,
+			Base: models.Base{CreatedAt: start.AddDate(0, 0, i)}}
+		if !correct {
+			review.Answer = " Dog "
+		}
+		if _, err := recordReview(ctx, repos, cfg, &review); err != nil {
+			t.Fatalf("recordReview: %v", err)
+		}
+	}
+
+	last := start.AddDate(0, 0, 3)
+	stats, err := loadStats(ctx, repos.Progress, word.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.CorrectCount != 3 || stats.WrongCount != 1 || stats.Streak != 1 || stats.LastReviewedAt == nil || !stats.LastReviewedAt.Equal(last) {
+		t.Errorf("stats = %+v, want 3/1 streak 1 last reviewed %v", stats, last)
+	}
+	// The lapse resets the interval and costs ease; the next correct answer starts over at one day
+	sched, err := loadSchedule(ctx, repos.Progress, word.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sched.Repetitions != 1 || sched.IntervalDays != 1 || sched.Ease != 2.6 || !sched.DueAt.Equal(last.AddDate(0, 0, 1)) {
+		t.Errorf("schedule = %+v, want 1 repetition, 1 day, ease 2.6, due %v", sched, last.AddDate(0, 0, 1))
+	}
+	// Wrong after reaching reviewing is a lapse, which makes the word a leech at threshold 1
+	mastery, err := loadMastery(ctx, repos.Progress, word.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mastery.State != models.MasteryLapsed || mastery.Lapses != 1 {
+		t.Errorf("mastery = %+v, want lapsed with 1 lapse", mastery)
+	}
+	if flags, err := repos.Progress.Flags(ctx, word.ID); err != nil || !flags.Leech || !flags.Suspended {
+		t.Errorf("flags = %+v, %v; want a suspended leech", flags, err)
+	}
+	reviews, err := repos.Reviews.ListByWord(ctx, word.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reviews[2]; got.Answer != "Dog" || got.AnswerWordID == nil || *got.AnswerWordID != dog.ID {
+		t.Errorf("wrong review = %+v, want answer Dog matched to word %d", got, dog.ID)
+	}
+
+	// Replaying the history arrives where the reviews left the word
+	change, err := recomputeWord(ctx, repos, cfg, word.ID)
+	if err != nil {
+		t.Fatalf("recomputeWord: %v", err)
+	}
+	if change.Stats.CorrectCount != 3 || change.Stats.WrongCount != 1 || change.Stats.Streak != 1 || !change.Stats.Leech {
+		t.Errorf("replayed stats = %+v, want 3/1 streak 1, leech", change.Stats)
+	}
+	if change.Schedule == nil || *change.Schedule != sched {
+		t.Errorf("replayed schedule = %+v, want %+v", change.Schedule, sched)
+	}
+	if change.Mastery != mastery {
+		t.Errorf("replayed mastery = %+v, want %+v", change.Mastery, mastery)
+	}
+
+	// A word without reviews has no progress
+	change, err = recomputeWord(ctx, repos, cfg, dog.ID)
+	if err != nil {
+		t.Fatalf("recomputeWord: %v", err)
+	}
+	if change.Schedule != nil || change.Stats.CorrectCount != 0 || change.Mastery.State != models.MasteryNew {
+		t.Errorf("unreviewed word change = %+v, want no progress", change)
+	}
+	if _, err := repos.Progress.Stats(ctx, dog.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Stats(unreviewed) error = %v, want %v", err, repository.ErrNotFound)
+	}
+}
+
+// BenchmarkGetWords lists a page of 100 words at 100k reviews using word_stats
+func BenchmarkGetWords(b *testing.B) {
+	db := newTestDB(b)
+	ctx := b.Context()
+	seedReviews(b, db, benchWords, benchReviews)
+	words := NewWordService(db)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := words.GetWords(ctx, 1, 100, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGetWordsCorrelated lists the same page with the per-word COUNT(*)
+// subqueries word_stats replaced, for comparison
+func BenchmarkGetWordsCorrelated(b *testing.B) {
+	db := newTestDB(b)
+	seedReviews(b, db, benchWords, benchReviews)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, err := db.Query(`
+			SELECT w.id,
+				(SELECT COUNT(*) FROM word_review_items wri WHERE wri.word_id = w.id AND wri.correct = 1),
+				(SELECT COUNT(*) FROM word_review_items wri WHERE wri.word_id = w.id AND wri.correct = 0)
+			FROM words w
+			ORDER BY w.created_at DESC
+			LIMIT 100
+		`)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for rows.Next() {
+			var id int64
+			var correct, wrong int
+			if err := rows.Scan(&id, &correct, &wrong); err != nil {
+				b.Fatal(err)
+			}
+		}
+		rows.Close()
+	}
+}
+
+// BenchmarkRecordReview measures the cost of storing a review, including
+// keeping word_stats up to date, at 100k reviews
+func BenchmarkRecordReview(b *testing.B) {
+	db := newTestDB(b)
+	ctx := b.Context()
+	seedReviews(b, db, benchWords, benchReviews)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		review := models.WordReviewItem{WordID: int64(i%benchWords + 1), StudySessionID: 1, Correct: i%4 != 0}
+		if err := insertReview(ctx, db, &review); err != nil {
+			b.Fatal(err)
+		}
+	}
+}